@@ -1,39 +1,259 @@
 package billing
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/doddeeph/billing-engine/internal/batch"
+	"github.com/doddeeph/billing-engine/internal/collection"
 	"github.com/doddeeph/billing-engine/internal/config"
 	"github.com/doddeeph/billing-engine/internal/db"
+	"github.com/doddeeph/billing-engine/internal/gateway"
 	"github.com/doddeeph/billing-engine/internal/handler"
+	"github.com/doddeeph/billing-engine/internal/loanstatement"
+	"github.com/doddeeph/billing-engine/internal/lock"
+	"github.com/doddeeph/billing-engine/internal/notification"
+	"github.com/doddeeph/billing-engine/internal/outbox"
 	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/doddeeph/billing-engine/internal/scheduler"
 	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/webhook"
 	"github.com/gin-gonic/gin"
 )
 
 type BillingApp struct {
-	AppPort        string
-	BillingHandler *handler.BillingHandler
-	PaymentHandler *handler.PaymentHandler
+	AppPort            string
+	Scheduler          *scheduler.Scheduler
+	OutboxRelay        *outbox.Relay
+	WebhookDispatcher  *webhook.Dispatcher
+	BatchWorkers       []*batch.Worker
+	BillingHandler     *handler.BillingHandler
+	PaymentHandler     *handler.PaymentHandler
+	FreezeHandler      *handler.FreezeHandler
+	WriteOffHandler    *handler.WriteOffHandler
+	PolicyHandler      *handler.DelinquencyPolicyHandler
+	DelinquencyHandler *handler.DelinquencyHandler
+	JobHandler         *handler.JobHandler
+	WebhookHandler     *handler.WebhookHandler
+	GatewayHandler     *handler.GatewayHandler
+	VAHandler          *handler.VirtualAccountHandler
+	QRISHandler        *handler.QRISHandler
+	ReconHandler       *handler.ReconciliationHandler
+	BatchHandler       *handler.PaymentBatchHandler
+	LoanImportHandler  *handler.LoanImportHandler
+	CustomerHandler    *handler.CustomerHandler
+	ReminderHandler    *handler.ReminderHandler
+	DunningHandler     *handler.DunningHandler
+	CollectionHandler  *handler.CollectionHandler
+	PromiseHandler     *handler.PaymentPromiseHandler
+	StatementHandler   *handler.LoanStatementHandler
+	CalendarHandler    *handler.RepaymentCalendarHandler
 }
 
 func NewBillingApp() *BillingApp {
 	appConfig := config.LoadConfig()
 	db := db.InitDB(&appConfig.DB)
 
+	outboxRepo := repository.NewOutboxRepository(db)
+	outboxSvc := service.NewOutboxService(outboxRepo)
+
 	billingRepo := repository.NewBillingRepository(db)
-	billingSvc := service.NewBillingService(billingRepo)
+	policyRepo := repository.NewDelinquencyPolicyRepository(db)
+	policySvc := service.NewDelinquencyPolicyService(policyRepo, billingRepo, &appConfig.Billing, &appConfig.PaymentPromise)
+	policyHandler := handler.NewDelinquencyPolicyHandler(policySvc)
+
+	vaRepo := repository.NewVirtualAccountRepository(db)
+	vaSvc := service.NewVirtualAccountService(vaRepo, billingRepo, &appConfig.VirtualAccount)
+	vaHandler := handler.NewVirtualAccountHandler(vaSvc)
+
+	qrisRepo := repository.NewQRISPaymentRepository(db)
+	qrisSvc := service.NewQRISService(qrisRepo, billingRepo, &appConfig.QRIS)
+	qrisHandler := handler.NewQRISHandler(qrisSvc)
+
+	customerRepo := repository.NewCustomerRepository(db)
+	exposureRepo := repository.NewCustomerExposureRepository(db)
+	billingSvc := service.NewBillingService(billingRepo, customerRepo, exposureRepo, policySvc, outboxSvc, vaSvc, &appConfig.Billing)
 	billingHandler := handler.NewBillingHandler(billingSvc)
+	customerSvc := service.NewCustomerService(customerRepo, billingRepo, exposureRepo, billingSvc, policySvc, &appConfig.Customer)
+	customerHandler := handler.NewCustomerHandler(customerSvc)
+
+	collectionRepo := repository.NewCollectionRepository(db)
+	collectionSvc := service.NewCollectionService(collectionRepo, billingRepo, customerRepo, &appConfig.Billing, &appConfig.Collections)
+	collectionHandler := handler.NewCollectionHandler(collectionSvc)
+
+	recoveryRepo := repository.NewRecoveryRepository(db)
+	writeOffSvc := service.NewWriteOffService(billingRepo, recoveryRepo, collectionSvc, &appConfig.Billing)
+	writeOffHandler := handler.NewWriteOffHandler(writeOffSvc)
+
+	delinquencyHistoryRepo := repository.NewDelinquencyHistoryRepository(db)
+	delinquencySvc := service.NewDelinquencyService(delinquencyHistoryRepo, billingRepo, policySvc, outboxSvc, &appConfig.Billing)
+	delinquencyHandler := handler.NewDelinquencyHandler(delinquencySvc)
+
+	promiseRepo := repository.NewPaymentPromiseRepository(db)
+	promiseSvc := service.NewPaymentPromiseService(promiseRepo, billingRepo, &appConfig.PaymentPromise)
+	promiseHandler := handler.NewPaymentPromiseHandler(promiseSvc)
 
 	paymentRepo := repository.NewPaymentRepository(db)
-	paymentSvc := service.NewPaymentService(paymentRepo, billingSvc)
+	paymentSvc := service.NewPaymentService(paymentRepo, billingSvc, writeOffSvc, delinquencySvc, promiseSvc, outboxSvc)
 	paymentHandler := handler.NewPaymentHandler(paymentSvc)
 
+	var providers []gateway.Provider
+	if appConfig.Gateway.MockEnabled {
+		if appConfig.Gateway.MockSecret == "" {
+			log.Fatal("GATEWAY_MOCK_SECRET is required when the mock gateway is enabled")
+		}
+		providers = append(providers, gateway.NewMockProvider(appConfig.Gateway.MockSecret))
+	}
+	gatewayCallbackRepo := repository.NewGatewayCallbackRepository(db)
+	gatewaySvc := service.NewGatewayService(gatewayCallbackRepo, paymentSvc, vaSvc, qrisSvc, gateway.NewRegistry(providers...))
+	gatewayHandler := handler.NewGatewayHandler(gatewaySvc)
+
+	statementRepo := repository.NewStatementRepository(db)
+	reconSvc := service.NewReconciliationService(statementRepo, gatewayCallbackRepo, paymentSvc, vaSvc, qrisSvc, &appConfig.Reconciliation)
+	reconHandler := handler.NewReconciliationHandler(reconSvc)
+
+	freezeRepo := repository.NewFreezeRepository(db)
+	freezeSvc := service.NewFreezeService(freezeRepo, paymentRepo, billingRepo)
+	freezeHandler := handler.NewFreezeHandler(freezeSvc)
+
+	sweepSvc := service.NewSweepService(billingRepo, paymentRepo, delinquencySvc, promiseSvc, &appConfig.Billing)
+
+	channels, err := notification.NewChannels(&appConfig.Notification)
+	if err != nil {
+		log.Fatalf("Failed to create notification channels: %v", err)
+	}
+	renderer, err := notification.NewRenderer(appConfig.Customer.Languages, appConfig.Customer.DefaultLanguage, appConfig.Notification.TemplateDir)
+	if err != nil {
+		log.Fatalf("Failed to load notification templates: %v", err)
+	}
+	reminderRepo := repository.NewReminderRepository(db)
+	reminderSvc := service.NewReminderService(reminderRepo, customerRepo, vaRepo, channels, renderer, &appConfig.Reminder, &appConfig.Customer)
+	reminderHandler := handler.NewReminderHandler(reminderSvc)
+
+	dunningRepo := repository.NewDunningRepository(db)
+	dunningSvc := service.NewDunningService(dunningRepo, billingRepo, customerRepo, channels, renderer, &appConfig.Billing, &appConfig.Customer, &appConfig.PaymentPromise, &appConfig.Dunning)
+	dunningHandler := handler.NewDunningHandler(dunningSvc)
+
+	statementRenderer, err := loanstatement.NewRenderer(loanstatement.Brand{
+		Name:    appConfig.LoanStatement.BrandName,
+		Address: appConfig.LoanStatement.BrandAddress,
+		Phone:   appConfig.LoanStatement.BrandPhone,
+		Email:   appConfig.LoanStatement.BrandEmail,
+		Website: appConfig.LoanStatement.BrandWebsite,
+		Color:   appConfig.LoanStatement.BrandColor,
+		Footer:  appConfig.LoanStatement.Footer,
+	}, appConfig.LoanStatement.TemplatePath)
+	if err != nil {
+		log.Fatalf("Failed to load statement template: %v", err)
+	}
+	loanStatementSvc := service.NewLoanStatementService(billingRepo, recoveryRepo, statementRenderer, &appConfig.Customer)
+	loanStatementHandler := handler.NewLoanStatementHandler(loanStatementSvc)
+	calendarSvc := service.NewRepaymentCalendarService(billingRepo, customerRepo, &appConfig.Customer, &appConfig.Calendar)
+	calendarHandler := handler.NewRepaymentCalendarHandler(calendarSvc)
+
+	locker := lock.NewAdvisoryLocker(db)
+	jobRunRepo := repository.NewJobRunRepository(db)
+	jobScheduler, err := scheduler.NewScheduler(jobRunRepo, locker, &appConfig.Scheduler)
+	if err != nil {
+		log.Fatalf("Failed to create scheduler: %v", err)
+	}
+	err = jobScheduler.Register(config.JobEndOfDaySweep, func(ctx context.Context) (scheduler.JobResult, error) {
+		processed, failed, err := sweepSvc.RunEndOfDaySweep(ctx)
+		return scheduler.JobResult{Processed: processed, Failed: failed}, err
+	})
+	if err != nil {
+		log.Fatalf("Failed to register job: %v", err)
+	}
+	err = jobScheduler.Register(config.JobPaymentReminders, func(ctx context.Context) (scheduler.JobResult, error) {
+		sent, failed, err := reminderSvc.RunReminders(ctx)
+		return scheduler.JobResult{Processed: sent, Failed: failed}, err
+	})
+	if err != nil {
+		log.Fatalf("Failed to register job: %v", err)
+	}
+	err = jobScheduler.Register(config.JobDunning, func(ctx context.Context) (scheduler.JobResult, error) {
+		processed, failed, err := dunningSvc.RunDunning(ctx, time.Now())
+		return scheduler.JobResult{Processed: processed, Failed: failed}, err
+	})
+	if err != nil {
+		log.Fatalf("Failed to register job: %v", err)
+	}
+	err = jobScheduler.Register(config.JobCollectionSync, func(ctx context.Context) (scheduler.JobResult, error) {
+		processed, failed, err := collectionSvc.SyncCases(ctx)
+		return scheduler.JobResult{Processed: processed, Failed: failed}, err
+	})
+	if err != nil {
+		log.Fatalf("Failed to register job: %v", err)
+	}
+	jobHandler := handler.NewJobHandler(jobScheduler, jobRunRepo)
+
+	webhookRepo := repository.NewWebhookRepository(db)
+	webhookSvc := service.NewWebhookService(webhookRepo)
+	webhookHandler := handler.NewWebhookHandler(webhookSvc)
+	webhookDispatcher := webhook.NewDispatcher(webhookRepo, locker, &appConfig.Webhook)
+
+	publisher, err := outbox.NewPublisher(appConfig.Outbox.Publishers, map[string]outbox.Publisher{
+		outbox.PublisherLog:      outbox.NewLogPublisher(),
+		webhook.PublisherName:    webhook.NewPublisher(webhookSvc),
+		collection.PublisherName: collection.NewPublisher(collectionSvc),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create outbox publisher: %v", err)
+	}
+	outboxRelay := outbox.NewRelay(outboxRepo, publisher, locker, &appConfig.Outbox)
+
+	paymentBatchRepo := repository.NewPaymentBatchRepository(db)
+	paymentBatchSvc := service.NewPaymentBatchService(paymentBatchRepo, paymentSvc, &appConfig.PaymentBatch)
+	batchHandler := handler.NewPaymentBatchHandler(paymentBatchSvc)
+	loanImportRepo := repository.NewLoanImportRepository(db)
+	loanImportSvc := service.NewLoanImportService(loanImportRepo, billingRepo, billingSvc)
+	loanImportHandler := handler.NewLoanImportHandler(loanImportSvc)
+	var batchWorkers []*batch.Worker
+	if appConfig.PaymentBatch.WorkerEnabled {
+		batchWorkers = append(batchWorkers, batch.NewWorker("payment-batch-worker", appConfig.PaymentBatch.PollInterval, locker, paymentBatchSvc))
+	} else {
+		log.Println("Payment batch worker is disabled.")
+	}
+	if appConfig.LoanImport.WorkerEnabled {
+		batchWorkers = append(batchWorkers, batch.NewWorker("loan-import-worker", appConfig.LoanImport.PollInterval, locker, loanImportSvc))
+	} else {
+		log.Println("Loan import worker is disabled.")
+	}
+
 	return &BillingApp{
-		AppPort:        fmt.Sprintf(":%s", appConfig.AppPort),
-		BillingHandler: billingHandler,
-		PaymentHandler: paymentHandler,
+		AppPort:            fmt.Sprintf(":%s", appConfig.AppPort),
+		Scheduler:          jobScheduler,
+		OutboxRelay:        outboxRelay,
+		WebhookDispatcher:  webhookDispatcher,
+		BatchWorkers:       batchWorkers,
+		BillingHandler:     billingHandler,
+		PaymentHandler:     paymentHandler,
+		FreezeHandler:      freezeHandler,
+		WriteOffHandler:    writeOffHandler,
+		PolicyHandler:      policyHandler,
+		DelinquencyHandler: delinquencyHandler,
+		JobHandler:         jobHandler,
+		WebhookHandler:     webhookHandler,
+		GatewayHandler:     gatewayHandler,
+		VAHandler:          vaHandler,
+		QRISHandler:        qrisHandler,
+		ReconHandler:       reconHandler,
+		BatchHandler:       batchHandler,
+		LoanImportHandler:  loanImportHandler,
+		CustomerHandler:    customerHandler,
+		ReminderHandler:    reminderHandler,
+		DunningHandler:     dunningHandler,
+		CollectionHandler:  collectionHandler,
+		PromiseHandler:     promiseHandler,
+		StatementHandler:   loanStatementHandler,
+		CalendarHandler:    calendarHandler,
 	}
 }
 
@@ -43,9 +263,55 @@ func (app *BillingApp) Start() {
 	apiV1 := r.Group("/api/v1")
 	app.BillingHandler.RegisterRoutes(apiV1)
 	app.PaymentHandler.RegisterRoutes(apiV1)
+	app.FreezeHandler.RegisterRoutes(apiV1)
+	app.WriteOffHandler.RegisterRoutes(apiV1)
+	app.PolicyHandler.RegisterRoutes(apiV1)
+	app.DelinquencyHandler.RegisterRoutes(apiV1)
+	app.JobHandler.RegisterRoutes(apiV1)
+	app.WebhookHandler.RegisterRoutes(apiV1)
+	app.GatewayHandler.RegisterRoutes(apiV1)
+	app.VAHandler.RegisterRoutes(apiV1)
+	app.QRISHandler.RegisterRoutes(apiV1)
+	app.ReconHandler.RegisterRoutes(apiV1)
+	app.BatchHandler.RegisterRoutes(apiV1)
+	app.LoanImportHandler.RegisterRoutes(apiV1)
+	app.CustomerHandler.RegisterRoutes(apiV1)
+	app.ReminderHandler.RegisterRoutes(apiV1)
+	app.DunningHandler.RegisterRoutes(apiV1)
+	app.CollectionHandler.RegisterRoutes(apiV1)
+	app.PromiseHandler.RegisterRoutes(apiV1)
+	app.StatementHandler.RegisterRoutes(apiV1)
+	app.CalendarHandler.RegisterRoutes(apiV1)
 
-	if err := r.Run(app.AppPort); err != nil {
-		log.Fatalf("Failed to run Billing Engine: %v", err)
-	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{Addr: app.AppPort, Handler: r}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to run Billing Engine: %v", err)
+		}
+	}()
 	log.Printf("Billing Engine started at %s", app.AppPort)
+	app.Scheduler.Start()
+	app.OutboxRelay.Start()
+	app.WebhookDispatcher.Start()
+	for _, worker := range app.BatchWorkers {
+		worker.Start()
+	}
+
+	<-ctx.Done()
+	log.Println("Shutting down Billing Engine...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to shut down HTTP server: %v", err)
+	}
+	app.Scheduler.Stop()
+	app.OutboxRelay.Stop()
+	app.WebhookDispatcher.Stop()
+	for _, worker := range app.BatchWorkers {
+		worker.Stop()
+	}
+	log.Println("Billing Engine stopped.")
 }
@@ -0,0 +1,82 @@
+package batch
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/lock"
+)
+
+type Processor interface {
+	ProcessPending(ctx context.Context) (int, error)
+}
+
+type Worker struct {
+	name       string
+	interval   time.Duration
+	locker     lock.Locker
+	processors []Processor
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewWorker runs the processors every interval under the advisory lock with the given name.
+func NewWorker(name string, interval time.Duration, locker lock.Locker, processors ...Processor) *Worker {
+	return &Worker{name: name, interval: interval, locker: locker, processors: processors}
+}
+
+func (w *Worker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.poll(ctx)
+			}
+		}
+	}()
+	log.Printf("Worker %s started, polling every %s", w.name, w.interval)
+}
+
+func (w *Worker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	w.wg.Wait()
+	log.Printf("Worker %s stopped.", w.name)
+}
+
+func (w *Worker) poll(ctx context.Context) {
+	workerLock, acquired, err := w.locker.TryLock(ctx, w.name)
+	if err != nil {
+		log.Printf("Failed to acquire %s lock: %v", w.name, err)
+		return
+	}
+	if !acquired {
+		return
+	}
+	defer func() {
+		if err := workerLock.Release(context.Background()); err != nil {
+			log.Printf("Failed to release %s lock: %v", w.name, err)
+		}
+	}()
+	for _, processor := range w.processors {
+		processed, err := processor.ProcessPending(ctx)
+		if err != nil {
+			log.Printf("Worker %s failed to process pending work: %v", w.name, err)
+		}
+		if processed > 0 {
+			log.Printf("Worker %s processed %d items", w.name, processed)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package collection
+
+import (
+	"context"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/outbox"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/pkg/events"
+)
+
+const PublisherName = "collections"
+
+type publisher struct {
+	svc service.CollectionService
+}
+
+func NewPublisher(svc service.CollectionService) outbox.Publisher {
+	return &publisher{svc: svc}
+}
+
+func (p *publisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	switch event.EventType {
+	case events.TypeBillingDelinquent, events.TypeBillingCured, events.TypeBillingClosed:
+		_, err := p.svc.SyncCase(ctx, event.BillingID, event.OccurredAt)
+		return err
+	}
+	return nil
+}
@@ -1,8 +1,13 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -16,9 +21,180 @@ type DBConfig struct {
 	Password string
 }
 
+type BillingConfig struct {
+	AgingBuckets           []int
+	MissedPaymentMax       int
+	LateFeeAmount          int
+	EvaluationBatchSize    int
+	WriteOffMinDaysPastDue int
+}
+
+const (
+	JobEndOfDaySweep    = "eod-sweep"
+	JobPaymentReminders = "payment-reminders"
+	JobDunning          = "dunning"
+	JobCollectionSync   = "collection-sync"
+)
+
+type SchedulerConfig struct {
+	Enabled  bool
+	Timezone string
+	Jobs     map[string]string
+}
+
+type OutboxConfig struct {
+	RelayEnabled bool
+	Publishers   []string
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+type WebhookConfig struct {
+	DispatcherEnabled bool
+	PollInterval      time.Duration
+	BatchSize         int
+	MaxAttempts       int
+	BackoffBase       time.Duration
+	BackoffMax        time.Duration
+	Timeout           time.Duration
+}
+
+type GatewayConfig struct {
+	MockEnabled bool
+	MockSecret  string
+}
+
+type VirtualAccountBank struct {
+	Code   string
+	Prefix string
+}
+
+type VirtualAccountConfig struct {
+	Banks  []VirtualAccountBank
+	Length int
+}
+
+type QRISConfig struct {
+	AcquirerDomain string
+	MerchantPAN    string
+	MerchantID     string
+	NMID           string
+	Criteria       string
+	MCC            string
+	MerchantName   string
+	MerchantCity   string
+	PostalCode     string
+}
+
+type ReconciliationConfig struct {
+	MatchWindowDays int
+}
+
+type PaymentBatchConfig struct {
+	WorkerEnabled bool
+	PollInterval  time.Duration
+	SyncLimit     int
+	LeaseDuration time.Duration
+}
+
+type LoanImportConfig struct {
+	WorkerEnabled bool
+	PollInterval  time.Duration
+}
+
+type CustomerConfig struct {
+	DefaultLanguage string
+	DefaultTimezone string
+	Languages       []string
+}
+
+type NotificationConfig struct {
+	SMSAdapter            string
+	EmailAdapter          string
+	WhatsappAdapter       string
+	FilePath              string
+	TemplateDir           string
+	Timeout               time.Duration
+	SMSURL                string
+	SMSAPIKey             string
+	SMSSender             string
+	SMTPHost              string
+	SMTPPort              int
+	SMTPUsername          string
+	SMTPPassword          string
+	SMTPFrom              string
+	WhatsappURL           string
+	WhatsappPhoneNumberID string
+	WhatsappToken         string
+}
+
+type ReminderConfig struct {
+	Offsets       []int
+	Channels      []string
+	SendHour      int
+	GracePeriod   time.Duration
+	MaxAttempts   int
+	RetryInterval time.Duration
+	BatchSize     int
+}
+
+type DunningStep struct {
+	DaysPastDue int
+	Action      string
+}
+
+type DunningConfig struct {
+	DefaultSteps []DunningStep
+}
+
+type CollectionsConfig struct {
+	PriorityAmountUnit int
+}
+
+type LoanStatementConfig struct {
+	TemplatePath string
+	BrandName    string
+	BrandAddress string
+	BrandPhone   string
+	BrandEmail   string
+	BrandWebsite string
+	BrandColor   string
+	Footer       string
+}
+
+type CalendarConfig struct {
+	Name         string
+	UIDDomain    string
+	RefreshHours int
+}
+
+type PaymentPromiseConfig struct {
+	MaxDays             int
+	SuppressDunning     bool
+	SuppressDelinquency bool
+}
+
 type AppConfig struct {
-	DB      DBConfig
-	AppPort string
+	DB             DBConfig
+	Billing        BillingConfig
+	Scheduler      SchedulerConfig
+	Outbox         OutboxConfig
+	Webhook        WebhookConfig
+	Gateway        GatewayConfig
+	VirtualAccount VirtualAccountConfig
+	QRIS           QRISConfig
+	Reconciliation ReconciliationConfig
+	PaymentBatch   PaymentBatchConfig
+	LoanImport     LoanImportConfig
+	Customer       CustomerConfig
+	Notification   NotificationConfig
+	Reminder       ReminderConfig
+	Dunning        DunningConfig
+	Collections    CollectionsConfig
+	PaymentPromise PaymentPromiseConfig
+	LoanStatement  LoanStatementConfig
+	Calendar       CalendarConfig
+	AppPort        string
 }
 
 func LoadConfig() *AppConfig {
@@ -34,6 +210,129 @@ func LoadConfig() *AppConfig {
 			User:     getEnv("DB_USER", "postgres"),
 			Password: getEnv("DB_PASSWORD", ""),
 		},
+		Billing: BillingConfig{
+			AgingBuckets:           getEnvIntSlice("AGING_BUCKETS", []int{30, 60, 90}),
+			MissedPaymentMax:       getEnvInt("MISSED_PAYMENT_MAX", 2),
+			LateFeeAmount:          getEnvInt("LATE_FEE_AMOUNT", 0),
+			EvaluationBatchSize:    getEnvInt("EVALUATION_BATCH_SIZE", 100),
+			WriteOffMinDaysPastDue: getEnvInt("WRITE_OFF_MIN_DAYS_PAST_DUE", 90),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:  getEnv("SCHEDULER_ENABLED", "true") == "true",
+			Timezone: getEnv("SCHEDULER_TIMEZONE", "Asia/Jakarta"),
+			Jobs: map[string]string{
+				JobEndOfDaySweep:    getEnv("EOD_SWEEP_SCHEDULE", "55 23 * * *"),
+				JobPaymentReminders: getEnv("REMINDER_SCHEDULE", "*/15 * * * *"),
+				JobDunning:          getEnv("DUNNING_SCHEDULE", "0 10 * * *"),
+				JobCollectionSync:   getEnv("COLLECTIONS_SYNC_SCHEDULE", "0 * * * *"),
+			},
+		},
+		Outbox: OutboxConfig{
+			RelayEnabled: getEnv("OUTBOX_RELAY_ENABLED", "true") == "true",
+			Publishers:   getEnvSlice("OUTBOX_PUBLISHERS", []string{"log", "webhook", "collections"}),
+			PollInterval: getEnvDuration("OUTBOX_POLL_INTERVAL", 5*time.Second),
+			BatchSize:    getEnvInt("OUTBOX_BATCH_SIZE", 100),
+		},
+		Webhook: WebhookConfig{
+			DispatcherEnabled: getEnv("WEBHOOK_DISPATCHER_ENABLED", "true") == "true",
+			PollInterval:      getEnvDuration("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+			BatchSize:         getEnvInt("WEBHOOK_BATCH_SIZE", 100),
+			MaxAttempts:       getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			BackoffBase:       getEnvDuration("WEBHOOK_BACKOFF_BASE", 30*time.Second),
+			BackoffMax:        getEnvDuration("WEBHOOK_BACKOFF_MAX", 6*time.Hour),
+			Timeout:           getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		},
+		Gateway: GatewayConfig{
+			MockEnabled: getEnv("GATEWAY_MOCK_ENABLED", "false") == "true",
+			MockSecret:  getEnv("GATEWAY_MOCK_SECRET", ""),
+		},
+		VirtualAccount: VirtualAccountConfig{
+			Banks:  getEnvVirtualAccountBanks("VIRTUAL_ACCOUNT_BANKS"),
+			Length: getEnvInt("VIRTUAL_ACCOUNT_LENGTH", 16),
+		},
+		QRIS: QRISConfig{
+			AcquirerDomain: getEnv("QRIS_ACQUIRER_DOMAIN", ""),
+			MerchantPAN:    getEnv("QRIS_MERCHANT_PAN", ""),
+			MerchantID:     getEnv("QRIS_MERCHANT_ID", ""),
+			NMID:           getEnv("QRIS_NMID", ""),
+			Criteria:       getEnv("QRIS_CRITERIA", "UMI"),
+			MCC:            getEnv("QRIS_MCC", "6012"),
+			MerchantName:   getEnv("QRIS_MERCHANT_NAME", "Billing Engine"),
+			MerchantCity:   getEnv("QRIS_MERCHANT_CITY", "Jakarta"),
+			PostalCode:     getEnv("QRIS_POSTAL_CODE", ""),
+		},
+		Reconciliation: ReconciliationConfig{
+			MatchWindowDays: getEnvInt("RECONCILIATION_MATCH_WINDOW_DAYS", 3),
+		},
+		PaymentBatch: PaymentBatchConfig{
+			WorkerEnabled: getEnv("PAYMENT_BATCH_WORKER_ENABLED", "true") == "true",
+			PollInterval:  getEnvDuration("PAYMENT_BATCH_POLL_INTERVAL", 5*time.Second),
+			SyncLimit:     getEnvInt("PAYMENT_BATCH_SYNC_LIMIT", 200),
+			LeaseDuration: getEnvDuration("PAYMENT_BATCH_LEASE_DURATION", 5*time.Minute),
+		},
+		LoanImport: LoanImportConfig{
+			WorkerEnabled: getEnv("LOAN_IMPORT_WORKER_ENABLED", "true") == "true",
+			PollInterval:  getEnvDuration("LOAN_IMPORT_POLL_INTERVAL", 5*time.Second),
+		},
+		Customer: CustomerConfig{
+			DefaultLanguage: getEnv("CUSTOMER_DEFAULT_LANGUAGE", "id"),
+			DefaultTimezone: getEnv("CUSTOMER_DEFAULT_TIMEZONE", "Asia/Jakarta"),
+			Languages:       getEnvSlice("CUSTOMER_LANGUAGES", []string{"id", "en"}),
+		},
+		Notification: NotificationConfig{
+			SMSAdapter:            getEnv("NOTIFICATION_SMS_ADAPTER", "log"),
+			EmailAdapter:          getEnv("NOTIFICATION_EMAIL_ADAPTER", "log"),
+			WhatsappAdapter:       getEnv("NOTIFICATION_WHATSAPP_ADAPTER", "log"),
+			FilePath:              getEnv("NOTIFICATION_FILE_PATH", "notifications.log"),
+			TemplateDir:           getEnv("NOTIFICATION_TEMPLATE_DIR", ""),
+			Timeout:               getEnvDuration("NOTIFICATION_TIMEOUT", 10*time.Second),
+			SMSURL:                getEnv("NOTIFICATION_SMS_URL", ""),
+			SMSAPIKey:             getEnv("NOTIFICATION_SMS_API_KEY", ""),
+			SMSSender:             getEnv("NOTIFICATION_SMS_SENDER", ""),
+			SMTPHost:              getEnv("NOTIFICATION_SMTP_HOST", ""),
+			SMTPPort:              getEnvInt("NOTIFICATION_SMTP_PORT", 587),
+			SMTPUsername:          getEnv("NOTIFICATION_SMTP_USERNAME", ""),
+			SMTPPassword:          getEnv("NOTIFICATION_SMTP_PASSWORD", ""),
+			SMTPFrom:              getEnv("NOTIFICATION_SMTP_FROM", ""),
+			WhatsappURL:           getEnv("NOTIFICATION_WHATSAPP_URL", "https://graph.facebook.com/v20.0"),
+			WhatsappPhoneNumberID: getEnv("NOTIFICATION_WHATSAPP_PHONE_NUMBER_ID", ""),
+			WhatsappToken:         getEnv("NOTIFICATION_WHATSAPP_TOKEN", ""),
+		},
+		Reminder: ReminderConfig{
+			Offsets:       getEnvSignedIntSlice("REMINDER_OFFSETS", []int{-2, 0, 1}),
+			Channels:      getEnvSlice("REMINDER_CHANNELS", []string{"SMS", "EMAIL", "WHATSAPP"}),
+			SendHour:      getEnvInt("REMINDER_SEND_HOUR", 9),
+			GracePeriod:   getEnvDuration("REMINDER_GRACE_PERIOD", 12*time.Hour),
+			MaxAttempts:   getEnvInt("REMINDER_MAX_ATTEMPTS", 3),
+			RetryInterval: getEnvDuration("REMINDER_RETRY_INTERVAL", 30*time.Minute),
+			BatchSize:     getEnvInt("REMINDER_BATCH_SIZE", 200),
+		},
+		Dunning: DunningConfig{
+			DefaultSteps: getEnvDunningSteps("DUNNING_DEFAULT_STEPS", []DunningStep{{DaysPastDue: 1, Action: "SMS"}, {DaysPastDue: 7, Action: "CALL"}, {DaysPastDue: 30, Action: "LETTER"}}),
+		},
+		Collections: CollectionsConfig{
+			PriorityAmountUnit: getEnvInt("COLLECTIONS_PRIORITY_AMOUNT_UNIT", 100000),
+		},
+		PaymentPromise: PaymentPromiseConfig{
+			MaxDays:             getEnvInt("PAYMENT_PROMISE_MAX_DAYS", 14),
+			SuppressDunning:     getEnv("PAYMENT_PROMISE_SUPPRESS_DUNNING", "true") == "true",
+			SuppressDelinquency: getEnv("PAYMENT_PROMISE_SUPPRESS_DELINQUENCY", "true") == "true",
+		},
+		LoanStatement: LoanStatementConfig{
+			TemplatePath: getEnv("LOAN_STATEMENT_TEMPLATE_PATH", ""),
+			BrandName:    getEnv("LOAN_STATEMENT_BRAND_NAME", "Billing Engine"),
+			BrandAddress: getEnv("LOAN_STATEMENT_BRAND_ADDRESS", ""),
+			BrandPhone:   getEnv("LOAN_STATEMENT_BRAND_PHONE", ""),
+			BrandEmail:   getEnv("LOAN_STATEMENT_BRAND_EMAIL", ""),
+			BrandWebsite: getEnv("LOAN_STATEMENT_BRAND_WEBSITE", ""),
+			BrandColor:   getEnv("LOAN_STATEMENT_BRAND_COLOR", "#1F4E79"),
+			Footer:       getEnv("LOAN_STATEMENT_FOOTER", ""),
+		},
+		Calendar: CalendarConfig{
+			Name:         getEnv("CALENDAR_NAME", "Loan Repayments"),
+			UIDDomain:    getEnv("CALENDAR_UID_DOMAIN", "billing-engine"),
+			RefreshHours: getEnvInt("CALENDAR_REFRESH_HOURS", 6),
+		},
 		AppPort: getEnv("APP_PORT", "8080"),
 	}
 }
@@ -45,3 +344,128 @@ func getEnv(key, defaultVal string) string {
 	}
 	return val
 }
+
+func getEnvInt(key string, defaultVal int) int {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		log.Printf("Invalid %s value %q, using default %d", key, val, defaultVal)
+		return defaultVal
+	}
+	return i
+}
+
+func getEnvDuration(key string, defaultVal time.Duration) time.Duration {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		log.Printf("Invalid %s value %q, using default %s", key, val, defaultVal)
+		return defaultVal
+	}
+	return d
+}
+
+func getEnvSlice(key string, defaultVal []string) []string {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	var vals []string
+	for _, part := range strings.Split(val, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			vals = append(vals, part)
+		}
+	}
+	return vals
+}
+
+func getEnvVirtualAccountBanks(key string) []VirtualAccountBank {
+	var banks []VirtualAccountBank
+	for _, part := range getEnvSlice(key, nil) {
+		code, prefix, ok := strings.Cut(part, ":")
+		if _, err := strconv.ParseUint(prefix, 10, 64); !ok || code == "" || err != nil {
+			log.Printf("Invalid %s entry %q, expected BANK:PREFIX", key, part)
+			continue
+		}
+		banks = append(banks, VirtualAccountBank{Code: code, Prefix: prefix})
+	}
+	if err := validateVirtualAccountBanks(banks); err != nil {
+		log.Fatalf("Invalid %s: %v", key, err)
+	}
+	return banks
+}
+
+func validateVirtualAccountBanks(banks []VirtualAccountBank) error {
+	for i, bank := range banks {
+		for _, other := range banks[:i] {
+			if bank.Code == other.Code {
+				return fmt.Errorf("bank %s is listed more than once", bank.Code)
+			}
+			if strings.HasPrefix(bank.Prefix, other.Prefix) || strings.HasPrefix(other.Prefix, bank.Prefix) {
+				return fmt.Errorf("prefix %s of %s overlaps prefix %s of %s", bank.Prefix, bank.Code, other.Prefix, other.Code)
+			}
+		}
+	}
+	return nil
+}
+
+func getEnvDunningSteps(key string, defaultVal []DunningStep) []DunningStep {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	var steps []DunningStep
+	for _, part := range getEnvSlice(key, nil) {
+		days, action, ok := strings.Cut(part, ":")
+		dpd, err := strconv.Atoi(days)
+		if !ok || action == "" || err != nil || dpd <= 0 {
+			log.Printf("Invalid %s value %q, using default %v", key, val, defaultVal)
+			return defaultVal
+		}
+		steps = append(steps, DunningStep{DaysPastDue: dpd, Action: strings.ToUpper(action)})
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i].DaysPastDue < steps[j].DaysPastDue })
+	return steps
+}
+
+func getEnvIntSlice(key string, defaultVal []int) []int {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	var ints []int
+	for _, part := range strings.Split(val, ",") {
+		i, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || i <= 0 {
+			log.Printf("Invalid %s value %q, using default %v", key, val, defaultVal)
+			return defaultVal
+		}
+		ints = append(ints, i)
+	}
+	sort.Ints(ints)
+	return ints
+}
+
+func getEnvSignedIntSlice(key string, defaultVal []int) []int {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	var ints []int
+	for _, part := range strings.Split(val, ",") {
+		i, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			log.Printf("Invalid %s value %q, using default %v", key, val, defaultVal)
+			return defaultVal
+		}
+		ints = append(ints, i)
+	}
+	sort.Ints(ints)
+	return ints
+}
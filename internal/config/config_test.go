@@ -0,0 +1,14 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateVirtualAccountBanks(t *testing.T) {
+	assert.NoError(t, validateVirtualAccountBanks([]VirtualAccountBank{{Code: "BCA", Prefix: "39358"}, {Code: "BNI", Prefix: "8808"}}))
+	assert.Error(t, validateVirtualAccountBanks([]VirtualAccountBank{{Code: "BCA", Prefix: "39358"}, {Code: "BCA", Prefix: "8808"}}))
+	assert.Error(t, validateVirtualAccountBanks([]VirtualAccountBank{{Code: "BCA", Prefix: "39358"}, {Code: "BNI", Prefix: "39358"}}))
+	assert.Error(t, validateVirtualAccountBanks([]VirtualAccountBank{{Code: "BCA", Prefix: "3935"}, {Code: "BNI", Prefix: "39358"}}))
+}
@@ -18,6 +18,7 @@ type DBTestConfig struct {
 
 type AppTestConfig struct {
 	DB      DBTestConfig
+	Billing BillingConfig
 	AppPort string
 }
 
@@ -35,6 +36,13 @@ func LoadTestConfig() *AppTestConfig {
 			User:     getEnv("DB_USER", "postgres"),
 			Password: getEnv("DB_PASSWORD", ""),
 		},
+		Billing: BillingConfig{
+			AgingBuckets:           getEnvIntSlice("AGING_BUCKETS", []int{30, 60, 90}),
+			MissedPaymentMax:       getEnvInt("MISSED_PAYMENT_MAX", 2),
+			LateFeeAmount:          getEnvInt("LATE_FEE_AMOUNT", 0),
+			EvaluationBatchSize:    getEnvInt("EVALUATION_BATCH_SIZE", 100),
+			WriteOffMinDaysPastDue: getEnvInt("WRITE_OFF_MIN_DAYS_PAST_DUE", 90),
+		},
 		AppPort: getEnv("APP_PORT", "8080"),
 	}
 }
@@ -20,6 +20,6 @@ func InitDB(cfg *config.DBConfig) *gorm.DB {
 		log.Fatalf("Failed to open to DB: %v", err)
 	}
 	log.Println("Connected to database.")
-	db.AutoMigrate(&model.Billing{}, &model.Payment{})
+	db.AutoMigrate(&model.Billing{}, &model.Payment{}, &model.BillingFreeze{}, &model.Recovery{}, &model.DelinquencyPolicy{}, &model.DelinquencyHistory{}, &model.JobRun{}, &model.OutboxEvent{}, &model.WebhookSubscription{}, &model.WebhookDelivery{}, &model.GatewayCallback{}, &model.VirtualAccount{}, &model.QRISPayment{}, &model.StatementImport{}, &model.StatementLine{}, &model.PaymentBatch{}, &model.PaymentBatchRow{}, &model.LoanImport{}, &model.LoanImportRow{}, &model.CustomerExposureLimit{}, &model.Customer{}, &model.Reminder{}, &model.DunningStrategy{}, &model.DunningStep{}, &model.DunningLog{}, &model.CollectionAgent{}, &model.CollectionCase{}, &model.ContactAttempt{}, &model.PaymentPromise{})
 	return db
 }
@@ -1,11 +1,19 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+)
+
 type CreateBillingDTO struct {
-	CustomerID   uint `json:"customerId"`
-	LoanID       uint `json:"loanId"`
-	LoanAmount   int  `json:"loanAmount"`
-	LoanInterest int  `json:"loanInterest"`
-	LoanWeeks    int  `json:"loanWeeks"`
+	CustomerID          uint   `json:"customerId"`
+	LoanID              uint   `json:"loanId"`
+	LoanAmount          int    `json:"loanAmount"`
+	LoanInterest        int    `json:"loanInterest"`
+	LoanWeeks           int    `json:"loanWeeks"`
+	ProductCode         string `json:"productCode,omitempty"`
+	DelinquencyPolicyID *uint  `json:"delinquencyPolicyId,omitempty"`
 }
 
 type CreateBillingRequest struct {
@@ -13,8 +21,9 @@ type CreateBillingRequest struct {
 }
 
 type CreateBillingResponse struct {
-	BillingID   uint `json:"billingId"`
-	Outstanding int  `json:"outstanding"`
+	BillingID       uint                   `json:"billingId"`
+	Outstanding     int                    `json:"outstanding"`
+	VirtualAccounts []model.VirtualAccount `json:"virtualAccounts"`
 	CreateBillingDTO
 }
 
@@ -24,12 +33,70 @@ type BaseResponse struct {
 	LoanID     uint `json:"loanId"`
 }
 
+type Aging struct {
+	DaysPastDue int    `json:"daysPastDue"`
+	AgingBucket string `json:"agingBucket"`
+}
+
 type OutstandingResponse struct {
 	BaseResponse
+	Aging
 	Outstanding int `json:"outstanding"`
 }
 
+type Delinquency struct {
+	IsDelinquent bool   `json:"isDelinquent"`
+	PolicyCode   string `json:"policyCode"`
+	Rule         string `json:"rule,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+	Frozen       bool   `json:"frozen,omitempty"`
+	Promised     bool   `json:"promised,omitempty"`
+}
+
 type DelinquentResponse struct {
 	BaseResponse
-	IsDelinquent bool `json:"isDelinquent"`
+	Aging
+	Delinquency
+}
+
+type AgingBucketSummary struct {
+	Bucket      string `json:"bucket"`
+	Count       int    `json:"count"`
+	Outstanding int    `json:"outstanding"`
+}
+
+type PortfolioAgingResponse struct {
+	AsOf             time.Time            `json:"asOf"`
+	TotalCount       int                  `json:"totalCount"`
+	TotalOutstanding int                  `json:"totalOutstanding"`
+	Buckets          []AgingBucketSummary `json:"buckets"`
+}
+
+type BillingListQuery struct {
+	CustomerID        uint
+	Status            string
+	DelinquencyStatus string
+	CreatedFrom       string
+	CreatedTo         string
+	Cursor            string
+	Limit             int
+}
+
+type BillingSummary struct {
+	BillingID         uint      `json:"billingId"`
+	CustomerID        uint      `json:"customerId"`
+	LoanID            uint      `json:"loanId"`
+	LoanAmount        int       `json:"loanAmount"`
+	LoanWeeks         int       `json:"loanWeeks"`
+	LoanInterest      int       `json:"loanInterest"`
+	Outstanding       int       `json:"outstanding"`
+	ProductCode       string    `json:"productCode,omitempty"`
+	Status            string    `json:"status"`
+	DelinquencyStatus string    `json:"delinquencyStatus"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+type BillingListResponse struct {
+	Billings   []BillingSummary `json:"billings"`
+	NextCursor string           `json:"nextCursor,omitempty"`
 }
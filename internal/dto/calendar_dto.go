@@ -0,0 +1,6 @@
+package dto
+
+type CalendarFile struct {
+	FileName string
+	Content  []byte
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "time"
+
+type CollectionAgentRequest struct {
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Region string `json:"region"`
+	Active *bool  `json:"active"`
+}
+
+type AssignCollectionCaseRequest struct {
+	AgentID uint `json:"agentId"`
+}
+
+type ContactAttemptRequest struct {
+	AgentID     *uint      `json:"agentId"`
+	Channel     string     `json:"channel"`
+	Outcome     string     `json:"outcome"`
+	Notes       string     `json:"notes"`
+	AttemptedAt *time.Time `json:"attemptedAt"`
+}
+
+type CollectionQueueItem struct {
+	CaseID        uint       `json:"caseId"`
+	BillingID     uint       `json:"billingId"`
+	CustomerID    uint       `json:"customerId"`
+	LoanID        uint       `json:"loanId"`
+	AgentID       *uint      `json:"agentId"`
+	Region        string     `json:"region"`
+	OpenedAt      time.Time  `json:"openedAt"`
+	DaysPastDue   int        `json:"daysPastDue"`
+	OverdueAmount int        `json:"overdueAmount"`
+	Outstanding   int        `json:"outstanding"`
+	PriorityScore int        `json:"priorityScore"`
+	LastContactAt *time.Time `json:"lastContactAt"`
+	LastOutcome   string     `json:"lastOutcome,omitempty"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type CustomerRequest struct {
+	Name              string `json:"name"`
+	Phone             string `json:"phone"`
+	Email             string `json:"email"`
+	PreferredLanguage string `json:"preferredLanguage"`
+	Timezone          string `json:"timezone"`
+	Region            string `json:"region"`
+	SMSOptIn          bool   `json:"smsOptIn"`
+	EmailOptIn        bool   `json:"emailOptIn"`
+	WhatsappOptIn     bool   `json:"whatsappOptIn"`
+}
+
+type ExposureLimitRequest struct {
+	ExposureLimit int `json:"exposureLimit"`
+}
+
+type CustomerSummaryResponse struct {
+	CustomerID           uint       `json:"customerId"`
+	AsOf                 time.Time  `json:"asOf"`
+	BillingCount         int        `json:"billingCount"`
+	ActiveBillingCount   int        `json:"activeBillingCount"`
+	TotalOutstanding     int        `json:"totalOutstanding"`
+	TotalOverdue         int        `json:"totalOverdue"`
+	NextDueDate          *time.Time `json:"nextDueDate"`
+	WorstDaysPastDue     int        `json:"worstDaysPastDue"`
+	WorstAgingBucket     string     `json:"worstAgingBucket,omitempty"`
+	IsDelinquent         bool       `json:"isDelinquent"`
+	DelinquentBillingIDs []uint     `json:"delinquentBillingIds"`
+	ExposureLimit        *int       `json:"exposureLimit"`
+	AvailableExposure    *int       `json:"availableExposure"`
+}
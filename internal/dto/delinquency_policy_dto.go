@@ -0,0 +1,21 @@
+package dto
+
+type DelinquencyPolicyRequest struct {
+	Code         string  `json:"code"`
+	ProductCode  *string `json:"productCode"`
+	Rule         string  `json:"rule"`
+	Threshold    int     `json:"threshold"`
+	WindowWeeks  int     `json:"windowWeeks"`
+	CureRule     string  `json:"cureRule"`
+	CurePayments int     `json:"curePayments"`
+}
+
+type AssignDelinquencyPolicyRequest struct {
+	PolicyID *uint `json:"policyId"`
+}
+
+type DelinquencyEvaluationResult struct {
+	Evaluated int
+	Changed   int
+	Failed    int
+}
@@ -0,0 +1,12 @@
+package dto
+
+type DunningStepRequest struct {
+	DaysPastDue int    `json:"daysPastDue"`
+	Action      string `json:"action"`
+}
+
+type DunningStrategyRequest struct {
+	Code        string               `json:"code"`
+	ProductCode *string              `json:"productCode"`
+	Steps       []DunningStepRequest `json:"steps"`
+}
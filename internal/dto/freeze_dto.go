@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+)
+
+type FreezeRequest struct {
+	StartDate time.Time `json:"startDate"`
+	EndDate   time.Time `json:"endDate"`
+	Reason    string    `json:"reason"`
+}
+
+type BillingFilter struct {
+	BillingIDs  []uint `json:"billingIds"`
+	CustomerIDs []uint `json:"customerIds"`
+	LoanIDs     []uint `json:"loanIds"`
+}
+
+type BatchFreezeRequest struct {
+	FreezeRequest
+	Filter BillingFilter `json:"filter"`
+}
+
+type BatchFreezeResponse struct {
+	Count   int                   `json:"count"`
+	Freezes []model.BillingFreeze `json:"freezes"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+import "github.com/doddeeph/billing-engine/internal/model"
+
+type GatewayCallbackResponse struct {
+	Duplicate bool                   `json:"duplicate"`
+	Callback  *model.GatewayCallback `json:"callback"`
+}
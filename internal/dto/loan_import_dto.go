@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+type ImportInstallment struct {
+	Week      int        `json:"week"`
+	Amount    int        `json:"amount"`
+	LateFee   int        `json:"lateFee"`
+	StartDate *time.Time `json:"startDate"`
+	DueDate   time.Time  `json:"dueDate"`
+}
+
+type ImportPayment struct {
+	Week     int       `json:"week"`
+	Amount   int       `json:"amount"`
+	PaidDate time.Time `json:"paidDate"`
+}
+
+type ImportBillingRequest struct {
+	CreateBillingDTO
+	StartDate    time.Time           `json:"startDate"`
+	Outstanding  *int                `json:"outstanding,omitempty"`
+	Installments []ImportInstallment `json:"installments,omitempty"`
+	Payments     []ImportPayment     `json:"payments,omitempty"`
+}
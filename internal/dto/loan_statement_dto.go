@@ -0,0 +1,13 @@
+package dto
+
+type LoanStatementQuery struct {
+	From   string
+	To     string
+	Format string
+}
+
+type LoanStatementFile struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
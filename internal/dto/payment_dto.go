@@ -8,8 +8,18 @@ type PaymentRequest struct {
 }
 
 type PaymentResponse struct {
-	CustomerID  uint          `json:"customerId"`
-	LoanID      uint          `json:"loanId"`
-	Outstanding int           `json:"outstanding"`
-	Payment     model.Payment `json:"payment"`
+	CustomerID  uint                  `json:"customerId"`
+	LoanID      uint                  `json:"loanId"`
+	Outstanding int                   `json:"outstanding"`
+	Status      string                `json:"status"`
+	Payment     *model.Payment        `json:"payment,omitempty"`
+	Recovery    *model.Recovery       `json:"recovery,omitempty"`
+	Promise     *model.PaymentPromise `json:"promise,omitempty"`
+}
+
+type PaymentBatchRowRequest struct {
+	BillingID uint   `json:"billingId"`
+	Week      int    `json:"week"`
+	Amount    int    `json:"amount"`
+	Reference string `json:"reference"`
 }
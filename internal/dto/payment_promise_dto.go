@@ -0,0 +1,7 @@
+package dto
+
+type PaymentPromiseRequest struct {
+	Amount      int    `json:"amount"`
+	PromiseDate string `json:"promiseDate"`
+	Notes       string `json:"notes"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+type QRISRequest struct {
+	Amount int `json:"amount"`
+}
+
+type QRISResponse struct {
+	Reference string `json:"reference"`
+	BillingID uint   `json:"billingId"`
+	Amount    int    `json:"amount"`
+	Week      int    `json:"week,omitempty"`
+	Payload   string `json:"payload"`
+	Image     string `json:"image"`
+}
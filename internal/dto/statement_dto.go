@@ -0,0 +1,8 @@
+package dto
+
+import "github.com/doddeeph/billing-engine/internal/model"
+
+type StatementImportResponse struct {
+	Duplicate bool                   `json:"duplicate"`
+	Import    *model.StatementImport `json:"import"`
+}
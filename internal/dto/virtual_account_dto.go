@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type NextInstallment struct {
+	Week      int       `json:"week"`
+	Amount    int       `json:"amount"`
+	LateFee   int       `json:"lateFee"`
+	AmountDue int       `json:"amountDue"`
+	DueDate   time.Time `json:"dueDate"`
+}
+
+type VirtualAccountResponse struct {
+	Number          string           `json:"number"`
+	BankCode        string           `json:"bankCode"`
+	BillingID       uint             `json:"billingId"`
+	CustomerID      uint             `json:"customerId"`
+	LoanID          uint             `json:"loanId"`
+	BillingStatus   string           `json:"billingStatus"`
+	Outstanding     int              `json:"outstanding"`
+	NextInstallment *NextInstallment `json:"nextInstallment"`
+}
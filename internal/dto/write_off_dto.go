@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+)
+
+type WriteOffRequest struct {
+	Reason string `json:"reason"`
+}
+
+type WriteOffResponse struct {
+	BaseResponse
+	Status           string    `json:"status"`
+	WrittenOffAmount int       `json:"writtenOffAmount"`
+	WriteOffReason   string    `json:"writeOffReason"`
+	WrittenOffAt     time.Time `json:"writtenOffAt"`
+}
+
+type RecoveriesResponse struct {
+	BaseResponse
+	WrittenOffAmount int              `json:"writtenOffAmount"`
+	RecoveredAmount  int              `json:"recoveredAmount"`
+	Recoveries       []model.Recovery `json:"recoveries"`
+}
+
+type WriteOffReportResponse struct {
+	WrittenOffCount  int `json:"writtenOffCount"`
+	WrittenOffAmount int `json:"writtenOffAmount"`
+	RecoveredAmount  int `json:"recoveredAmount"`
+	NetLoss          int `json:"netLoss"`
+}
@@ -0,0 +1,68 @@
+package gateway
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	MockProviderName    = "mock"
+	MockSignatureHeader = "X-Callback-Signature"
+	MockStatusPaid      = "PAID"
+)
+
+type MockCallbackRequest struct {
+	TransactionID  string     `json:"transactionId"`
+	BillingID      uint       `json:"billingId"`
+	VirtualAccount string     `json:"virtualAccount"`
+	Reference      string     `json:"reference"`
+	Amount         int        `json:"amount"`
+	Status         string     `json:"status"`
+	PaidAt         *time.Time `json:"paidAt"`
+}
+
+type mockProvider struct {
+	secret string
+}
+
+func NewMockProvider(secret string) Provider {
+	return &mockProvider{secret: secret}
+}
+
+func SignMockCallback(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (p *mockProvider) Name() string {
+	return MockProviderName
+}
+
+func (p *mockProvider) ParseCallback(header http.Header, body []byte) (*Callback, error) {
+	signature := header.Get(MockSignatureHeader)
+	if !hmac.Equal([]byte(SignMockCallback(p.secret, body)), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+	var req MockCallbackRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, fmt.Errorf("Invalid mock callback payload.")
+	}
+	if req.TransactionID == "" || (req.BillingID == 0 && req.VirtualAccount == "" && req.Reference == "") || req.Amount <= 0 {
+		return nil, fmt.Errorf("Mock callback requires transactionId, billingId, virtualAccount or reference and a positive amount.")
+	}
+	return &Callback{
+		ExternalID:     req.TransactionID,
+		BillingID:      req.BillingID,
+		VirtualAccount: req.VirtualAccount,
+		Reference:      req.Reference,
+		Amount:         req.Amount,
+		Paid:           req.Status == MockStatusPaid,
+		PaidAt:         req.PaidAt,
+	}, nil
+}
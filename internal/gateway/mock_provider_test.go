@@ -0,0 +1,69 @@
+package gateway
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func signedHeader(secret string, body []byte) http.Header {
+	header := http.Header{}
+	header.Set(MockSignatureHeader, SignMockCallback(secret, body))
+	return header
+}
+
+func TestMockProvider_ParseCallback(t *testing.T) {
+	provider := NewMockProvider("s3cret")
+	body := []byte(`{"transactionId":"trx-1","billingId":7,"amount":110000,"status":"PAID","paidAt":"2025-12-01T09:30:00Z"}`)
+
+	callback, err := provider.ParseCallback(signedHeader("s3cret", body), body)
+	assert.NoError(t, err)
+	assert.Equal(t, "trx-1", callback.ExternalID)
+	assert.Equal(t, uint(7), callback.BillingID)
+	assert.Equal(t, 110000, callback.Amount)
+	assert.True(t, callback.Paid)
+	assert.NotNil(t, callback.PaidAt)
+}
+
+func TestMockProvider_NotPaid(t *testing.T) {
+	provider := NewMockProvider("s3cret")
+	body := []byte(`{"transactionId":"trx-2","billingId":7,"amount":110000,"status":"EXPIRED"}`)
+
+	callback, err := provider.ParseCallback(signedHeader("s3cret", body), body)
+	assert.NoError(t, err)
+	assert.False(t, callback.Paid)
+}
+
+func TestMockProvider_RejectsInvalidSignature(t *testing.T) {
+	provider := NewMockProvider("s3cret")
+	body := []byte(`{"transactionId":"trx-1","billingId":7,"amount":110000,"status":"PAID"}`)
+
+	_, err := provider.ParseCallback(signedHeader("other", body), body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = provider.ParseCallback(http.Header{}, body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	tampered := []byte(`{"transactionId":"trx-1","billingId":7,"amount":990000,"status":"PAID"}`)
+	_, err = provider.ParseCallback(signedHeader("s3cret", body), tampered)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestMockProvider_RejectsIncompletePayload(t *testing.T) {
+	provider := NewMockProvider("s3cret")
+	body := []byte(`{"transactionId":"trx-1","amount":110000,"status":"PAID"}`)
+
+	_, err := provider.ParseCallback(signedHeader("s3cret", body), body)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(NewMockProvider("s3cret"))
+	provider, ok := registry.Get(MockProviderName)
+	assert.True(t, ok)
+	assert.Equal(t, MockProviderName, provider.Name())
+	_, ok = registry.Get("unknown")
+	assert.False(t, ok)
+}
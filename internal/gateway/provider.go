@@ -0,0 +1,44 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("Invalid callback signature.")
+	ErrUnknownProvider  = errors.New("Unknown payment gateway provider")
+)
+
+type Callback struct {
+	ExternalID     string
+	BillingID      uint
+	VirtualAccount string
+	Reference      string
+	Amount         int
+	Paid           bool
+	PaidAt         *time.Time
+}
+
+type Provider interface {
+	Name() string
+	ParseCallback(header http.Header, body []byte) (*Callback, error)
+}
+
+type Registry struct {
+	providers map[string]Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	registry := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, provider := range providers {
+		registry.providers[provider.Name()] = provider
+	}
+	return registry
+}
+
+func (r *Registry) Get(name string) (Provider, bool) {
+	provider, ok := r.providers[name]
+	return provider, ok
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/doddeeph/billing-engine/internal/dto"
 	"github.com/doddeeph/billing-engine/internal/service"
@@ -21,12 +22,20 @@ func (h *BillingHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	billing := rg.Group("/billings")
 	// POST /billings
 	billing.POST("", h.CreateBilling)
+	// GET /billings?status=ACTIVE&delinquencyStatus=DELINQUENT&createdFrom=2025-01-01&createdTo=2025-01-31&cursor=&limit=20
+	billing.GET("", h.ListBillings)
 	// GET /billings/1
 	billing.GET("/:id", h.GetBilling)
 	// GET /billings/1/outstanding
 	billing.GET("/:id/outstanding", h.GetOutstanding)
 	// GET /billings/1/delinquent
 	billing.GET("/:id/delinquent", h.IsDelinquent)
+	// GET /loans/1001/billing
+	rg.GET("/loans/:loanId/billing", h.GetBillingByLoanID)
+	// GET /customers/1/billings
+	rg.GET("/customers/:customerId/billings", h.ListCustomerBillings)
+	// GET /portfolio/aging
+	rg.GET("/portfolio/aging", h.GetPortfolioAging)
 }
 
 func (h *BillingHandler) CreateBilling(c *gin.Context) {
@@ -41,14 +50,17 @@ func (h *BillingHandler) CreateBilling(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusCreated, dto.CreateBillingResponse{
-		BillingID:   billing.ID,
-		Outstanding: billing.Outstanding,
+		BillingID:       billing.ID,
+		Outstanding:     billing.Outstanding,
+		VirtualAccounts: billing.VirtualAccounts,
 		CreateBillingDTO: dto.CreateBillingDTO{
-			CustomerID:   billing.CustomerID,
-			LoanID:       billing.LoanID,
-			LoanAmount:   billing.LoanAmount,
-			LoanInterest: billing.LoanInterest,
-			LoanWeeks:    billing.LoanWeeks,
+			CustomerID:          billing.CustomerID,
+			LoanID:              billing.LoanID,
+			LoanAmount:          billing.LoanAmount,
+			LoanInterest:        billing.LoanInterest,
+			LoanWeeks:           billing.LoanWeeks,
+			ProductCode:         billing.ProductCode,
+			DelinquencyPolicyID: billing.DelinquencyPolicyID,
 		},
 	})
 }
@@ -68,6 +80,55 @@ func (h *BillingHandler) GetBilling(c *gin.Context) {
 	c.JSON(http.StatusOK, billing)
 }
 
+func (h *BillingHandler) GetBillingByLoanID(c *gin.Context) {
+	loanID, err := utils.ConvertStringToUint(c.Param("loanId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	billing, err := h.svc.GetBillingByLoanID(c.Request.Context(), loanID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, billing)
+}
+
+func (h *BillingHandler) ListBillings(c *gin.Context) {
+	h.listBillings(c, 0)
+}
+
+func (h *BillingHandler) ListCustomerBillings(c *gin.Context) {
+	customerID, err := utils.ConvertStringToUint(c.Param("customerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.listBillings(c, customerID)
+}
+
+func (h *BillingHandler) listBillings(c *gin.Context, customerID uint) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	resp, err := h.svc.ListBillings(c.Request.Context(), dto.BillingListQuery{
+		CustomerID:        customerID,
+		Status:            c.Query("status"),
+		DelinquencyStatus: c.Query("delinquencyStatus"),
+		CreatedFrom:       c.Query("createdFrom"),
+		CreatedTo:         c.Query("createdTo"),
+		Cursor:            c.Query("cursor"),
+		Limit:             limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
 func (h *BillingHandler) GetOutstanding(c *gin.Context) {
 	id := c.Param("id")
 	billingID, err := utils.ConvertStringToUint(id)
@@ -86,6 +147,7 @@ func (h *BillingHandler) GetOutstanding(c *gin.Context) {
 			CustomerID: billing.CustomerID,
 			LoanID:     billing.LoanID,
 		},
+		Aging:       h.svc.GetAging(billing),
 		Outstanding: billing.Outstanding,
 	})
 }
@@ -97,7 +159,7 @@ func (h *BillingHandler) IsDelinquent(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	billing, isDelinquent, err := h.svc.IsDelinquent(c.Request.Context(), billingID)
+	billing, delinquency, err := h.svc.IsDelinquent(c.Request.Context(), billingID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -108,6 +170,16 @@ func (h *BillingHandler) IsDelinquent(c *gin.Context) {
 			CustomerID: billing.CustomerID,
 			LoanID:     billing.LoanID,
 		},
-		IsDelinquent: isDelinquent,
+		Aging:       h.svc.GetAging(billing),
+		Delinquency: *delinquency,
 	})
 }
+
+func (h *BillingHandler) GetPortfolioAging(c *gin.Context) {
+	resp, err := h.svc.GetPortfolioAging(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
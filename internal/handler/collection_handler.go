@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type CollectionHandler struct {
+	svc service.CollectionService
+}
+
+func NewCollectionHandler(svc service.CollectionService) *CollectionHandler {
+	return &CollectionHandler{svc: svc}
+}
+
+func (h *CollectionHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	agent := rg.Group("/collection-agents")
+	// POST /collection-agents
+	agent.POST("", h.CreateAgent)
+	// GET /collection-agents
+	agent.GET("", h.GetAgents)
+	// PUT /collection-agents/1
+	agent.PUT("/:id", h.UpdateAgent)
+	// GET /collection-agents/1/queue?limit=20&offset=0
+	agent.GET("/:id/queue", h.GetAgentQueue)
+	collectionCase := rg.Group("/collection-cases")
+	// GET /collection-cases/unassigned?limit=20&offset=0
+	collectionCase.GET("/unassigned", h.GetUnassignedQueue)
+	// GET /collection-cases/1
+	collectionCase.GET("/:id", h.GetCase)
+	// PUT /collection-cases/1/agent
+	collectionCase.PUT("/:id/agent", h.AssignCase)
+	// POST /collection-cases/1/contact-attempts
+	collectionCase.POST("/:id/contact-attempts", h.LogContactAttempt)
+	// GET /billings/1/collection-cases
+	rg.GET("/billings/:id/collection-cases", h.GetBillingCases)
+}
+
+func (h *CollectionHandler) CreateAgent(c *gin.Context) {
+	var req dto.CollectionAgentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	agent, err := h.svc.CreateAgent(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, agent)
+}
+
+func (h *CollectionHandler) GetAgents(c *gin.Context) {
+	agents, err := h.svc.GetAgents(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, agents)
+}
+
+func (h *CollectionHandler) UpdateAgent(c *gin.Context) {
+	agentID, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req dto.CollectionAgentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	agent, err := h.svc.UpdateAgent(c.Request.Context(), agentID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, agent)
+}
+
+func (h *CollectionHandler) GetAgentQueue(c *gin.Context) {
+	agentID, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+		return
+	}
+	queue, err := h.svc.GetAgentQueue(c.Request.Context(), agentID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, queue)
+}
+
+func (h *CollectionHandler) GetUnassignedQueue(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+		return
+	}
+	queue, err := h.svc.GetUnassignedQueue(c.Request.Context(), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, queue)
+}
+
+func (h *CollectionHandler) GetCase(c *gin.Context) {
+	caseID, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	collectionCase, err := h.svc.GetCase(c.Request.Context(), caseID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, collectionCase)
+}
+
+func (h *CollectionHandler) AssignCase(c *gin.Context) {
+	caseID, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req dto.AssignCollectionCaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	collectionCase, err := h.svc.AssignCase(c.Request.Context(), caseID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, collectionCase)
+}
+
+func (h *CollectionHandler) LogContactAttempt(c *gin.Context) {
+	caseID, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req dto.ContactAttemptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	attempt, err := h.svc.LogContactAttempt(c.Request.Context(), caseID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, attempt)
+}
+
+func (h *CollectionHandler) GetBillingCases(c *gin.Context) {
+	billingID, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	cases, err := h.svc.GetBillingCases(c.Request.Context(), billingID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cases)
+}
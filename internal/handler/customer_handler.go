@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type CustomerHandler struct {
+	svc service.CustomerService
+}
+
+func NewCustomerHandler(svc service.CustomerService) *CustomerHandler {
+	return &CustomerHandler{svc: svc}
+}
+
+func (h *CustomerHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	customer := rg.Group("/customers")
+	// PUT /customers/1
+	customer.PUT("/:customerId", h.UpsertCustomer)
+	// GET /customers/1
+	customer.GET("/:customerId", h.GetCustomer)
+	// GET /customers/1/summary
+	customer.GET("/:customerId/summary", h.GetSummary)
+	// PUT /customers/1/exposure-limit
+	customer.PUT("/:customerId/exposure-limit", h.SetExposureLimit)
+	// DELETE /customers/1/exposure-limit
+	customer.DELETE("/:customerId/exposure-limit", h.RemoveExposureLimit)
+}
+
+func (h *CustomerHandler) UpsertCustomer(c *gin.Context) {
+	customerID, err := utils.ConvertStringToUint(c.Param("customerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req dto.CustomerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	customer, err := h.svc.UpsertCustomer(c.Request.Context(), customerID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, customer)
+}
+
+func (h *CustomerHandler) GetCustomer(c *gin.Context) {
+	customerID, err := utils.ConvertStringToUint(c.Param("customerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	customer, err := h.svc.GetCustomer(c.Request.Context(), customerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, customer)
+}
+
+func (h *CustomerHandler) GetSummary(c *gin.Context) {
+	customerID, err := utils.ConvertStringToUint(c.Param("customerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	summary, err := h.svc.GetSummary(c.Request.Context(), customerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+func (h *CustomerHandler) SetExposureLimit(c *gin.Context) {
+	customerID, err := utils.ConvertStringToUint(c.Param("customerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req dto.ExposureLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	limit, err := h.svc.SetExposureLimit(c.Request.Context(), customerID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, limit)
+}
+
+func (h *CustomerHandler) RemoveExposureLimit(c *gin.Context) {
+	customerID, err := utils.ConvertStringToUint(c.Param("customerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.svc.RemoveExposureLimit(c.Request.Context(), customerID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type DelinquencyHandler struct {
+	svc service.DelinquencyService
+}
+
+func NewDelinquencyHandler(svc service.DelinquencyService) *DelinquencyHandler {
+	return &DelinquencyHandler{svc: svc}
+}
+
+func (h *DelinquencyHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	// GET /billings/1/delinquency-history
+	rg.GET("/billings/:id/delinquency-history", h.GetHistory)
+}
+
+func (h *DelinquencyHandler) GetHistory(c *gin.Context) {
+	id := c.Param("id")
+	billingID, err := utils.ConvertStringToUint(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	histories, err := h.svc.GetHistory(c.Request.Context(), billingID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, histories)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type DelinquencyPolicyHandler struct {
+	svc service.DelinquencyPolicyService
+}
+
+func NewDelinquencyPolicyHandler(svc service.DelinquencyPolicyService) *DelinquencyPolicyHandler {
+	return &DelinquencyPolicyHandler{svc: svc}
+}
+
+func (h *DelinquencyPolicyHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	policy := rg.Group("/delinquency-policies")
+	// POST /delinquency-policies
+	policy.POST("", h.CreatePolicy)
+	// GET /delinquency-policies
+	policy.GET("", h.GetPolicies)
+	// PUT /delinquency-policies/1
+	policy.PUT("/:id", h.UpdatePolicy)
+	// PUT /billings/1/delinquency-policy
+	rg.PUT("/billings/:id/delinquency-policy", h.AssignPolicy)
+}
+
+func (h *DelinquencyPolicyHandler) CreatePolicy(c *gin.Context) {
+	var req dto.DelinquencyPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	policy, err := h.svc.CreatePolicy(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, policy)
+}
+
+func (h *DelinquencyPolicyHandler) GetPolicies(c *gin.Context) {
+	policies, err := h.svc.GetPolicies(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policies)
+}
+
+func (h *DelinquencyPolicyHandler) UpdatePolicy(c *gin.Context) {
+	id := c.Param("id")
+	policyID, err := utils.ConvertStringToUint(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req dto.DelinquencyPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	policy, err := h.svc.UpdatePolicy(c.Request.Context(), policyID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *DelinquencyPolicyHandler) AssignPolicy(c *gin.Context) {
+	id := c.Param("id")
+	billingID, err := utils.ConvertStringToUint(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req dto.AssignDelinquencyPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	billing, err := h.svc.AssignPolicy(c.Request.Context(), billingID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, billing)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type DunningHandler struct {
+	svc service.DunningService
+}
+
+func NewDunningHandler(svc service.DunningService) *DunningHandler {
+	return &DunningHandler{svc: svc}
+}
+
+func (h *DunningHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	strategy := rg.Group("/dunning-strategies")
+	// POST /dunning-strategies
+	strategy.POST("", h.CreateStrategy)
+	// GET /dunning-strategies
+	strategy.GET("", h.GetStrategies)
+	// PUT /dunning-strategies/1
+	strategy.PUT("/:id", h.UpdateStrategy)
+	// GET /billings/1/dunning-log
+	rg.GET("/billings/:id/dunning-log", h.GetBillingLog)
+}
+
+func (h *DunningHandler) CreateStrategy(c *gin.Context) {
+	var req dto.DunningStrategyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	strategy, err := h.svc.CreateStrategy(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, strategy)
+}
+
+func (h *DunningHandler) GetStrategies(c *gin.Context) {
+	strategies, err := h.svc.GetStrategies(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, strategies)
+}
+
+func (h *DunningHandler) UpdateStrategy(c *gin.Context) {
+	strategyID, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req dto.DunningStrategyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	strategy, err := h.svc.UpdateStrategy(c.Request.Context(), strategyID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, strategy)
+}
+
+func (h *DunningHandler) GetBillingLog(c *gin.Context) {
+	billingID, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	logs, err := h.svc.GetBillingLog(c.Request.Context(), billingID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, logs)
+}
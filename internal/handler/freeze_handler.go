@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type FreezeHandler struct {
+	svc service.FreezeService
+}
+
+func NewFreezeHandler(svc service.FreezeService) *FreezeHandler {
+	return &FreezeHandler{svc: svc}
+}
+
+func (h *FreezeHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	freeze := rg.Group("/billings/:id/freezes")
+	// POST /billings/:id/freezes
+	freeze.POST("", h.FreezeBilling)
+	// GET /billings/:id/freezes
+	freeze.GET("", h.GetFreezes)
+	// POST /freezes
+	rg.POST("/freezes", h.FreezeBillings)
+}
+
+func (h *FreezeHandler) FreezeBilling(c *gin.Context) {
+	id := c.Param("id")
+	billingID, err := utils.ConvertStringToUint(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req dto.FreezeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	freeze, err := h.svc.FreezeBilling(c.Request.Context(), billingID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, freeze)
+}
+
+func (h *FreezeHandler) FreezeBillings(c *gin.Context) {
+	var req dto.BatchFreezeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	freezes, err := h.svc.FreezeBillings(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, dto.BatchFreezeResponse{
+		Count:   len(freezes),
+		Freezes: freezes,
+	})
+}
+
+func (h *FreezeHandler) GetFreezes(c *gin.Context) {
+	id := c.Param("id")
+	billingID, err := utils.ConvertStringToUint(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	freezes, err := h.svc.GetFreezes(c.Request.Context(), billingID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, freezes)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/gateway"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type GatewayHandler struct {
+	svc service.GatewayService
+}
+
+func NewGatewayHandler(svc service.GatewayService) *GatewayHandler {
+	return &GatewayHandler{svc: svc}
+}
+
+func (h *GatewayHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	// POST /gateways/mock/callbacks
+	rg.POST("/gateways/:provider/callbacks", h.HandleCallback)
+	// GET /gateway-callbacks?provider=mock&status=FAILED&limit=20
+	rg.GET("/gateway-callbacks", h.GetCallbacks)
+}
+
+func (h *GatewayHandler) HandleCallback(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	callback, duplicate, err := h.svc.HandleCallback(c.Request.Context(), c.Param("provider"), c.Request.Header, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, gateway.ErrInvalidSignature):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, gateway.ErrUnknownProvider):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, dto.GatewayCallbackResponse{Duplicate: duplicate, Callback: callback})
+}
+
+func (h *GatewayHandler) GetCallbacks(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	callbacks, err := h.svc.GetCallbacks(c.Request.Context(), c.Query("provider"), c.Query("status"), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, callbacks)
+}
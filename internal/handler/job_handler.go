@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/doddeeph/billing-engine/internal/scheduler"
+	"github.com/gin-gonic/gin"
+)
+
+type JobHandler struct {
+	scheduler *scheduler.Scheduler
+	runRepo   repository.JobRunRepository
+}
+
+func NewJobHandler(scheduler *scheduler.Scheduler, runRepo repository.JobRunRepository) *JobHandler {
+	return &JobHandler{scheduler: scheduler, runRepo: runRepo}
+}
+
+func (h *JobHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	job := rg.Group("/jobs")
+	// GET /jobs/runs?job=eod-sweep&limit=20
+	job.GET("/runs", h.GetRuns)
+	// POST /jobs/eod-sweep/run
+	job.POST("/:name/run", h.TriggerJob)
+}
+
+func (h *JobHandler) GetRuns(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	runs, err := h.runRepo.FindRecent(c.Request.Context(), c.Query("job"), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, runs)
+}
+
+func (h *JobHandler) TriggerJob(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.scheduler.Trigger(name); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"job": name, "status": "triggered"})
+}
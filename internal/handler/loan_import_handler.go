@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type LoanImportHandler struct {
+	svc service.LoanImportService
+}
+
+func NewLoanImportHandler(svc service.LoanImportService) *LoanImportHandler {
+	return &LoanImportHandler{svc: svc}
+}
+
+func (h *LoanImportHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	// POST /loan-imports
+	rg.POST("/loan-imports", h.SubmitImport)
+	// GET /loan-imports/1?status=FAILED
+	rg.GET("/loan-imports/:id", h.GetImport)
+	// POST /loan-imports/1/retry
+	rg.POST("/loan-imports/:id/retry", h.RetryImport)
+}
+
+func (h *LoanImportHandler) SubmitImport(c *gin.Context) {
+	var data []byte
+	var err error
+	if fileHeader, fileErr := c.FormFile("file"); fileErr == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+		defer file.Close()
+		data, err = io.ReadAll(file)
+	} else {
+		data, err = io.ReadAll(c.Request.Body)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	loanImport, err := h.svc.SubmitImport(c.Request.Context(), data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, loanImport)
+}
+
+func (h *LoanImportHandler) GetImport(c *gin.Context) {
+	id, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	loanImport, err := h.svc.GetImport(c.Request.Context(), id, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, loanImport)
+}
+
+func (h *LoanImportHandler) RetryImport(c *gin.Context) {
+	id, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	loanImport, err := h.svc.RetryImport(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, loanImport)
+}
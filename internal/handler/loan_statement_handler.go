@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/loanstatement"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type LoanStatementHandler struct {
+	svc service.LoanStatementService
+}
+
+func NewLoanStatementHandler(svc service.LoanStatementService) *LoanStatementHandler {
+	return &LoanStatementHandler{svc: svc}
+}
+
+func (h *LoanStatementHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	// GET /billings/1/statement?from=2025-08-01&to=2025-08-31&format=pdf
+	rg.GET("/billings/:id/statement", h.GetStatement)
+}
+
+func (h *LoanStatementHandler) GetStatement(c *gin.Context) {
+	billingID, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	file, err := h.svc.RenderStatement(c.Request.Context(), billingID, dto.LoanStatementQuery{
+		From:   c.Query("from"),
+		To:     c.Query("to"),
+		Format: c.Query("format"),
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	disposition := "inline"
+	if file.ContentType == loanstatement.ContentTypes[loanstatement.FormatCSV] {
+		disposition = "attachment"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("%s; filename=%q", disposition, file.FileName))
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
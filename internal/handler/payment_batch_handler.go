@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentBatchHandler struct {
+	svc service.PaymentBatchService
+}
+
+func NewPaymentBatchHandler(svc service.PaymentBatchService) *PaymentBatchHandler {
+	return &PaymentBatchHandler{svc: svc}
+}
+
+func (h *PaymentBatchHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	// POST /payments/batch?dryRun=true&async=true
+	rg.POST("/payments/batch", h.SubmitBatch)
+	// GET /payments/batch/1?status=REJECTED
+	rg.GET("/payments/batch/:id", h.GetBatch)
+}
+
+func (h *PaymentBatchHandler) SubmitBatch(c *gin.Context) {
+	format := c.Query("format")
+	var data []byte
+	var err error
+	if fileHeader, fileErr := c.FormFile("file"); fileErr == nil {
+		if format == "" {
+			format = paymentBatchFormat(filepath.Ext(fileHeader.Filename))
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+		defer file.Close()
+		data, err = io.ReadAll(file)
+	} else {
+		if format == "" {
+			format = paymentBatchFormat(c.ContentType())
+		}
+		data, err = io.ReadAll(c.Request.Body)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	batch, err := h.svc.SubmitBatch(c.Request.Context(), format, data, c.Query("dryRun") == "true", c.Query("async") == "true")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if batch.Status == model.PaymentBatchStatusPending {
+		c.JSON(http.StatusAccepted, batch)
+		return
+	}
+	c.JSON(http.StatusOK, batch)
+}
+
+func (h *PaymentBatchHandler) GetBatch(c *gin.Context) {
+	id, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	batch, err := h.svc.GetBatch(c.Request.Context(), id, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, batch)
+}
+
+func paymentBatchFormat(hint string) string {
+	switch strings.ToLower(hint) {
+	case ".csv", "text/csv":
+		return model.PaymentBatchFormatCSV
+	case ".jsonl", ".ndjson", "application/jsonl", "application/x-ndjson", "application/json":
+		return model.PaymentBatchFormatJSONL
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentPromiseHandler struct {
+	svc service.PaymentPromiseService
+}
+
+func NewPaymentPromiseHandler(svc service.PaymentPromiseService) *PaymentPromiseHandler {
+	return &PaymentPromiseHandler{svc: svc}
+}
+
+func (h *PaymentPromiseHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	promise := rg.Group("/billings/:id/payment-promises")
+	// POST /billings/:id/payment-promises
+	promise.POST("", h.CreatePromise)
+	// GET /billings/:id/payment-promises
+	promise.GET("", h.GetPromises)
+}
+
+func (h *PaymentPromiseHandler) CreatePromise(c *gin.Context) {
+	billingID, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req dto.PaymentPromiseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	promise, err := h.svc.CreatePromise(c.Request.Context(), billingID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, promise)
+}
+
+func (h *PaymentPromiseHandler) GetPromises(c *gin.Context) {
+	billingID, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	promises, err := h.svc.GetPromises(c.Request.Context(), billingID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, promises)
+}
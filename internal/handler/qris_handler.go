@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type QRISHandler struct {
+	svc service.QRISService
+}
+
+func NewQRISHandler(svc service.QRISService) *QRISHandler {
+	return &QRISHandler{svc: svc}
+}
+
+func (h *QRISHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	// POST /billings/1/qris
+	rg.POST("/billings/:id/qris", h.GeneratePayment)
+	// GET /qris/QR1A1B2C3D4/image
+	rg.GET("/qris/:reference/image", h.GetImage)
+}
+
+func (h *QRISHandler) GeneratePayment(c *gin.Context) {
+	id := c.Param("id")
+	billingID, err := utils.ConvertStringToUint(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req dto.QRISRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+	}
+	resp, err := h.svc.GeneratePayment(c.Request.Context(), billingID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *QRISHandler) GetImage(c *gin.Context) {
+	png, err := h.svc.GetImage(c.Request.Context(), c.Param("reference"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "image/png", png)
+}
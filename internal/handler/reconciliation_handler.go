@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ReconciliationHandler struct {
+	svc service.ReconciliationService
+}
+
+func NewReconciliationHandler(svc service.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{svc: svc}
+}
+
+func (h *ReconciliationHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	// POST /statement-imports
+	rg.POST("/statement-imports", h.ImportStatement)
+	// GET /statement-imports?limit=20
+	rg.GET("/statement-imports", h.GetImports)
+	// GET /statement-imports/1?status=UNMATCHED
+	rg.GET("/statement-imports/:id", h.GetImport)
+}
+
+func (h *ReconciliationHandler) ImportStatement(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	statementImport, duplicate, err := h.svc.ImportStatement(c.Request.Context(), fileHeader.Filename, c.PostForm("format"), data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.StatementImportResponse{Duplicate: duplicate, Import: statementImport})
+}
+
+func (h *ReconciliationHandler) GetImports(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	statementImports, err := h.svc.GetImports(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, statementImports)
+}
+
+func (h *ReconciliationHandler) GetImport(c *gin.Context) {
+	id, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	statementImport, err := h.svc.GetImport(c.Request.Context(), id, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, statementImport)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ReminderHandler struct {
+	svc service.ReminderService
+}
+
+func NewReminderHandler(svc service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{svc: svc}
+}
+
+func (h *ReminderHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	// GET /reminders?status=FAILED&limit=20
+	rg.GET("/reminders", h.GetReminders)
+	// GET /billings/1/reminders
+	rg.GET("/billings/:id/reminders", h.GetBillingReminders)
+}
+
+func (h *ReminderHandler) GetReminders(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	reminders, err := h.svc.GetReminders(c.Request.Context(), c.Query("status"), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, reminders)
+}
+
+func (h *ReminderHandler) GetBillingReminders(c *gin.Context) {
+	billingID, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	reminders, err := h.svc.GetBillingReminders(c.Request.Context(), billingID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, reminders)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/ical"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type RepaymentCalendarHandler struct {
+	svc service.RepaymentCalendarService
+}
+
+func NewRepaymentCalendarHandler(svc service.RepaymentCalendarService) *RepaymentCalendarHandler {
+	return &RepaymentCalendarHandler{svc: svc}
+}
+
+func (h *RepaymentCalendarHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	// GET /billings/1/schedule.ics
+	rg.GET("/billings/:id/schedule.ics", h.GetBillingCalendar)
+	// GET /customers/1/schedule.ics
+	rg.GET("/customers/:customerId/schedule.ics", h.GetCustomerCalendar)
+}
+
+func (h *RepaymentCalendarHandler) GetBillingCalendar(c *gin.Context) {
+	billingID, err := utils.ConvertStringToUint(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	file, err := h.svc.GetBillingCalendar(c.Request.Context(), billingID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	writeCalendar(c, file)
+}
+
+func (h *RepaymentCalendarHandler) GetCustomerCalendar(c *gin.Context) {
+	customerID, err := utils.ConvertStringToUint(c.Param("customerId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	file, err := h.svc.GetCustomerCalendar(c.Request.Context(), customerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	writeCalendar(c, file)
+}
+
+func writeCalendar(c *gin.Context, file *dto.CalendarFile) {
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", file.FileName))
+	c.Header("Cache-Control", "no-cache")
+	c.Data(http.StatusOK, ical.ContentType, file.Content)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type VirtualAccountHandler struct {
+	svc service.VirtualAccountService
+}
+
+func NewVirtualAccountHandler(svc service.VirtualAccountService) *VirtualAccountHandler {
+	return &VirtualAccountHandler{svc: svc}
+}
+
+func (h *VirtualAccountHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	// GET /virtual-accounts/3935800000000420
+	rg.GET("/virtual-accounts/:number", h.GetVirtualAccount)
+	// POST /billings/1/virtual-accounts
+	rg.POST("/billings/:id/virtual-accounts", h.IssueVirtualAccounts)
+}
+
+func (h *VirtualAccountHandler) GetVirtualAccount(c *gin.Context) {
+	virtualAccount, err := h.svc.GetVirtualAccount(c.Request.Context(), c.Param("number"))
+	if errors.Is(err, service.ErrVirtualAccountNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, virtualAccount)
+}
+
+func (h *VirtualAccountHandler) IssueVirtualAccounts(c *gin.Context) {
+	id := c.Param("id")
+	billingID, err := utils.ConvertStringToUint(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	virtualAccounts, err := h.svc.IssueVirtualAccounts(c.Request.Context(), billingID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, virtualAccounts)
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	svc service.WebhookService
+}
+
+func NewWebhookHandler(svc service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{svc: svc}
+}
+
+func (h *WebhookHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	webhook := rg.Group("/webhooks")
+	// POST /webhooks
+	webhook.POST("", h.CreateSubscription)
+	// GET /webhooks
+	webhook.GET("", h.GetSubscriptions)
+	// PUT /webhooks/1
+	webhook.PUT("/:id", h.UpdateSubscription)
+	// DELETE /webhooks/1
+	webhook.DELETE("/:id", h.DeleteSubscription)
+	delivery := rg.Group("/webhook-deliveries")
+	// GET /webhook-deliveries?subscriptionId=1&status=DEAD&limit=20
+	delivery.GET("", h.GetDeliveries)
+	// POST /webhook-deliveries/1/replay
+	delivery.POST("/:id/replay", h.ReplayDelivery)
+}
+
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	var req dto.WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	subscription, err := h.svc.CreateSubscription(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, subscription)
+}
+
+func (h *WebhookHandler) GetSubscriptions(c *gin.Context) {
+	subscriptions, err := h.svc.GetSubscriptions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+func (h *WebhookHandler) UpdateSubscription(c *gin.Context) {
+	id := c.Param("id")
+	subscriptionID, err := utils.ConvertStringToUint(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req dto.WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	subscription, err := h.svc.UpdateSubscription(c.Request.Context(), subscriptionID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, subscription)
+}
+
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	id := c.Param("id")
+	subscriptionID, err := utils.ConvertStringToUint(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.svc.DeleteSubscription(c.Request.Context(), subscriptionID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+		return
+	}
+	var subscriptionID uint
+	if id := c.Query("subscriptionId"); id != "" {
+		subscriptionID, err = utils.ConvertStringToUint(id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	deliveries, err := h.svc.GetDeliveries(c.Request.Context(), subscriptionID, c.Query("status"), limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	id := c.Param("id")
+	deliveryID, err := utils.ConvertStringToUint(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	delivery, err := h.svc.ReplayDelivery(c.Request.Context(), deliveryID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
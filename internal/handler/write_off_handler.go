@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/service"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type WriteOffHandler struct {
+	svc service.WriteOffService
+}
+
+func NewWriteOffHandler(svc service.WriteOffService) *WriteOffHandler {
+	return &WriteOffHandler{svc: svc}
+}
+
+func (h *WriteOffHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	billing := rg.Group("/billings/:id")
+	// POST /billings/:id/write-off
+	billing.POST("/write-off", h.WriteOff)
+	// GET /billings/:id/recoveries
+	billing.GET("/recoveries", h.GetRecoveries)
+	// GET /reports/write-offs
+	rg.GET("/reports/write-offs", h.GetReport)
+}
+
+func (h *WriteOffHandler) WriteOff(c *gin.Context) {
+	id := c.Param("id")
+	billingID, err := utils.ConvertStringToUint(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var req dto.WriteOffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+	resp, err := h.svc.WriteOff(c.Request.Context(), billingID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *WriteOffHandler) GetRecoveries(c *gin.Context) {
+	id := c.Param("id")
+	billingID, err := utils.ConvertStringToUint(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	resp, err := h.svc.GetRecoveries(c.Request.Context(), billingID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *WriteOffHandler) GetReport(c *gin.Context) {
+	resp, err := h.svc.GetReport(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,118 @@
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	ContentType = "text/calendar; charset=utf-8"
+
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+
+	maxLineOctets = 75
+	dateFormat    = "20060102"
+	stampFormat   = "20060102T150405Z"
+)
+
+type Event struct {
+	UID          string
+	Sequence     int
+	Date         time.Time
+	LastModified time.Time
+	Summary      string
+	Description  string
+	Status       string
+}
+
+type Calendar struct {
+	ProdID  string
+	Name    string
+	Refresh time.Duration
+	Events  []Event
+}
+
+func (c *Calendar) Render(now time.Time) []byte {
+	var b bytes.Buffer
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+c.ProdID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+Escape(c.Name))
+	}
+	if c.Refresh > 0 {
+		duration := formatDuration(c.Refresh)
+		writeLine(&b, "REFRESH-INTERVAL;VALUE=DURATION:"+duration)
+		writeLine(&b, "X-PUBLISHED-TTL:"+duration)
+	}
+	stamp := now.UTC().Format(stampFormat)
+	for _, e := range c.Events {
+		status := e.Status
+		if status == "" {
+			status = StatusConfirmed
+		}
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+e.UID)
+		writeLine(&b, "DTSTAMP:"+stamp)
+		writeLine(&b, fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+		if !e.LastModified.IsZero() {
+			writeLine(&b, "LAST-MODIFIED:"+e.LastModified.UTC().Format(stampFormat))
+		}
+		writeLine(&b, "DTSTART;VALUE=DATE:"+e.Date.Format(dateFormat))
+		writeLine(&b, "DTEND;VALUE=DATE:"+e.Date.AddDate(0, 0, 1).Format(dateFormat))
+		writeLine(&b, "SUMMARY:"+Escape(e.Summary))
+		if e.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+Escape(e.Description))
+		}
+		writeLine(&b, "STATUS:"+status)
+		writeLine(&b, "TRANSP:TRANSPARENT")
+		writeLine(&b, "END:VEVENT")
+	}
+	writeLine(&b, "END:VCALENDAR")
+	return b.Bytes()
+}
+
+func Escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+func writeLine(b *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func formatDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	s := "PT"
+	if hours > 0 {
+		s += fmt.Sprintf("%dH", hours)
+	}
+	if minutes > 0 || hours == 0 {
+		s += fmt.Sprintf("%dM", minutes)
+	}
+	return s
+}
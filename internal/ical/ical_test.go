@@ -0,0 +1,61 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendar_Render(t *testing.T) {
+	now := time.Date(2025, 8, 10, 9, 30, 0, 0, time.UTC)
+	cal := &Calendar{
+		ProdID:  "-//Billing Engine//Repayment Schedule//EN",
+		Name:    "Loan 1001, repayments",
+		Refresh: 6 * time.Hour,
+		Events: []Event{
+			{UID: "payment-1@billing-engine", Date: time.Date(2025, 8, 14, 0, 0, 0, 0, time.UTC), Summary: "Installment 1 due", Description: "Amount Rp 110.000\nWeek 1"},
+			{UID: "payment-2@billing-engine", Sequence: 3, Date: time.Date(2025, 8, 21, 0, 0, 0, 0, time.UTC), LastModified: now, Summary: "Installment 2 paid", Status: StatusCancelled},
+		},
+	}
+
+	out := string(cal.Render(now))
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.NotContains(t, strings.ReplaceAll(out, "\r\n", ""), "\n")
+	assert.Contains(t, out, "X-WR-CALNAME:Loan 1001\\, repayments\r\n")
+	assert.Contains(t, out, "REFRESH-INTERVAL;VALUE=DURATION:PT6H\r\n")
+	assert.Equal(t, 2, strings.Count(out, "BEGIN:VEVENT\r\n"))
+	assert.Equal(t, 2, strings.Count(out, "DTSTAMP:20250810T093000Z\r\n"))
+	assert.Contains(t, out, "UID:payment-1@billing-engine\r\nDTSTAMP:20250810T093000Z\r\nSEQUENCE:0\r\nDTSTART;VALUE=DATE:20250814\r\nDTEND;VALUE=DATE:20250815\r\n")
+	assert.Contains(t, out, "DESCRIPTION:Amount Rp 110.000\\nWeek 1\r\nSTATUS:CONFIRMED\r\n")
+	assert.Contains(t, out, "SEQUENCE:3\r\nLAST-MODIFIED:20250810T093000Z\r\n")
+	assert.Contains(t, out, "STATUS:CANCELLED\r\n")
+}
+
+func TestWriteLine_FoldsLongLines(t *testing.T) {
+	var b bytes.Buffer
+	line := "DESCRIPTION:" + strings.Repeat("é", 80)
+
+	writeLine(&b, line)
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	assert.Greater(t, len(lines), 1)
+	var unfolded strings.Builder
+	for i, l := range lines {
+		assert.LessOrEqual(t, len(l), maxLineOctets)
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(l, " "))
+			l = l[1:]
+		}
+		unfolded.WriteString(l)
+	}
+	assert.Equal(t, line, unfolded.String())
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, `a\\b\;c\,d\ne`, Escape("a\\b;c,d\ne"))
+}
@@ -0,0 +1,46 @@
+package loanstatement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var csvHeader = []string{"date", "type", "week", "description", "due", "debit", "credit", "balance"}
+
+func RenderCSV(s *Statement) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	rows := [][]string{csvHeader, {s.From.Format(time.DateOnly), "OPENING_BALANCE", "", "Opening balance", "", "", "", strconv.Itoa(s.OpeningBalance)}}
+	for _, inst := range s.Installments {
+		description := "Installment " + strings.ToLower(inst.Status)
+		if inst.PaidDate != nil {
+			description += " on " + inst.PaidDate.Format(time.DateOnly)
+		}
+		rows = append(rows, []string{inst.DueDate.Format(time.DateOnly), "INSTALLMENT", strconv.Itoa(inst.Week), description, strconv.Itoa(inst.Amount + inst.LateFee), "", "", ""})
+	}
+	for _, e := range s.Entries {
+		rows = append(rows, []string{e.Date.Format(time.DateOnly), e.Type, csvWeek(e.Week), e.Description, "", csvAmount(e.Debit), csvAmount(e.Credit), strconv.Itoa(e.Balance)})
+	}
+	rows = append(rows, []string{s.To.Format(time.DateOnly), "CLOSING_BALANCE", "", "Closing balance", "", strconv.Itoa(s.TotalDebit), strconv.Itoa(s.TotalCredit), strconv.Itoa(s.ClosingBalance)})
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func csvWeek(week int) string {
+	if week == 0 {
+		return ""
+	}
+	return strconv.Itoa(week)
+}
+
+func csvAmount(amount int) string {
+	if amount == 0 {
+		return ""
+	}
+	return strconv.Itoa(amount)
+}
@@ -0,0 +1,96 @@
+package loanstatement
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatHTML = "html"
+	FormatPDF  = "pdf"
+
+	EntryLoan     = "LOAN"
+	EntryLateFee  = "LATE_FEE"
+	EntryPayment  = "PAYMENT"
+	EntryWriteOff = "WRITE_OFF"
+	EntryRecovery = "RECOVERY"
+
+	InstallmentPaid    = "PAID"
+	InstallmentOverdue = "OVERDUE"
+	InstallmentDue     = "DUE"
+)
+
+var ContentTypes = map[string]string{
+	FormatCSV:  "text/csv; charset=utf-8",
+	FormatHTML: "text/html; charset=utf-8",
+	FormatPDF:  "application/pdf",
+}
+
+type Brand struct {
+	Name    string
+	Address string
+	Phone   string
+	Email   string
+	Website string
+	Color   string
+	Footer  string
+}
+
+type Installment struct {
+	Week     int
+	DueDate  time.Time
+	Amount   int
+	LateFee  int
+	Status   string
+	PaidDate *time.Time
+}
+
+type Entry struct {
+	Date        time.Time
+	Type        string
+	Week        int
+	Description string
+	Debit       int
+	Credit      int
+	Balance     int
+}
+
+type Statement struct {
+	Brand          Brand
+	BillingID      uint
+	CustomerID     uint
+	LoanID         uint
+	CustomerName   string
+	ProductCode    string
+	Status         string
+	From           time.Time
+	To             time.Time
+	GeneratedAt    time.Time
+	OpeningBalance int
+	TotalDebit     int
+	TotalCredit    int
+	ClosingBalance int
+	Installments   []Installment
+	Entries        []Entry
+}
+
+func (s *Statement) FileName(format string) string {
+	return fmt.Sprintf("statement-%d-%s-%s.%s", s.LoanID, s.From.Format("20060102"), s.To.Format("20060102"), format)
+}
+
+func ParseFormat(format string) (string, error) {
+	if format == "" {
+		return FormatPDF, nil
+	}
+	format = strings.ToLower(format)
+	if _, ok := ContentTypes[format]; !ok {
+		return "", fmt.Errorf("Unsupported statement format %q.", format)
+	}
+	return format, nil
+}
+
+func FormatDate(t time.Time) string {
+	return t.Format("02 Jan 2006")
+}
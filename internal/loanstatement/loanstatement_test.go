@@ -0,0 +1,147 @@
+package loanstatement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func testStatement(entries int) *Statement {
+	from := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	paidDate := time.Date(2025, 8, 8, 10, 0, 0, 0, time.UTC)
+	s := &Statement{
+		BillingID:      1,
+		CustomerID:     7,
+		LoanID:         1001,
+		CustomerName:   "Budi (Santoso)",
+		Status:         "ACTIVE",
+		From:           from,
+		To:             time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC),
+		GeneratedAt:    time.Date(2025, 9, 1, 9, 0, 0, 0, time.UTC),
+		OpeningBalance: 5500000,
+		Installments: []Installment{
+			{Week: 1, DueDate: time.Date(2025, 8, 7, 0, 0, 0, 0, time.UTC), Amount: 110000, Status: InstallmentPaid, PaidDate: &paidDate},
+			{Week: 2, DueDate: time.Date(2025, 8, 14, 0, 0, 0, 0, time.UTC), Amount: 110000, LateFee: 5000, Status: InstallmentOverdue},
+		},
+	}
+	balance := s.OpeningBalance
+	for i := range entries {
+		balance -= 110000
+		s.Entries = append(s.Entries, Entry{Date: from.AddDate(0, 0, i), Type: EntryPayment, Week: i + 1, Description: fmt.Sprintf("Payment week %d", i+1), Credit: 110000, Balance: balance})
+		s.TotalCredit += 110000
+	}
+	s.ClosingBalance = balance
+	return s
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatPDF, format)
+	format, err = ParseFormat("CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+	_, err = ParseFormat("xlsx")
+	assert.EqualError(t, err, `Unsupported statement format "xlsx".`)
+}
+
+func TestRenderCSV(t *testing.T) {
+	out, err := RenderCSV(testStatement(1))
+	assert.NoError(t, err)
+	rows, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"date", "type", "week", "description", "due", "debit", "credit", "balance"},
+		{"2025-08-01", "OPENING_BALANCE", "", "Opening balance", "", "", "", "5500000"},
+		{"2025-08-07", "INSTALLMENT", "1", "Installment paid on 2025-08-08", "110000", "", "", ""},
+		{"2025-08-14", "INSTALLMENT", "2", "Installment overdue", "115000", "", "", ""},
+		{"2025-08-01", "PAYMENT", "1", "Payment week 1", "", "", "110000", "5390000"},
+		{"2025-08-31", "CLOSING_BALANCE", "", "Closing balance", "", "0", "110000", "5390000"},
+	}, rows)
+}
+
+func TestRenderer_RendersBrandedHTML(t *testing.T) {
+	r, err := NewRenderer(Brand{Name: "Kredit <Maju>", Color: "#1F4E79", Footer: "Diawasi oleh OJK"}, "")
+	assert.NoError(t, err)
+	out, err := r.Render(FormatHTML, testStatement(1))
+	assert.NoError(t, err)
+	html := string(out)
+	assert.Contains(t, html, "Kredit &lt;Maju&gt;")
+	assert.Contains(t, html, "background: #1F4E79")
+	assert.Contains(t, html, "Rp5.500.000")
+	assert.Contains(t, html, "Payment week 1")
+	assert.Contains(t, html, "Diawasi oleh OJK")
+
+	_, err = NewRenderer(Brand{Name: "Kredit Maju", Color: "blue"}, "")
+	assert.Error(t, err)
+	_, err = NewRenderer(Brand{Name: "Kredit Maju", Color: "#1F4E79"}, "/nonexistent/statement.html")
+	assert.Error(t, err)
+}
+
+func TestRenderPDF(t *testing.T) {
+	s := testStatement(80)
+	s.Brand = Brand{Name: "Kredit Maju", Color: "#1F4E79", Footer: "Diawasi oleh OJK"}
+	out, err := RenderPDF(s)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	assert.NotNil(t, m)
+	xref, _ := strconv.Atoi(string(m[1]))
+	assert.True(t, bytes.HasPrefix(out[xref:], []byte("xref\n")))
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(out[xref:], -1)
+	for i, offset := range offsets {
+		pos, _ := strconv.Atoi(string(offset[1]))
+		assert.True(t, bytes.HasPrefix(out[pos:], fmt.Appendf(nil, "%d 0 obj", i+1)))
+	}
+
+	pages := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(out)
+	count, _ := strconv.Atoi(string(pages[1]))
+	assert.Greater(t, count, 1)
+	assert.Contains(t, string(out), `(Customer  Budi \(Santoso\) \(7\)) Tj`)
+	assert.Contains(t, string(out), "(Payment week 80) Tj")
+	assert.Contains(t, string(out), fmt.Sprintf("(Diawasi oleh OJK  |  Page %d) Tj", count))
+}
+
+func TestRender_Golden(t *testing.T) {
+	s := testStatement(2)
+	s.Status = "WRITTEN_OFF"
+	s.Entries = append(s.Entries,
+		Entry{Date: time.Date(2025, 8, 20, 0, 0, 0, 0, time.UTC), Type: EntryWriteOff, Description: "Write-off of Rp5.280.000: Uncollectible after 120 days past due", Balance: 5280000},
+		Entry{Date: time.Date(2025, 8, 28, 0, 0, 0, 0, time.UTC), Type: EntryRecovery, Description: "Recovery payment", Credit: 80000, Balance: 5200000},
+	)
+	s.TotalCredit += 80000
+	s.ClosingBalance = 5200000
+	r, err := NewRenderer(Brand{Name: "Kredit Maju", Address: "Jl. Sudirman 1, Jakarta", Phone: "021-555-0100", Color: "#1F4E79", Footer: "Diawasi oleh OJK"}, "")
+	assert.NoError(t, err)
+	for _, format := range []string{FormatCSV, FormatHTML, FormatPDF} {
+		t.Run(format, func(t *testing.T) {
+			out, err := r.Render(format, s)
+			assert.NoError(t, err)
+			golden := filepath.Join("testdata", "statement."+format)
+			if *update {
+				assert.NoError(t, os.WriteFile(golden, out, 0o644))
+			}
+			want, err := os.ReadFile(golden)
+			assert.NoError(t, err)
+			assert.Equal(t, string(want), string(out))
+		})
+	}
+}
+
+func TestPDFEncode(t *testing.T) {
+	assert.Equal(t, []byte("Rp ??"), pdfEncode("Rp 中–"))
+	assert.Equal(t, `\(7\) \\`, pdfEscape(`(7) \`))
+}
@@ -0,0 +1,252 @@
+package loanstatement
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/doddeeph/billing-engine/internal/notification"
+)
+
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 40.0
+	pdfFontSize   = 9.0
+	pdfRowHeight  = 14.0
+	pdfBodyBottom = pdfPageHeight - 60
+)
+
+type pdfColumn struct {
+	title string
+	x     float64
+	right bool
+	chars int
+}
+
+var pdfInstallmentColumns = []pdfColumn{
+	{title: "Week", x: pdfMargin},
+	{title: "Due date", x: 90},
+	{title: "Amount", x: 290, right: true},
+	{title: "Late fee", x: 370, right: true},
+	{title: "Status", x: 390},
+	{title: "Paid on", x: 460},
+}
+
+var pdfTransactionColumns = []pdfColumn{
+	{title: "Date", x: pdfMargin},
+	{title: "Description", x: 110, chars: 34},
+	{title: "Debit", x: 385, right: true},
+	{title: "Credit", x: 470, right: true},
+	{title: "Balance", x: pdfPageWidth - pdfMargin, right: true},
+}
+
+type pdfWriter struct {
+	s     *Statement
+	color string
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+func RenderPDF(s *Statement) ([]byte, error) {
+	w := &pdfWriter{s: s, color: pdfColor(s.Brand.Color)}
+	w.newPage()
+	w.header()
+	w.section("Summary")
+	w.row(nil, []string{"Opening balance", notification.FormatRupiah(s.OpeningBalance)})
+	w.row(nil, []string{"Charges", notification.FormatRupiah(s.TotalDebit)})
+	w.row(nil, []string{"Payments and adjustments", notification.FormatRupiah(s.TotalCredit)})
+	w.row(nil, []string{"Closing balance", notification.FormatRupiah(s.ClosingBalance)})
+	w.table("Installments", pdfInstallmentColumns, w.installmentRows())
+	w.table("Transactions", pdfTransactionColumns, w.transactionRows())
+	return w.bytes(), nil
+}
+
+func (w *pdfWriter) header() {
+	brand := w.s.Brand
+	fmt.Fprintf(w.page, "%s rg 0 %.2f %.2f 70 re f\n", w.color, pdfPageHeight-70, pdfPageWidth)
+	w.text(pdfMargin, 36, 18, "1 1 1", brand.Name)
+	w.text(pdfMargin, 56, pdfFontSize, "1 1 1", strings.Join(nonEmpty(brand.Address, brand.Phone, brand.Email, brand.Website), "  |  "))
+	w.y = 96
+	w.text(pdfMargin, w.y, 14, w.color, "Loan Statement")
+	loan := strconv.FormatUint(uint64(w.s.LoanID), 10)
+	if w.s.ProductCode != "" {
+		loan += " " + w.s.ProductCode
+	}
+	w.y += 6
+	for _, line := range []string{
+		fmt.Sprintf("Customer  %s (%d)", w.s.CustomerName, w.s.CustomerID),
+		fmt.Sprintf("Loan      %s  Status %s  Billing %d", loan, w.s.Status, w.s.BillingID),
+		fmt.Sprintf("Period    %s - %s  Generated %s", FormatDate(w.s.From), FormatDate(w.s.To), FormatDate(w.s.GeneratedAt)),
+	} {
+		w.y += pdfRowHeight
+		w.text(pdfMargin, w.y, pdfFontSize, "0 0 0", line)
+	}
+}
+
+func (w *pdfWriter) installmentRows() [][]string {
+	if len(w.s.Installments) == 0 {
+		return [][]string{{"No installments due in this period."}}
+	}
+	rows := make([][]string, 0, len(w.s.Installments))
+	for _, inst := range w.s.Installments {
+		paidOn := ""
+		if inst.PaidDate != nil {
+			paidOn = FormatDate(*inst.PaidDate)
+		}
+		rows = append(rows, []string{strconv.Itoa(inst.Week), FormatDate(inst.DueDate), notification.FormatRupiah(inst.Amount), notification.FormatRupiah(inst.LateFee), inst.Status, paidOn})
+	}
+	return rows
+}
+
+func (w *pdfWriter) transactionRows() [][]string {
+	rows := [][]string{{FormatDate(w.s.From), "Opening balance", "", "", notification.FormatRupiah(w.s.OpeningBalance)}}
+	for _, e := range w.s.Entries {
+		rows = append(rows, []string{FormatDate(e.Date), e.Description, pdfAmount(e.Debit), pdfAmount(e.Credit), notification.FormatRupiah(e.Balance)})
+	}
+	return append(rows, []string{FormatDate(w.s.To), "Closing balance", notification.FormatRupiah(w.s.TotalDebit), notification.FormatRupiah(w.s.TotalCredit), notification.FormatRupiah(w.s.ClosingBalance)})
+}
+
+func (w *pdfWriter) section(title string) {
+	w.y += 28
+	if w.y > pdfBodyBottom-3*pdfRowHeight {
+		w.newPage()
+	}
+	w.text(pdfMargin, w.y, 12, w.color, title)
+	w.y += 4
+}
+
+func (w *pdfWriter) table(title string, columns []pdfColumn, rows [][]string) {
+	w.section(title)
+	w.row(columns, nil)
+	for _, values := range rows {
+		if w.y+pdfRowHeight > pdfBodyBottom {
+			w.newPage()
+			w.row(columns, nil)
+		}
+		w.row(columns, values)
+	}
+}
+
+func (w *pdfWriter) row(columns []pdfColumn, values []string) {
+	w.y += pdfRowHeight
+	if columns == nil {
+		w.text(pdfMargin, w.y, pdfFontSize, "0 0 0", values[0])
+		w.textRight(pdfPageWidth-pdfMargin, w.y, values[1])
+		return
+	}
+	for i, col := range columns {
+		value := col.title
+		if values != nil {
+			if i >= len(values) {
+				break
+			}
+			value = values[i]
+		}
+		if runes := []rune(value); col.chars > 0 && len(runes) > col.chars {
+			value = string(runes[:col.chars-3]) + "..."
+		}
+		if col.right {
+			w.textRight(col.x, w.y, value)
+		} else {
+			w.text(col.x, w.y, pdfFontSize, "0 0 0", value)
+		}
+	}
+}
+
+func (w *pdfWriter) text(x, y, size float64, color, s string) {
+	if s == "" {
+		return
+	}
+	fmt.Fprintf(w.page, "BT /F1 %.0f Tf %s rg %.2f %.2f Td (%s) Tj ET\n", size, color, x, pdfPageHeight-y, pdfEscape(s))
+}
+
+func (w *pdfWriter) textRight(right, y float64, s string) {
+	w.text(right-float64(len(pdfEncode(s)))*pdfFontSize*0.6, y, pdfFontSize, "0 0 0", s)
+}
+
+func (w *pdfWriter) newPage() {
+	w.page = &bytes.Buffer{}
+	w.pages = append(w.pages, w.page)
+	w.y = 40
+	w.text(pdfMargin, pdfPageHeight-30, 8, "0.4 0.4 0.4", strings.Join(nonEmpty(w.s.Brand.Footer, fmt.Sprintf("Page %d", len(w.pages))), "  |  "))
+}
+
+func (w *pdfWriter) bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	out.WriteString("%PDF-1.4\n")
+	kids := make([]string, len(w.pages))
+	for i := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(w.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	for i, page := range w.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 5+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", page.Len(), page.String()))
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+func pdfColor(hex string) string {
+	var c [3]string
+	for i := range c {
+		v, _ := strconv.ParseUint(hex[1+2*i:3+2*i], 16, 8)
+		c[i] = strconv.FormatFloat(float64(v)/255, 'f', 3, 64)
+	}
+	return strings.Join(c[:], " ")
+}
+
+func pdfEncode(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r >= 32 && r < 127 || r >= 160 && r <= 255 {
+			b = append(b, byte(r))
+		} else {
+			b = append(b, '?')
+		}
+	}
+	return b
+}
+
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, c := range pdfEncode(s) {
+		if c == '(' || c == ')' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func pdfAmount(amount int) string {
+	if amount == 0 {
+		return ""
+	}
+	return notification.FormatRupiah(amount)
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,63 @@
+package loanstatement
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"os"
+	"regexp"
+
+	"github.com/doddeeph/billing-engine/internal/notification"
+)
+
+//go:embed templates/statement.html
+var defaultTemplates embed.FS
+
+var brandColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type Renderer struct {
+	brand Brand
+	html  *template.Template
+}
+
+func NewRenderer(brand Brand, templatePath string) (*Renderer, error) {
+	if !brandColorPattern.MatchString(brand.Color) {
+		return nil, fmt.Errorf("invalid statement brand color %q", brand.Color)
+	}
+	var src []byte
+	var err error
+	if templatePath != "" {
+		src, err = os.ReadFile(templatePath)
+	} else {
+		src, err = defaultTemplates.ReadFile("templates/statement.html")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("no statement template: %s", err.Error())
+	}
+	tmpl, err := template.New("statement").Funcs(template.FuncMap{
+		"rupiah": notification.FormatRupiah,
+		"date":   FormatDate,
+	}).Parse(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("invalid statement template: %s", err.Error())
+	}
+	return &Renderer{brand: brand, html: tmpl}, nil
+}
+
+func (r *Renderer) Render(format string, s *Statement) ([]byte, error) {
+	s.Brand = r.brand
+	switch format {
+	case FormatCSV:
+		return RenderCSV(s)
+	case FormatHTML:
+		var b bytes.Buffer
+		if err := r.html.Execute(&b, s); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	case FormatPDF:
+		return RenderPDF(s)
+	}
+	return nil, fmt.Errorf("Unsupported statement format %q.", format)
+}
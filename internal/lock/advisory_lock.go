@@ -0,0 +1,66 @@
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+
+	"gorm.io/gorm"
+)
+
+type Lock interface {
+	Release(ctx context.Context) error
+}
+
+type Locker interface {
+	TryLock(ctx context.Context, name string) (Lock, bool, error)
+}
+
+type advisoryLocker struct {
+	db *gorm.DB
+}
+
+type advisoryLock struct {
+	conn *sql.Conn
+	key  int64
+}
+
+func NewAdvisoryLocker(db *gorm.DB) Locker {
+	return &advisoryLocker{db}
+}
+
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+// TryLock takes a session-level advisory lock on a dedicated connection, so the lock is
+// released by Postgres when the connection closes, including when the instance crashes.
+func (l *advisoryLocker) TryLock(ctx context.Context, name string) (Lock, bool, error) {
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	key := lockKey(name)
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+	return &advisoryLock{conn: conn, key: key}, true, nil
+}
+
+func (l *advisoryLock) Release(ctx context.Context) error {
+	defer l.conn.Close()
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	return err
+}
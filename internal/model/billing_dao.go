@@ -1,13 +1,33 @@
 package model
 
+import "time"
+
+const (
+	BillingStatusActive     = "ACTIVE"
+	BillingStatusClosed     = "CLOSED"
+	BillingStatusWrittenOff = "WRITTEN_OFF"
+)
+
 type Billing struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	CustomerID   uint      `gorm:"not null" json:"customerId"`
-	LoanID       uint      `gorm:"uniqueIndex:idx_loan_id;not null" json:"loanId"`
-	LoanAmount   int       `gorm:"not null" json:"loanAmount"`
-	LoanWeeks    int       `gorm:"not null" json:"loanWeeks"`
-	LoanInterest int       `gorm:"not null" json:"loanInterest"`
-	Outstanding  int       `gorm:"not null" json:"outstanding"`
-	Payments     []Payment `gorm:"foreignKey:BillingID"`
+	ID                  uint             `gorm:"primaryKey" json:"id"`
+	CustomerID          uint             `gorm:"index;not null" json:"customerId"`
+	LoanID              uint             `gorm:"uniqueIndex:idx_loan_id;not null" json:"loanId"`
+	LoanAmount          int              `gorm:"not null" json:"loanAmount"`
+	LoanWeeks           int              `gorm:"not null" json:"loanWeeks"`
+	LoanInterest        int              `gorm:"not null" json:"loanInterest"`
+	Outstanding         int              `gorm:"not null" json:"outstanding"`
+	ProductCode         string           `gorm:"index" json:"productCode"`
+	DelinquencyPolicyID *uint            `json:"delinquencyPolicyId"`
+	DelinquencyStatus   string           `gorm:"not null;default:CURRENT" json:"delinquencyStatus"`
+	Status              string           `gorm:"not null;default:ACTIVE" json:"status"`
+	WrittenOffAmount    int              `gorm:"not null;default:0" json:"writtenOffAmount"`
+	RecoveredAmount     int              `gorm:"not null;default:0" json:"recoveredAmount"`
+	WriteOffReason      string           `json:"writeOffReason,omitempty"`
+	WrittenOffAt        *time.Time       `json:"writtenOffAt"`
+	Customer            *Customer        `json:"customer,omitempty"`
+	Payments            []Payment        `gorm:"foreignKey:BillingID"`
+	Freezes             []BillingFreeze  `gorm:"foreignKey:BillingID" json:"freezes"`
+	Promises            []PaymentPromise `gorm:"foreignKey:BillingID" json:"promises"`
+	VirtualAccounts     []VirtualAccount `gorm:"foreignKey:BillingID" json:"virtualAccounts"`
 	CommonModel
 }
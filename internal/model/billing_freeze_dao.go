@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+type BillingFreeze struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	BillingID    uint      `gorm:"index;not null" json:"billingId"`
+	StartDate    time.Time `gorm:"not null" json:"startDate"`
+	EndDate      time.Time `gorm:"not null" json:"endDate"`
+	Reason       string    `gorm:"not null" json:"reason"`
+	ShiftedWeeks int       `gorm:"not null" json:"shiftedWeeks"`
+	CommonModel
+}
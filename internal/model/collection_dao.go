@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+const (
+	CollectionCaseStatusOpen   = "OPEN"
+	CollectionCaseStatusClosed = "CLOSED"
+
+	ContactChannelCall     = "CALL"
+	ContactChannelSMS      = "SMS"
+	ContactChannelEmail    = "EMAIL"
+	ContactChannelWhatsapp = "WHATSAPP"
+	ContactChannelVisit    = "VISIT"
+
+	ContactOutcomeNoAnswer      = "NO_ANSWER"
+	ContactOutcomeUnreachable   = "UNREACHABLE"
+	ContactOutcomeWrongNumber   = "WRONG_NUMBER"
+	ContactOutcomeContacted     = "CONTACTED"
+	ContactOutcomePromisedToPay = "PROMISED_TO_PAY"
+	ContactOutcomeRefusedToPay  = "REFUSED_TO_PAY"
+	ContactOutcomeDisputed      = "DISPUTED"
+	ContactOutcomeCallback      = "CALLBACK_REQUESTED"
+)
+
+type CollectionAgent struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Name           string     `gorm:"not null" json:"name"`
+	Email          string     `json:"email"`
+	Region         string     `gorm:"index;not null;default:''" json:"region"`
+	Active         bool       `gorm:"not null;default:true" json:"active"`
+	LastAssignedAt *time.Time `json:"lastAssignedAt"`
+	CommonModel
+}
+
+type CollectionCase struct {
+	ID            uint             `gorm:"primaryKey" json:"id"`
+	BillingID     uint             `gorm:"index;not null;uniqueIndex:idx_collection_cases_open_billing,where:status = 'OPEN'" json:"billingId"`
+	CustomerID    uint             `gorm:"index;not null" json:"customerId"`
+	AgentID       *uint            `gorm:"index" json:"agentId"`
+	Region        string           `gorm:"not null;default:''" json:"region"`
+	Status        string           `gorm:"index;not null;default:OPEN" json:"status"`
+	OpenedAt      time.Time        `gorm:"not null" json:"openedAt"`
+	AssignedAt    *time.Time       `json:"assignedAt"`
+	ClosedAt      *time.Time       `json:"closedAt"`
+	CloseReason   string           `json:"closeReason,omitempty"`
+	LastContactAt *time.Time       `json:"lastContactAt"`
+	LastOutcome   string           `json:"lastOutcome,omitempty"`
+	Attempts      []ContactAttempt `gorm:"foreignKey:CaseID" json:"attempts,omitempty"`
+	Billing       *Billing         `json:"-"`
+	CommonModel
+}
+
+type ContactAttempt struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CaseID      uint      `gorm:"index;not null" json:"caseId"`
+	AgentID     *uint     `gorm:"index" json:"agentId"`
+	Channel     string    `gorm:"not null" json:"channel"`
+	Outcome     string    `gorm:"not null" json:"outcome"`
+	Notes       string    `json:"notes,omitempty"`
+	AttemptedAt time.Time `gorm:"not null" json:"attemptedAt"`
+	CommonModel
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+type Customer struct {
+	ID                uint       `gorm:"primaryKey;autoIncrement:false" json:"id"`
+	Name              string     `json:"name"`
+	Phone             string     `gorm:"index" json:"phone"`
+	Email             string     `gorm:"index" json:"email"`
+	PreferredLanguage string     `gorm:"not null;default:''" json:"preferredLanguage"`
+	Timezone          string     `gorm:"not null;default:''" json:"timezone"`
+	Region            string     `gorm:"not null;default:''" json:"region"`
+	SMSOptIn          bool       `gorm:"not null;default:false" json:"smsOptIn"`
+	EmailOptIn        bool       `gorm:"not null;default:false" json:"emailOptIn"`
+	WhatsappOptIn     bool       `gorm:"not null;default:false" json:"whatsappOptIn"`
+	SyncedAt          *time.Time `json:"syncedAt"`
+	CommonModel
+}
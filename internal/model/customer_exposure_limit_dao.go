@@ -0,0 +1,7 @@
+package model
+
+type CustomerExposureLimit struct {
+	CustomerID    uint `gorm:"primaryKey;autoIncrement:false" json:"customerId"`
+	ExposureLimit int  `gorm:"not null" json:"exposureLimit"`
+	CommonModel
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const (
+	DelinquencyStatusCurrent    = "CURRENT"
+	DelinquencyStatusDelinquent = "DELINQUENT"
+
+	DelinquencySourceEvaluator = "EVALUATOR"
+	DelinquencySourcePayment   = "PAYMENT"
+)
+
+type DelinquencyHistory struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	BillingID  uint      `gorm:"index;not null" json:"billingId"`
+	FromStatus string    `gorm:"not null" json:"fromStatus"`
+	ToStatus   string    `gorm:"not null" json:"toStatus"`
+	PolicyCode string    `gorm:"not null" json:"policyCode"`
+	Rule       string    `json:"rule"`
+	Reason     string    `json:"reason"`
+	Source     string    `gorm:"not null" json:"source"`
+	ChangedAt  time.Time `gorm:"not null" json:"changedAt"`
+	CommonModel
+}
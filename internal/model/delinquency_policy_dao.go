@@ -0,0 +1,24 @@
+package model
+
+const (
+	DelinquencyRuleConsecutiveMisses = "CONSECUTIVE_MISSES"
+	DelinquencyRuleWindowMisses      = "WINDOW_MISSES"
+	DelinquencyRuleDPDThreshold      = "DPD_THRESHOLD"
+
+	CureRuleArrearsCleared = "ARREARS_CLEARED"
+	CureRuleOnTimePayments = "ON_TIME_PAYMENTS"
+
+	DefaultDelinquencyPolicyCode = "DEFAULT"
+)
+
+type DelinquencyPolicy struct {
+	ID           uint    `gorm:"primaryKey" json:"id"`
+	Code         string  `gorm:"uniqueIndex:idx_delinquency_policy_code;not null" json:"code"`
+	ProductCode  *string `gorm:"uniqueIndex:idx_delinquency_policy_product_code" json:"productCode"`
+	Rule         string  `gorm:"not null" json:"rule"`
+	Threshold    int     `gorm:"not null" json:"threshold"`
+	WindowWeeks  int     `gorm:"not null;default:0" json:"windowWeeks"`
+	CureRule     string  `gorm:"not null;default:ARREARS_CLEARED" json:"cureRule"`
+	CurePayments int     `gorm:"not null;default:0" json:"curePayments"`
+	CommonModel
+}
@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+const (
+	DunningActionSMS      = "SMS"
+	DunningActionEmail    = "EMAIL"
+	DunningActionWhatsapp = "WHATSAPP"
+	DunningActionCall     = "CALL"
+	DunningActionLetter   = "LETTER"
+
+	DunningStatusSent    = "SENT"
+	DunningStatusCreated = "CREATED"
+	DunningStatusSkipped = "SKIPPED"
+	DunningStatusFailed  = "FAILED"
+	DunningStatusStopped = "STOPPED"
+
+	DefaultDunningStrategyCode = "DEFAULT"
+)
+
+type DunningStrategy struct {
+	ID          uint          `gorm:"primaryKey" json:"id"`
+	Code        string        `gorm:"uniqueIndex:idx_dunning_strategy_code;not null" json:"code"`
+	ProductCode *string       `gorm:"uniqueIndex:idx_dunning_strategy_product_code" json:"productCode"`
+	Steps       []DunningStep `gorm:"foreignKey:StrategyID" json:"steps"`
+	CommonModel
+}
+
+type DunningStep struct {
+	ID          uint   `gorm:"primaryKey" json:"-"`
+	StrategyID  uint   `gorm:"index;not null" json:"-"`
+	DaysPastDue int    `gorm:"not null" json:"daysPastDue"`
+	Action      string `gorm:"not null" json:"action"`
+	CommonModel
+}
+
+type DunningLog struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	BillingID       uint      `gorm:"index;not null" json:"billingId"`
+	Cycle           int       `gorm:"not null" json:"cycle"`
+	StrategyCode    string    `gorm:"not null" json:"strategyCode"`
+	StepDaysPastDue int       `gorm:"not null;default:0" json:"stepDaysPastDue"`
+	Action          string    `json:"action,omitempty"`
+	DaysPastDue     int       `gorm:"not null" json:"daysPastDue"`
+	OverdueAmount   int       `gorm:"not null;default:0" json:"overdueAmount"`
+	Status          string    `gorm:"not null" json:"status"`
+	Recipient       string    `json:"recipient,omitempty"`
+	Subject         string    `json:"subject,omitempty"`
+	Body            string    `json:"body,omitempty"`
+	ProviderRef     string    `json:"providerRef,omitempty"`
+	Reason          string    `json:"reason,omitempty"`
+	TriggeredAt     time.Time `gorm:"not null" json:"triggeredAt"`
+	CommonModel
+}
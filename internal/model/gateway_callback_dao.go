@@ -0,0 +1,29 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	GatewayCallbackStatusProcessed = "PROCESSED"
+	GatewayCallbackStatusFailed    = "FAILED"
+	GatewayCallbackStatusIgnored   = "IGNORED"
+)
+
+type GatewayCallback struct {
+	ID         uint            `gorm:"primaryKey" json:"id"`
+	Provider   string          `gorm:"not null;uniqueIndex:idx_gateway_callbacks_provider_external_id" json:"provider"`
+	ExternalID string          `gorm:"not null;uniqueIndex:idx_gateway_callbacks_provider_external_id" json:"externalId"`
+	BillingID  uint            `gorm:"index" json:"billingId"`
+	Amount     int             `gorm:"not null" json:"amount"`
+	Status     string          `gorm:"index;not null" json:"status"`
+	Error      string          `json:"error,omitempty"`
+	PaymentID  *uint           `json:"paymentId"`
+	RecoveryID *uint           `json:"recoveryId"`
+	Payload    json.RawMessage `gorm:"type:jsonb;not null" json:"payload"`
+	PaidAt     *time.Time      `json:"paidAt"`
+	ReceivedAt time.Time       `gorm:"not null" json:"receivedAt"`
+	Attempts   int             `gorm:"not null;default:1" json:"attempts"`
+	CommonModel
+}
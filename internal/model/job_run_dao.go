@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+const (
+	JobRunStatusRunning = "RUNNING"
+	JobRunStatusSuccess = "SUCCESS"
+	JobRunStatusFailed  = "FAILED"
+)
+
+type JobRun struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	JobName    string     `gorm:"index;not null;uniqueIndex:idx_job_runs_completed_slot,priority:1,where:status = 'SUCCESS'" json:"jobName"`
+	Slot       *time.Time `gorm:"uniqueIndex:idx_job_runs_completed_slot,priority:2" json:"slot,omitempty"`
+	Status     string     `gorm:"not null" json:"status"`
+	StartedAt  time.Time  `gorm:"not null" json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt"`
+	Processed  int        `gorm:"not null;default:0" json:"processed"`
+	Failed     int        `gorm:"not null;default:0" json:"failed"`
+	Error      string     `json:"error,omitempty"`
+	CommonModel
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	LoanImportStatusPending   = "PENDING"
+	LoanImportStatusRunning   = "RUNNING"
+	LoanImportStatusCompleted = "COMPLETED"
+
+	LoanImportRowStatusPending  = "PENDING"
+	LoanImportRowStatusImported = "IMPORTED"
+	LoanImportRowStatusSkipped  = "SKIPPED"
+	LoanImportRowStatusFailed   = "FAILED"
+)
+
+type LoanImport struct {
+	ID           uint            `gorm:"primaryKey" json:"id"`
+	Status       string          `gorm:"index;not null" json:"status"`
+	TotalRows    int             `gorm:"not null;default:0" json:"totalRows"`
+	ImportedRows int             `gorm:"not null;default:0" json:"importedRows"`
+	SkippedRows  int             `gorm:"not null;default:0" json:"skippedRows"`
+	FailedRows   int             `gorm:"not null;default:0" json:"failedRows"`
+	PendingRows  int             `gorm:"not null;default:0" json:"pendingRows"`
+	StartedAt    *time.Time      `json:"startedAt"`
+	FinishedAt   *time.Time      `json:"finishedAt"`
+	Rows         []LoanImportRow `gorm:"foreignKey:ImportID" json:"rows,omitempty"`
+	CommonModel
+}
+
+type LoanImportRow struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	ImportID  uint            `gorm:"index;not null" json:"importId"`
+	RowNo     int             `gorm:"not null" json:"rowNo"`
+	LoanID    uint            `gorm:"index" json:"loanId"`
+	Status    string          `gorm:"index;not null" json:"status"`
+	Reason    string          `json:"reason,omitempty"`
+	BillingID *uint           `json:"billingId"`
+	Payload   json.RawMessage `gorm:"type:jsonb;not null" json:"-"`
+	CommonModel
+}
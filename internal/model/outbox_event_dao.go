@@ -0,0 +1,24 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	OutboxStatusPending   = "PENDING"
+	OutboxStatusPublished = "PUBLISHED"
+)
+
+type OutboxEvent struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	BillingID   uint            `gorm:"index;not null" json:"billingId"`
+	EventType   string          `gorm:"not null" json:"eventType"`
+	Payload     json.RawMessage `gorm:"type:jsonb;not null" json:"payload"`
+	Status      string          `gorm:"index;not null;default:PENDING" json:"status"`
+	Attempts    int             `gorm:"not null;default:0" json:"attempts"`
+	LastError   string          `json:"lastError,omitempty"`
+	OccurredAt  time.Time       `gorm:"not null" json:"occurredAt"`
+	PublishedAt *time.Time      `json:"publishedAt"`
+	CommonModel
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+const (
+	PaymentBatchFormatCSV   = "CSV"
+	PaymentBatchFormatJSONL = "JSONL"
+
+	PaymentBatchStatusPending   = "PENDING"
+	PaymentBatchStatusRunning   = "RUNNING"
+	PaymentBatchStatusCompleted = "COMPLETED"
+
+	PaymentBatchRowStatusPending   = "PENDING"
+	PaymentBatchRowStatusApplied   = "APPLIED"
+	PaymentBatchRowStatusValid     = "VALID"
+	PaymentBatchRowStatusDuplicate = "DUPLICATE"
+	PaymentBatchRowStatusRejected  = "REJECTED"
+)
+
+type PaymentBatch struct {
+	ID             uint              `gorm:"primaryKey" json:"id"`
+	Format         string            `gorm:"not null" json:"format"`
+	DryRun         bool              `gorm:"not null;default:false" json:"dryRun"`
+	Status         string            `gorm:"index;not null" json:"status"`
+	TotalRows      int               `gorm:"not null;default:0" json:"totalRows"`
+	AppliedRows    int               `gorm:"not null;default:0" json:"appliedRows"`
+	ValidRows      int               `gorm:"not null;default:0" json:"validRows"`
+	DuplicateRows  int               `gorm:"not null;default:0" json:"duplicateRows"`
+	RejectedRows   int               `gorm:"not null;default:0" json:"rejectedRows"`
+	StartedAt      *time.Time        `json:"startedAt"`
+	FinishedAt     *time.Time        `json:"finishedAt"`
+	LeaseExpiresAt *time.Time        `json:"-"`
+	Rows           []PaymentBatchRow `gorm:"foreignKey:BatchID" json:"rows,omitempty"`
+	CommonModel
+}
+
+type PaymentBatchRow struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	BatchID    uint   `gorm:"index;not null" json:"batchId"`
+	RowNo      int    `gorm:"not null" json:"rowNo"`
+	BillingID  uint   `json:"billingId"`
+	Week       int    `json:"week"`
+	Amount     int    `json:"amount"`
+	Reference  string `gorm:"index;uniqueIndex:idx_payment_batch_rows_applied_reference,where:status = 'APPLIED' AND deleted_at IS NULL" json:"reference"`
+	Status     string `gorm:"index;not null" json:"status"`
+	Reason     string `json:"reason,omitempty"`
+	PaymentID  *uint  `json:"paymentId"`
+	RecoveryID *uint  `json:"recoveryId"`
+	CommonModel
+}
@@ -8,8 +8,11 @@ type Payment struct {
 	Amount    int        `gorm:"not null" json:"amount"`
 	Week      int        `gorm:"not null" json:"week"`
 	Paid      bool       `gorm:"default:false" json:"paid"`
+	Overdue   bool       `gorm:"default:false" json:"overdue"`
+	LateFee   int        `gorm:"not null;default:0" json:"lateFee"`
 	StartDate time.Time  `gorm:"not null" json:"startDate"`
 	DueDate   time.Time  `gorm:"not null" json:"dueDate"`
 	PaidDate  *time.Time `json:"paidDate"`
+	Revision  int        `gorm:"not null;default:0" json:"revision"`
 	CommonModel
 }
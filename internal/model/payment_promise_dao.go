@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const (
+	PaymentPromiseStatusPending   = "PENDING"
+	PaymentPromiseStatusFulfilled = "FULFILLED"
+	PaymentPromiseStatusBroken    = "BROKEN"
+	PaymentPromiseStatusCancelled = "CANCELLED"
+)
+
+type PaymentPromise struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	BillingID   uint       `gorm:"index;not null;uniqueIndex:idx_payment_promises_pending_billing,where:status = 'PENDING'" json:"billingId"`
+	Amount      int        `gorm:"not null" json:"amount"`
+	PromiseDate time.Time  `gorm:"not null" json:"promiseDate"`
+	ExpiresAt   time.Time  `gorm:"index;not null" json:"expiresAt"`
+	PaidAmount  int        `gorm:"not null;default:0" json:"paidAmount"`
+	Status      string     `gorm:"index;not null;default:PENDING" json:"status"`
+	Notes       string     `json:"notes,omitempty"`
+	PromisedAt  time.Time  `gorm:"not null" json:"promisedAt"`
+	ResolvedAt  *time.Time `json:"resolvedAt"`
+	CommonModel
+}
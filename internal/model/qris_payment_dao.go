@@ -0,0 +1,12 @@
+package model
+
+type QRISPayment struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	Reference  string `gorm:"uniqueIndex;not null" json:"reference"`
+	BillingID  uint   `gorm:"index;not null" json:"billingId"`
+	CustomerID uint   `gorm:"not null" json:"customerId"`
+	Amount     int    `gorm:"not null" json:"amount"`
+	Week       int    `json:"week,omitempty"`
+	Payload    string `gorm:"not null" json:"payload"`
+	CommonModel
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type Recovery struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	BillingID   uint      `gorm:"index;not null" json:"billingId"`
+	Amount      int       `gorm:"not null" json:"amount"`
+	RecoveredAt time.Time `gorm:"not null" json:"recoveredAt"`
+	CommonModel
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+const (
+	ReminderStatusScheduled = "SCHEDULED"
+	ReminderStatusSent      = "SENT"
+	ReminderStatusFailed    = "FAILED"
+	ReminderStatusCancelled = "CANCELLED"
+)
+
+type Reminder struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	BillingID     uint       `gorm:"index;not null" json:"billingId"`
+	PaymentID     uint       `gorm:"not null;uniqueIndex:idx_reminders_payment_schedule" json:"paymentId"`
+	CustomerID    uint       `gorm:"index;not null" json:"customerId"`
+	Week          int        `gorm:"not null" json:"week"`
+	DueDate       time.Time  `gorm:"not null;uniqueIndex:idx_reminders_payment_schedule" json:"dueDate"`
+	DayOffset     int        `gorm:"not null;uniqueIndex:idx_reminders_payment_schedule" json:"dayOffset"`
+	Channel       string     `gorm:"not null;uniqueIndex:idx_reminders_payment_schedule" json:"channel"`
+	Language      string     `gorm:"not null" json:"language"`
+	Status        string     `gorm:"index;not null;default:SCHEDULED" json:"status"`
+	ScheduledAt   time.Time  `gorm:"not null" json:"scheduledAt"`
+	NextAttemptAt time.Time  `gorm:"index;not null" json:"nextAttemptAt"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	Recipient     string     `json:"recipient,omitempty"`
+	Subject       string     `json:"subject,omitempty"`
+	Body          string     `json:"body,omitempty"`
+	ProviderRef   string     `json:"providerRef,omitempty"`
+	LastError     string     `json:"lastError,omitempty"`
+	SentAt        *time.Time `json:"sentAt"`
+	CommonModel
+}
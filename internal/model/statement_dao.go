@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+const (
+	StatementLineStatusPending    = "PENDING"
+	StatementLineStatusMatched    = "MATCHED"
+	StatementLineStatusApplied    = "APPLIED"
+	StatementLineStatusMismatched = "MISMATCHED"
+	StatementLineStatusUnmatched  = "UNMATCHED"
+	StatementLineStatusIgnored    = "IGNORED"
+
+	StatementMatchVirtualAccount = "VIRTUAL_ACCOUNT"
+	StatementMatchQRIS           = "QRIS"
+	StatementMatchReference      = "REFERENCE"
+	StatementMatchAmount         = "AMOUNT"
+)
+
+type StatementImport struct {
+	ID                 uint            `gorm:"primaryKey" json:"id"`
+	Format             string          `gorm:"not null" json:"format"`
+	FileName           string          `json:"fileName"`
+	Checksum           string          `gorm:"uniqueIndex;not null" json:"checksum"`
+	AccountNumber      string          `json:"accountNumber"`
+	StatementReference string          `json:"statementReference"`
+	TotalLines         int             `gorm:"not null;default:0" json:"totalLines"`
+	MatchedLines       int             `gorm:"not null;default:0" json:"matchedLines"`
+	AppliedLines       int             `gorm:"not null;default:0" json:"appliedLines"`
+	MismatchedLines    int             `gorm:"not null;default:0" json:"mismatchedLines"`
+	UnmatchedLines     int             `gorm:"not null;default:0" json:"unmatchedLines"`
+	IgnoredLines       int             `gorm:"not null;default:0" json:"ignoredLines"`
+	Lines              []StatementLine `gorm:"foreignKey:ImportID" json:"lines,omitempty"`
+	CommonModel
+}
+
+type StatementLine struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ImportID    uint      `gorm:"index;not null" json:"importId"`
+	LineNo      int       `gorm:"not null" json:"lineNo"`
+	ValueDate   time.Time `gorm:"not null" json:"valueDate"`
+	Direction   string    `gorm:"not null" json:"direction"`
+	Amount      int       `gorm:"not null" json:"amount"`
+	Reference   string    `json:"reference"`
+	Description string    `json:"description"`
+	Status      string    `gorm:"index;not null" json:"status"`
+	MatchedBy   string    `json:"matchedBy,omitempty"`
+	BillingID   *uint     `gorm:"index" json:"billingId"`
+	PaymentID   *uint     `gorm:"index" json:"paymentId"`
+	RecoveryID  *uint     `json:"recoveryId"`
+	Note        string    `json:"note,omitempty"`
+	CommonModel
+}
@@ -0,0 +1,10 @@
+package model
+
+type VirtualAccount struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	Number     string `gorm:"uniqueIndex;not null" json:"number"`
+	BankCode   string `gorm:"not null;uniqueIndex:idx_virtual_accounts_billing_bank" json:"bankCode"`
+	BillingID  uint   `gorm:"not null;uniqueIndex:idx_virtual_accounts_billing_bank" json:"billingId"`
+	CustomerID uint   `gorm:"index;not null" json:"customerId"`
+	CommonModel
+}
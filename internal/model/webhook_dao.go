@@ -0,0 +1,37 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WebhookDeliveryStatusPending   = "PENDING"
+	WebhookDeliveryStatusDelivered = "DELIVERED"
+	WebhookDeliveryStatusDead      = "DEAD"
+)
+
+type WebhookSubscription struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	URL        string `gorm:"not null" json:"url"`
+	EventTypes string `json:"eventTypes"`
+	Secret     string `gorm:"not null" json:"-"`
+	Active     bool   `gorm:"not null;default:true" json:"active"`
+	CommonModel
+}
+
+type WebhookDelivery struct {
+	ID             uint                `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint                `gorm:"not null;uniqueIndex:idx_webhook_deliveries_subscription_event" json:"subscriptionId"`
+	OutboxEventID  uint                `gorm:"not null;uniqueIndex:idx_webhook_deliveries_subscription_event" json:"outboxEventId"`
+	EventType      string              `gorm:"not null" json:"eventType"`
+	Payload        json.RawMessage     `gorm:"type:jsonb;not null" json:"payload"`
+	Status         string              `gorm:"index;not null;default:PENDING" json:"status"`
+	Attempts       int                 `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time           `gorm:"index;not null" json:"nextAttemptAt"`
+	LastStatusCode int                 `json:"lastStatusCode,omitempty"`
+	LastError      string              `json:"lastError,omitempty"`
+	DeliveredAt    *time.Time          `json:"deliveredAt"`
+	Subscription   WebhookSubscription `gorm:"foreignKey:SubscriptionID" json:"-"`
+	CommonModel
+}
@@ -0,0 +1,82 @@
+package notification
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const AdapterSMTP = "smtp"
+
+type emailChannel struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+func NewEmailChannel(host string, port int, username, password, from string) Channel {
+	return &emailChannel{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+func (c *emailChannel) Send(ctx context.Context, msg Message) (string, error) {
+	from, err := mail.ParseAddress(c.from)
+	if err != nil {
+		return "", fmt.Errorf("invalid sender address %q", c.from)
+	}
+	messageID, err := newMessageID(from.Address)
+	if err != nil {
+		return "", err
+	}
+	var auth smtp.Auth
+	if c.username != "" {
+		auth = smtp.PlainAuth("", c.username, c.password, c.host)
+	}
+	if err := smtp.SendMail(c.addr, auth, from.Address, []string{msg.To}, buildEmail(from.String(), msg, messageID, time.Now())); err != nil {
+		return "", err
+	}
+	return messageID, nil
+}
+
+func buildEmail(from string, msg Message, messageID string, date time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("Message-ID: " + messageID + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+func newMessageID(address string) (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	domain := "localhost"
+	if at := strings.LastIndex(address, "@"); at >= 0 {
+		domain = address[at+1:]
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">", nil
+}
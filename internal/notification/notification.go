@@ -0,0 +1,113 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+)
+
+const (
+	ChannelSMS      = "SMS"
+	ChannelEmail    = "EMAIL"
+	ChannelWhatsapp = "WHATSAPP"
+
+	AdapterLog  = "log"
+	AdapterFile = "file"
+)
+
+type Message struct {
+	Reference string
+	Channel   string
+	To        string
+	Subject   string
+	Body      string
+}
+
+type Channel interface {
+	Send(ctx context.Context, msg Message) (string, error)
+}
+
+func Channels() []string {
+	return []string{ChannelSMS, ChannelEmail, ChannelWhatsapp}
+}
+
+func NewChannels(cfg *config.NotificationConfig) (map[string]Channel, error) {
+	client := &http.Client{Timeout: cfg.Timeout}
+	var file *fileChannel
+	adapters := map[string]string{
+		ChannelSMS:      cfg.SMSAdapter,
+		ChannelEmail:    cfg.EmailAdapter,
+		ChannelWhatsapp: cfg.WhatsappAdapter,
+	}
+	channels := make(map[string]Channel, len(adapters))
+	for name, adapter := range adapters {
+		switch {
+		case adapter == AdapterLog:
+			channels[name] = NewLogChannel()
+		case adapter == AdapterFile:
+			if file == nil {
+				file = NewFileChannel(cfg.FilePath)
+			}
+			channels[name] = file
+		case name == ChannelSMS && adapter == AdapterSMSHTTP:
+			channels[name] = NewSMSChannel(client, cfg.SMSURL, cfg.SMSAPIKey, cfg.SMSSender)
+		case name == ChannelEmail && adapter == AdapterSMTP:
+			channels[name] = NewEmailChannel(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+		case name == ChannelWhatsapp && adapter == AdapterWhatsappCloud:
+			channels[name] = NewWhatsappChannel(client, cfg.WhatsappURL, cfg.WhatsappPhoneNumberID, cfg.WhatsappToken)
+		default:
+			return nil, fmt.Errorf("unknown %s notification adapter %q", name, adapter)
+		}
+	}
+	return channels, nil
+}
+
+type logChannel struct{}
+
+func NewLogChannel() Channel {
+	return &logChannel{}
+}
+
+func (c *logChannel) Send(ctx context.Context, msg Message) (string, error) {
+	log.Printf("Notification %s via %s to %s: %s %s", msg.Reference, msg.Channel, msg.To, msg.Subject, msg.Body)
+	return msg.Reference, nil
+}
+
+type fileChannel struct {
+	path string
+	mu   sync.Mutex
+}
+
+type fileRecord struct {
+	SentAt time.Time `json:"sentAt"`
+	Message
+}
+
+func NewFileChannel(path string) *fileChannel {
+	return &fileChannel{path: path}
+}
+
+func (c *fileChannel) Send(ctx context.Context, msg Message) (string, error) {
+	line, err := json.Marshal(fileRecord{SentAt: time.Now(), Message: msg})
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f, err := os.OpenFile(c.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return "", err
+	}
+	return msg.Reference, nil
+}
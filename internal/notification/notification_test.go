@@ -0,0 +1,162 @@
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTemplateData() TemplateData {
+	return TemplateData{
+		CustomerName:    "Budi",
+		LoanID:          1001,
+		Week:            3,
+		Amount:          FormatRupiah(110000),
+		DueDate:         "17 Aug 2025",
+		Days:            2,
+		VirtualAccounts: []VirtualAccount{{BankCode: "BCA", Number: "3935800000000420"}},
+	}
+}
+
+func TestFormatRupiah(t *testing.T) {
+	assert.Equal(t, "Rp0", FormatRupiah(0))
+	assert.Equal(t, "Rp999", FormatRupiah(999))
+	assert.Equal(t, "Rp110.000", FormatRupiah(110000))
+	assert.Equal(t, "Rp5.500.000", FormatRupiah(5500000))
+	assert.Equal(t, "-Rp1.000", FormatRupiah(-1000))
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2025, 8, 17, 23, 59, 59, 0, time.UTC)
+	assert.Equal(t, "17 Agustus 2025", FormatDate(date, "id"))
+	assert.Equal(t, "17 August 2025", FormatDate(date, "en"))
+}
+
+func TestTemplateForOffset(t *testing.T) {
+	assert.Equal(t, TemplateUpcoming, TemplateForOffset(-2))
+	assert.Equal(t, TemplateDue, TemplateForOffset(0))
+	assert.Equal(t, TemplateOverdue, TemplateForOffset(1))
+}
+
+func TestRenderer_RendersPerLanguageWithFallback(t *testing.T) {
+	r, err := NewRenderer([]string{"id", "en"}, "id", "")
+	assert.NoError(t, err)
+
+	subject, body, err := r.Render("en", TemplateUpcoming, testTemplateData())
+	assert.NoError(t, err)
+	assert.Equal(t, "Reminder: week 3 installment is due on 17 Aug 2025", subject)
+	assert.Equal(t, "Hi Budi, your week 3 installment of Rp110.000 for loan 1001 is due in 2 days, on 17 Aug 2025. Pay to BCA virtual account 3935800000000420.", body)
+
+	data := testTemplateData()
+	data.Days = 1
+	_, body, err = r.Render("en", TemplateOverdue, data)
+	assert.NoError(t, err)
+	assert.Contains(t, body, "is 1 day past its due date")
+
+	_, body, err = r.Render("fr", TemplateDue, testTemplateData())
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(body, "Halo Budi, cicilan minggu ke-3 pinjaman 1001 sebesar Rp110.000 jatuh tempo hari ini"))
+}
+
+func TestRenderer_TemplateDirOverridesDefaults(t *testing.T) {
+	dir := t.TempDir()
+	src := `{{define "due.subject"}}Due{{end}}{{define "due.body"}}Pay {{.Amount}}{{end}}`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "en.tmpl"), []byte(src), 0o644))
+
+	r, err := NewRenderer([]string{"id", "en"}, "id", dir)
+	assert.NoError(t, err)
+	subject, body, err := r.Render("en", TemplateDue, testTemplateData())
+	assert.NoError(t, err)
+	assert.Equal(t, "Due", subject)
+	assert.Equal(t, "Pay Rp110.000", body)
+
+	_, err = NewRenderer([]string{"fr"}, "fr", "")
+	assert.Error(t, err)
+}
+
+func TestFileChannel_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+	channel := NewFileChannel(path)
+	ref, err := channel.Send(t.Context(), Message{Reference: "reminder-1", Channel: ChannelSMS, To: "+6281234567890", Body: "hello"})
+	assert.NoError(t, err)
+	assert.Equal(t, "reminder-1", ref)
+	_, err = channel.Send(t.Context(), Message{Reference: "reminder-2", Channel: ChannelEmail, To: "budi@example.com", Subject: "Hi", Body: "hello"})
+	assert.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 2)
+	var record fileRecord
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, "budi@example.com", record.To)
+	assert.Equal(t, ChannelEmail, record.Channel)
+}
+
+func TestSMSChannel(t *testing.T) {
+	var got smsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"messageId":"sms-42"}`))
+	}))
+	defer server.Close()
+
+	channel := NewSMSChannel(server.Client(), server.URL, "secret", "BILLING")
+	ref, err := channel.Send(t.Context(), Message{Reference: "reminder-1", To: "+6281234567890", Body: "hello"})
+	assert.NoError(t, err)
+	assert.Equal(t, "sms-42", ref)
+	assert.Equal(t, smsRequest{From: "BILLING", To: "+6281234567890", Text: "hello", Reference: "reminder-1"}, got)
+}
+
+func TestWhatsappChannel(t *testing.T) {
+	var got whatsappRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/1234/messages", r.URL.Path)
+		json.NewDecoder(r.Body).Decode(&got)
+		if got.To == "6280000000000" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"message":"Recipient not on WhatsApp"}}`))
+			return
+		}
+		w.Write([]byte(`{"messages":[{"id":"wamid.1"}]}`))
+	}))
+	defer server.Close()
+
+	channel := NewWhatsappChannel(server.Client(), server.URL+"/", "1234", "token")
+	ref, err := channel.Send(t.Context(), Message{To: "+6281234567890", Body: "hello"})
+	assert.NoError(t, err)
+	assert.Equal(t, "wamid.1", ref)
+	assert.Equal(t, "6281234567890", got.To)
+	assert.Equal(t, "hello", got.Text.Body)
+
+	_, err = channel.Send(t.Context(), Message{To: "+6280000000000", Body: "hello"})
+	assert.EqualError(t, err, "whatsapp responded with status 400: Recipient not on WhatsApp")
+}
+
+func TestBuildEmail(t *testing.T) {
+	email := string(buildEmail("Billing <billing@example.com>", Message{To: "budi@example.com", Subject: "Cicilan jatuh tempo", Body: "line 1\nline 2"}, "<abc@example.com>", testTime))
+	assert.Contains(t, email, "To: budi@example.com\r\n")
+	assert.Contains(t, email, "Subject: Cicilan jatuh tempo\r\n")
+	assert.Contains(t, email, "Message-ID: <abc@example.com>\r\n")
+	assert.True(t, strings.HasSuffix(email, "\r\n\r\nline 1\r\nline 2\r\n"))
+}
+
+func TestNewChannels(t *testing.T) {
+	channels, err := NewChannels(&config.NotificationConfig{SMSAdapter: AdapterFile, EmailAdapter: AdapterLog, WhatsappAdapter: AdapterWhatsappCloud})
+	assert.NoError(t, err)
+	assert.Len(t, channels, 3)
+
+	_, err = NewChannels(&config.NotificationConfig{SMSAdapter: AdapterSMTP, EmailAdapter: AdapterLog, WhatsappAdapter: AdapterLog})
+	assert.EqualError(t, err, `unknown SMS notification adapter "smtp"`)
+}
+
+var testTime = time.Date(2025, 8, 15, 9, 0, 0, 0, time.UTC)
@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const AdapterSMSHTTP = "http"
+
+type smsChannel struct {
+	client *http.Client
+	url    string
+	apiKey string
+	sender string
+}
+
+type smsRequest struct {
+	From      string `json:"from,omitempty"`
+	To        string `json:"to"`
+	Text      string `json:"text"`
+	Reference string `json:"reference"`
+}
+
+type smsResponse struct {
+	MessageID string `json:"messageId"`
+}
+
+func NewSMSChannel(client *http.Client, url, apiKey, sender string) Channel {
+	return &smsChannel{client: client, url: url, apiKey: apiKey, sender: sender}
+}
+
+func (c *smsChannel) Send(ctx context.Context, msg Message) (string, error) {
+	body, err := json.Marshal(smsRequest{From: c.sender, To: msg.To, Text: msg.Body, Reference: msg.Reference})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("sms gateway responded with status %d", resp.StatusCode)
+	}
+	var result smsResponse
+	if err := json.Unmarshal(respBody, &result); err != nil || result.MessageID == "" {
+		return msg.Reference, nil
+	}
+	return result.MessageID, nil
+}
@@ -0,0 +1,130 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	TemplateUpcoming = "upcoming"
+	TemplateDue      = "due"
+	TemplateOverdue  = "overdue"
+	TemplateDunning  = "dunning"
+	TemplateLetter   = "letter"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+var indonesianMonths = []string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+type VirtualAccount struct {
+	BankCode string
+	Number   string
+}
+
+type TemplateData struct {
+	CustomerName    string
+	LoanID          uint
+	Week            int
+	Amount          string
+	DueDate         string
+	Days            int
+	VirtualAccounts []VirtualAccount
+}
+
+type Renderer struct {
+	templates       map[string]*template.Template
+	defaultLanguage string
+}
+
+func NewRenderer(languages []string, defaultLanguage, dir string) (*Renderer, error) {
+	r := &Renderer{templates: make(map[string]*template.Template), defaultLanguage: defaultLanguage}
+	for _, language := range languages {
+		name := language + ".tmpl"
+		var src []byte
+		var err error
+		if dir != "" {
+			src, err = os.ReadFile(filepath.Join(dir, name))
+		}
+		if dir == "" || os.IsNotExist(err) {
+			src, err = defaultTemplates.ReadFile("templates/" + name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("no notification templates for language %q", language)
+		}
+		tmpl, err := template.New(language).Parse(string(src))
+		if err != nil {
+			return nil, fmt.Errorf("invalid notification templates for language %q: %s", language, err.Error())
+		}
+		r.templates[language] = tmpl
+	}
+	if _, ok := r.templates[defaultLanguage]; !ok {
+		return nil, fmt.Errorf("no notification templates for default language %q", defaultLanguage)
+	}
+	return r, nil
+}
+
+func TemplateForOffset(offset int) string {
+	switch {
+	case offset < 0:
+		return TemplateUpcoming
+	case offset == 0:
+		return TemplateDue
+	}
+	return TemplateOverdue
+}
+
+func (r *Renderer) Render(language, name string, data TemplateData) (string, string, error) {
+	tmpl, ok := r.templates[language]
+	if !ok {
+		tmpl = r.templates[r.defaultLanguage]
+	}
+	subject, err := execute(tmpl, name+".subject", data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := execute(tmpl, name+".body", data)
+	if err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
+
+func execute(tmpl *template.Template, name string, data TemplateData) (string, error) {
+	var b bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&b, name, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+func FormatRupiah(amount int) string {
+	digits := strconv.Itoa(max(amount, -amount))
+	var b strings.Builder
+	if amount < 0 {
+		b.WriteString("-")
+	}
+	b.WriteString("Rp")
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
+func FormatDate(t time.Time, language string) string {
+	if language == "id" {
+		return fmt.Sprintf("%d %s %d", t.Day(), indonesianMonths[t.Month()-1], t.Year())
+	}
+	return t.Format("2 January 2006")
+}
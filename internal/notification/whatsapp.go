@@ -0,0 +1,79 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const AdapterWhatsappCloud = "cloud-api"
+
+type whatsappChannel struct {
+	client        *http.Client
+	url           string
+	phoneNumberID string
+	token         string
+}
+
+type whatsappText struct {
+	Body string `json:"body"`
+}
+
+type whatsappRequest struct {
+	MessagingProduct string       `json:"messaging_product"`
+	To               string       `json:"to"`
+	Type             string       `json:"type"`
+	Text             whatsappText `json:"text"`
+}
+
+type whatsappResponse struct {
+	Messages []struct {
+		ID string `json:"id"`
+	} `json:"messages"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func NewWhatsappChannel(client *http.Client, url, phoneNumberID, token string) Channel {
+	return &whatsappChannel{client: client, url: strings.TrimSuffix(url, "/"), phoneNumberID: phoneNumberID, token: token}
+}
+
+func (c *whatsappChannel) Send(ctx context.Context, msg Message) (string, error) {
+	body, err := json.Marshal(whatsappRequest{
+		MessagingProduct: "whatsapp",
+		To:               strings.TrimPrefix(msg.To, "+"),
+		Type:             "text",
+		Text:             whatsappText{Body: msg.Body},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/"+c.phoneNumberID+"/messages", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var result whatsappResponse
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&result)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if result.Error != nil {
+			return "", fmt.Errorf("whatsapp responded with status %d: %s", resp.StatusCode, result.Error.Message)
+		}
+		return "", fmt.Errorf("whatsapp responded with status %d", resp.StatusCode)
+	}
+	if len(result.Messages) == 0 {
+		return "", fmt.Errorf("whatsapp response has no message id")
+	}
+	return result.Messages[0].ID, nil
+}
@@ -0,0 +1,68 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/pkg/events"
+)
+
+const PublisherLog = "log"
+
+type Publisher interface {
+	Publish(ctx context.Context, event model.OutboxEvent) error
+}
+
+type logPublisher struct{}
+
+func NewLogPublisher() Publisher {
+	return &logPublisher{}
+}
+
+func ToCloudEvent(event model.OutboxEvent) events.Event {
+	return events.New(event.EventType, strconv.FormatUint(uint64(event.ID), 10), events.BillingSubject(event.BillingID), event.OccurredAt, event.Payload)
+}
+
+func (p *logPublisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	b, err := json.Marshal(ToCloudEvent(event))
+	if err != nil {
+		return err
+	}
+	log.Printf("Published event: %s", string(b))
+	return nil
+}
+
+type multiPublisher struct {
+	publishers []Publisher
+}
+
+func NewPublisher(names []string, available map[string]Publisher) (Publisher, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no outbox publisher configured")
+	}
+	publishers := make([]Publisher, 0, len(names))
+	for _, name := range names {
+		p, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown outbox publisher %q", name)
+		}
+		publishers = append(publishers, p)
+	}
+	if len(publishers) == 1 {
+		return publishers[0], nil
+	}
+	return &multiPublisher{publishers: publishers}, nil
+}
+
+func (p *multiPublisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
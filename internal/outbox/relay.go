@@ -0,0 +1,113 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/lock"
+	"github.com/doddeeph/billing-engine/internal/repository"
+)
+
+const relayLockName = "outbox-relay"
+
+type Relay struct {
+	repo      repository.OutboxRepository
+	publisher Publisher
+	locker    lock.Locker
+	cfg       *config.OutboxConfig
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func NewRelay(repo repository.OutboxRepository, publisher Publisher, locker lock.Locker, cfg *config.OutboxConfig) *Relay {
+	return &Relay{repo: repo, publisher: publisher, locker: locker, cfg: cfg}
+}
+
+func (r *Relay) Start() {
+	if !r.cfg.RelayEnabled {
+		log.Println("Outbox relay is disabled.")
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.cfg.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.poll(ctx)
+			}
+		}
+	}()
+	log.Printf("Outbox relay started, polling every %s", r.cfg.PollInterval)
+}
+
+func (r *Relay) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+	log.Println("Outbox relay stopped.")
+}
+
+func (r *Relay) poll(ctx context.Context) {
+	relayLock, acquired, err := r.locker.TryLock(ctx, relayLockName)
+	if err != nil {
+		log.Printf("Failed to acquire outbox relay lock: %v", err)
+		return
+	}
+	if !acquired {
+		return
+	}
+	defer func() {
+		if err := relayLock.Release(context.Background()); err != nil {
+			log.Printf("Failed to release outbox relay lock: %v", err)
+		}
+	}()
+	for ctx.Err() == nil {
+		published, _, err := r.RelayBatch(ctx)
+		if err != nil {
+			log.Printf("Failed to relay outbox events: %v", err)
+			return
+		}
+		if published < r.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+func (r *Relay) RelayBatch(ctx context.Context) (published, failed int, err error) {
+	events, err := r.repo.FindPending(ctx, r.cfg.BatchSize)
+	if err != nil {
+		return 0, 0, err
+	}
+	blocked := make(map[uint]bool)
+	for _, event := range events {
+		if blocked[event.BillingID] {
+			continue
+		}
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			log.Printf("Failed to publish event %d %s for billing %d: %v", event.ID, event.EventType, event.BillingID, err)
+			blocked[event.BillingID] = true
+			failed++
+			if err := r.repo.MarkFailed(ctx, event.ID, err.Error()); err != nil {
+				return published, failed, err
+			}
+			continue
+		}
+		if err := r.repo.MarkPublished(ctx, event.ID, time.Now()); err != nil {
+			return published, failed, err
+		}
+		published++
+	}
+	return published, failed, nil
+}
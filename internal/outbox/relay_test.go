@@ -0,0 +1,153 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/lock"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/doddeeph/billing-engine/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type fakeOutboxRepository struct {
+	events []model.OutboxEvent
+}
+
+func (r *fakeOutboxRepository) WithTransaction(trx *gorm.DB) repository.OutboxRepository {
+	return r
+}
+
+func (r *fakeOutboxRepository) Create(ctx context.Context, event *model.OutboxEvent) error {
+	event.ID = uint(len(r.events) + 1)
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *fakeOutboxRepository) FindPending(ctx context.Context, limit int) ([]model.OutboxEvent, error) {
+	var fresh, retries []model.OutboxEvent
+	failed := make(map[uint]bool)
+	for _, event := range r.events {
+		if event.Status != model.OutboxStatusPending || failed[event.BillingID] {
+			continue
+		}
+		if event.Attempts > 0 {
+			failed[event.BillingID] = true
+			retries = append(retries, event)
+			continue
+		}
+		fresh = append(fresh, event)
+	}
+	events := append(fresh, retries...)
+	return events[:min(limit, len(events))], nil
+}
+
+func (r *fakeOutboxRepository) MarkPublished(ctx context.Context, ID uint, publishedAt time.Time) error {
+	r.events[ID-1].Status = model.OutboxStatusPublished
+	r.events[ID-1].PublishedAt = &publishedAt
+	r.events[ID-1].Attempts++
+	return nil
+}
+
+func (r *fakeOutboxRepository) MarkFailed(ctx context.Context, ID uint, lastError string) error {
+	r.events[ID-1].LastError = lastError
+	r.events[ID-1].Attempts++
+	return nil
+}
+
+type fakePublisher struct {
+	published []uint
+	failing   map[uint]bool
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	if p.failing[event.ID] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+type noopLocker struct{}
+
+func (noopLocker) TryLock(ctx context.Context, name string) (lock.Lock, bool, error) {
+	return nil, false, nil
+}
+
+func newFakeOutbox(billingIDs ...uint) *fakeOutboxRepository {
+	repo := &fakeOutboxRepository{}
+	for _, billingID := range billingIDs {
+		_ = repo.Create(context.Background(), &model.OutboxEvent{
+			BillingID: billingID,
+			EventType: events.TypePaymentApplied,
+			Status:    model.OutboxStatusPending,
+		})
+	}
+	return repo
+}
+
+func TestRelayBatch_PublishesInOrder(t *testing.T) {
+	repo := newFakeOutbox(1, 2, 1, 2)
+	publisher := &fakePublisher{}
+	relay := NewRelay(repo, publisher, noopLocker{}, &config.OutboxConfig{BatchSize: 10})
+
+	published, failed, err := relay.RelayBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 4, published)
+	assert.Equal(t, 0, failed)
+	assert.Equal(t, []uint{1, 2, 3, 4}, publisher.published)
+	for _, event := range repo.events {
+		assert.Equal(t, model.OutboxStatusPublished, event.Status)
+		assert.NotNil(t, event.PublishedAt)
+	}
+}
+
+func TestRelayBatch_FailureBlocksLaterEventsOfSameBilling(t *testing.T) {
+	repo := newFakeOutbox(1, 2, 1, 2)
+	publisher := &fakePublisher{failing: map[uint]bool{1: true}}
+	relay := NewRelay(repo, publisher, noopLocker{}, &config.OutboxConfig{BatchSize: 10})
+
+	published, failed, err := relay.RelayBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, []uint{2, 4}, publisher.published)
+	assert.Equal(t, model.OutboxStatusPending, repo.events[0].Status)
+	assert.Equal(t, "broker unavailable", repo.events[0].LastError)
+	assert.Equal(t, model.OutboxStatusPending, repo.events[2].Status)
+	assert.Equal(t, 0, repo.events[2].Attempts)
+
+	publisher.failing = nil
+	published, failed, err = relay.RelayBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, 0, failed)
+	published, _, err = relay.RelayBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []uint{2, 4, 1, 3}, publisher.published)
+	assert.Equal(t, 2, repo.events[0].Attempts)
+}
+
+func TestRelayBatch_FailingBillingDoesNotStarveOthers(t *testing.T) {
+	repo := newFakeOutbox(1, 1, 1, 1, 2, 2)
+	publisher := &fakePublisher{failing: map[uint]bool{1: true}}
+	relay := NewRelay(repo, publisher, noopLocker{}, &config.OutboxConfig{BatchSize: 2})
+
+	_, failed, err := relay.RelayBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, failed)
+
+	for range 2 {
+		_, _, err = relay.RelayBatch(context.Background())
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []uint{5, 6}, publisher.published)
+	assert.Equal(t, model.OutboxStatusPending, repo.events[1].Status)
+	assert.Equal(t, 0, repo.events[1].Attempts)
+}
@@ -0,0 +1,187 @@
+package qris
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	TagPayloadFormat     = "00"
+	TagPointOfInitiation = "01"
+	TagMerchantAccount   = "26"
+	TagQRISMerchant      = "51"
+	TagMCC               = "52"
+	TagCurrency          = "53"
+	TagAmount            = "54"
+	TagCountry           = "58"
+	TagMerchantName      = "59"
+	TagMerchantCity      = "60"
+	TagPostalCode        = "61"
+	TagAdditionalData    = "62"
+	TagCRC               = "63"
+
+	TagAdditionalBillNumber     = "01"
+	TagAdditionalReferenceLabel = "05"
+	TagAdditionalTerminalLabel  = "07"
+
+	PayloadFormatIndicator   = "01"
+	PointOfInitiationStatic  = "11"
+	PointOfInitiationDynamic = "12"
+	CurrencyIDR              = "360"
+	CountryID                = "ID"
+	QRISGlobalIdentifier     = "ID.CO.QRIS.WWW"
+)
+
+type Merchant struct {
+	AcquirerDomain string
+	MerchantPAN    string
+	MerchantID     string
+	NMID           string
+	Criteria       string
+	MCC            string
+	Name           string
+	City           string
+	PostalCode     string
+}
+
+type Payment struct {
+	Amount        int
+	BillNumber    string
+	Reference     string
+	TerminalLabel string
+}
+
+func TLV(tag, value string) (string, error) {
+	if len(value) == 0 || len(value) > 99 {
+		return "", fmt.Errorf("value of tag %s must be 1-99 characters, got %d", tag, len(value))
+	}
+	return fmt.Sprintf("%s%02d%s", tag, len(value), value), nil
+}
+
+func CRC16(data string) string {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%04X", crc)
+}
+
+type builder struct {
+	sb  strings.Builder
+	err error
+}
+
+func (b *builder) add(tag, value string) {
+	if b.err != nil || value == "" {
+		return
+	}
+	field, err := TLV(tag, value)
+	if err != nil {
+		b.err = err
+		return
+	}
+	b.sb.WriteString(field)
+}
+
+func (b *builder) addTemplate(tag string, fields ...[2]string) {
+	if b.err != nil {
+		return
+	}
+	var inner builder
+	for _, field := range fields {
+		inner.add(field[0], field[1])
+	}
+	if inner.err != nil {
+		b.err = inner.err
+		return
+	}
+	b.add(tag, inner.sb.String())
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+func GenerateDynamic(merchant Merchant, payment Payment) (string, error) {
+	if merchant.NMID == "" || merchant.MCC == "" || merchant.Name == "" || merchant.City == "" {
+		return "", fmt.Errorf("QRIS merchant requires NMID, MCC, name and city")
+	}
+	if payment.Amount <= 0 {
+		return "", fmt.Errorf("QRIS amount must be positive")
+	}
+	var b builder
+	b.add(TagPayloadFormat, PayloadFormatIndicator)
+	b.add(TagPointOfInitiation, PointOfInitiationDynamic)
+	if merchant.AcquirerDomain != "" {
+		b.addTemplate(TagMerchantAccount,
+			[2]string{"00", merchant.AcquirerDomain},
+			[2]string{"01", merchant.MerchantPAN},
+			[2]string{"02", merchant.MerchantID},
+			[2]string{"03", merchant.Criteria},
+		)
+	}
+	b.addTemplate(TagQRISMerchant,
+		[2]string{"00", QRISGlobalIdentifier},
+		[2]string{"02", merchant.NMID},
+		[2]string{"03", merchant.Criteria},
+	)
+	b.add(TagMCC, merchant.MCC)
+	b.add(TagCurrency, CurrencyIDR)
+	b.add(TagAmount, strconv.Itoa(payment.Amount))
+	b.add(TagCountry, CountryID)
+	b.add(TagMerchantName, truncate(merchant.Name, 25))
+	b.add(TagMerchantCity, truncate(merchant.City, 15))
+	b.add(TagPostalCode, merchant.PostalCode)
+	b.addTemplate(TagAdditionalData,
+		[2]string{TagAdditionalBillNumber, payment.BillNumber},
+		[2]string{TagAdditionalReferenceLabel, payment.Reference},
+		[2]string{TagAdditionalTerminalLabel, payment.TerminalLabel},
+	)
+	if b.err != nil {
+		return "", b.err
+	}
+	payload := b.sb.String() + TagCRC + "04"
+	return payload + CRC16(payload), nil
+}
+
+func Parse(payload string) (map[string]string, error) {
+	if len(payload) < 8 || payload[len(payload)-8:len(payload)-4] != TagCRC+"04" {
+		return nil, fmt.Errorf("QRIS payload has no CRC field")
+	}
+	if CRC16(payload[:len(payload)-4]) != strings.ToUpper(payload[len(payload)-4:]) {
+		return nil, fmt.Errorf("QRIS payload CRC mismatch")
+	}
+	return parseTLV(payload)
+}
+
+func parseTLV(s string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for i := 0; i < len(s); {
+		if i+4 > len(s) {
+			return nil, fmt.Errorf("truncated TLV at position %d", i)
+		}
+		tag := s[i : i+2]
+		length, err := strconv.Atoi(s[i+2 : i+4])
+		if err != nil || i+4+length > len(s) {
+			return nil, fmt.Errorf("invalid length for tag %s at position %d", tag, i)
+		}
+		fields[tag] = s[i+4 : i+4+length]
+		i += 4 + length
+	}
+	return fields, nil
+}
+
+func ParseTemplate(value string) (map[string]string, error) {
+	return parseTLV(value)
+}
@@ -0,0 +1,87 @@
+package qris
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testMerchant() Merchant {
+	return Merchant{
+		AcquirerDomain: "ID.CO.BANK.WWW",
+		MerchantPAN:    "936000140000000001",
+		MerchantID:     "000000000000001",
+		NMID:           "ID1020000000001",
+		Criteria:       "UMI",
+		MCC:            "6012",
+		Name:           "Billing Engine Lending Company",
+		City:           "Jakarta Selatan Raya",
+		PostalCode:     "12190",
+	}
+}
+
+func TestCRC16(t *testing.T) {
+	assert.Equal(t, "29B1", CRC16("123456789"))
+}
+
+func TestTLV(t *testing.T) {
+	field, err := TLV("54", "110000")
+	assert.NoError(t, err)
+	assert.Equal(t, "5406110000", field)
+
+	_, err = TLV("54", "")
+	assert.Error(t, err)
+}
+
+func TestGenerateDynamic(t *testing.T) {
+	payload, err := GenerateDynamic(testMerchant(), Payment{Amount: 110000, BillNumber: "42", Reference: "QR42ABC"})
+	assert.NoError(t, err)
+	assert.Equal(t, "000201010212", payload[:12])
+
+	fields, err := Parse(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, "110000", fields[TagAmount])
+	assert.Equal(t, CurrencyIDR, fields[TagCurrency])
+	assert.Equal(t, CountryID, fields[TagCountry])
+	assert.Equal(t, "6012", fields[TagMCC])
+	assert.Equal(t, "Billing Engine Lending Co", fields[TagMerchantName])
+	assert.Equal(t, "Jakarta Selatan", fields[TagMerchantCity])
+
+	merchant, err := ParseTemplate(fields[TagQRISMerchant])
+	assert.NoError(t, err)
+	assert.Equal(t, QRISGlobalIdentifier, merchant["00"])
+	assert.Equal(t, "ID1020000000001", merchant["02"])
+
+	additional, err := ParseTemplate(fields[TagAdditionalData])
+	assert.NoError(t, err)
+	assert.Equal(t, "42", additional[TagAdditionalBillNumber])
+	assert.Equal(t, "QR42ABC", additional[TagAdditionalReferenceLabel])
+
+	_, err = GenerateDynamic(testMerchant(), Payment{Amount: 0})
+	assert.Error(t, err)
+}
+
+func TestParseRejectsTamperedPayload(t *testing.T) {
+	payload, err := GenerateDynamic(testMerchant(), Payment{Amount: 110000, Reference: "QR42ABC"})
+	assert.NoError(t, err)
+
+	tampered := payload[:len(payload)-4] + "0000"
+	_, err = Parse(tampered)
+	assert.Error(t, err)
+
+	_, err = Parse("000201")
+	assert.Error(t, err)
+}
+
+func TestGenerateDynamicRequiresMerchant(t *testing.T) {
+	_, err := GenerateDynamic(Merchant{Name: "Billing Engine"}, Payment{Amount: 110000})
+	assert.Error(t, err)
+
+	merchant := testMerchant()
+	merchant.AcquirerDomain = ""
+	payload, err := GenerateDynamic(merchant, Payment{Amount: 110000})
+	assert.NoError(t, err)
+	fields, err := Parse(payload)
+	assert.NoError(t, err)
+	assert.NotContains(t, fields, TagMerchantAccount)
+}
@@ -2,16 +2,62 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/doddeeph/billing-engine/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+type BillingFilter struct {
+	IDs         []uint
+	CustomerIDs []uint
+	LoanIDs     []uint
+	Status      string
+}
+
+type BillingListFilter struct {
+	CustomerID        uint
+	Status            string
+	DelinquencyStatus string
+	CreatedFrom       *time.Time
+	CreatedTo         *time.Time
+	BeforeID          uint
+	Limit             int
+}
+
+type WriteOffSummary struct {
+	WrittenOffCount  int
+	WrittenOffAmount int
+	RecoveredAmount  int
+}
+
+type BillingDueDate struct {
+	BillingID           uint
+	Outstanding         int
+	OldestUnpaidDueDate *time.Time
+}
+
 type BillingRepository interface {
 	WithTransaction(tx *gorm.DB) BillingRepository
+	WithDB() *gorm.DB
 	Create(ctx context.Context, billing *model.Billing) error
 	FindByID(ctx context.Context, ID uint) (*model.Billing, error)
+	FindByIDForUpdate(ctx context.Context, ID uint) (*model.Billing, error)
+	FindByLoanID(ctx context.Context, loanID uint) (*model.Billing, error)
+	FindIDsByFilter(ctx context.Context, filter BillingFilter) ([]uint, error)
+	FindByListFilter(ctx context.Context, filter BillingListFilter) ([]model.Billing, error)
+	FindByCustomerID(ctx context.Context, customerID uint) ([]model.Billing, error)
+	SumOutstandingByCustomerID(ctx context.Context, customerID uint, status string) (int, error)
 	UpdateOutstanding(ctx context.Context, billingID uint, balance int) error
+	UpdateStatus(ctx context.Context, billingID uint, status string) error
+	UpdateDelinquencyPolicy(ctx context.Context, billingID uint, policyID *uint) error
+	UpdateDelinquencyStatus(ctx context.Context, billingID uint, status string) error
+	FindIDsByStatus(ctx context.Context, status string, afterID uint, limit int) ([]uint, error)
+	WriteOff(ctx context.Context, billingID uint, amount int, reason string, writtenOffAt time.Time) error
+	AddRecoveredAmount(ctx context.Context, billingID uint, amount int) error
+	SummarizeWriteOffs(ctx context.Context) (*WriteOffSummary, error)
+	FindOldestUnpaidDueDates(ctx context.Context, status string) ([]BillingDueDate, error)
 }
 
 type billingRepository struct {
@@ -26,18 +72,179 @@ func (r *billingRepository) WithTransaction(tx *gorm.DB) BillingRepository {
 	return &billingRepository{tx}
 }
 
+func (r *billingRepository) WithDB() *gorm.DB {
+	return r.db
+}
+
 func (r *billingRepository) Create(ctx context.Context, billing *model.Billing) error {
 	return r.db.WithContext(ctx).Create(billing).Error
 }
 
+func (r *billingRepository) FindByLoanID(ctx context.Context, loanID uint) (*model.Billing, error) {
+	var billing model.Billing
+	if err := r.preloadDetails(ctx).Where("loan_id = ?", loanID).First(&billing).Error; err != nil {
+		return nil, err
+	}
+	return &billing, nil
+}
+
 func (r *billingRepository) FindByID(ctx context.Context, ID uint) (*model.Billing, error) {
 	var billing model.Billing
-	if err := r.db.WithContext(ctx).Preload("Payments").First(&billing, ID).Error; err != nil {
+	if err := r.preloadDetails(ctx).First(&billing, ID).Error; err != nil {
+		return nil, err
+	}
+	return &billing, nil
+}
+
+func (r *billingRepository) FindByIDForUpdate(ctx context.Context, ID uint) (*model.Billing, error) {
+	var billing model.Billing
+	if err := r.preloadDetails(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&billing, ID).Error; err != nil {
 		return nil, err
 	}
 	return &billing, nil
 }
 
+func (r *billingRepository) preloadDetails(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Preload("Payments", func(db *gorm.DB) *gorm.DB {
+		return db.Order("week")
+	}).Preload("Customer").Preload("Freezes").Preload("Promises", func(db *gorm.DB) *gorm.DB {
+		return db.Order("promised_at")
+	}).Preload("VirtualAccounts")
+}
+
+func (r *billingRepository) FindIDsByFilter(ctx context.Context, filter BillingFilter) ([]uint, error) {
+	var ids []uint
+	query := r.db.WithContext(ctx).Model(&model.Billing{})
+	if len(filter.IDs) > 0 {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	if len(filter.CustomerIDs) > 0 {
+		query = query.Where("customer_id IN ?", filter.CustomerIDs)
+	}
+	if len(filter.LoanIDs) > 0 {
+		query = query.Where("loan_id IN ?", filter.LoanIDs)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if err := query.Order("id").Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *billingRepository) FindByListFilter(ctx context.Context, filter BillingListFilter) ([]model.Billing, error) {
+	var billings []model.Billing
+	query := r.db.WithContext(ctx).Model(&model.Billing{})
+	if filter.CustomerID > 0 {
+		query = query.Where("customer_id = ?", filter.CustomerID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.DelinquencyStatus != "" {
+		query = query.Where("delinquency_status = ?", filter.DelinquencyStatus)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+	if filter.BeforeID > 0 {
+		query = query.Where("id < ?", filter.BeforeID)
+	}
+	if err := query.Order("id DESC").Limit(filter.Limit).Find(&billings).Error; err != nil {
+		return nil, err
+	}
+	return billings, nil
+}
+
+func (r *billingRepository) FindByCustomerID(ctx context.Context, customerID uint) ([]model.Billing, error) {
+	var billings []model.Billing
+	if err := r.preloadDetails(ctx).Where("customer_id = ?", customerID).Order("id").Find(&billings).Error; err != nil {
+		return nil, err
+	}
+	return billings, nil
+}
+
+func (r *billingRepository) SumOutstandingByCustomerID(ctx context.Context, customerID uint, status string) (int, error) {
+	var total int
+	err := r.db.WithContext(ctx).Model(&model.Billing{}).
+		Select("COALESCE(SUM(outstanding), 0)").
+		Where("customer_id = ? AND status = ?", customerID, status).
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
 func (r *billingRepository) UpdateOutstanding(ctx context.Context, billingID uint, balance int) error {
 	return r.db.WithContext(ctx).Model(&model.Billing{}).Where("id = ?", billingID).Update("outstanding", balance).Error
 }
+
+func (r *billingRepository) UpdateStatus(ctx context.Context, billingID uint, status string) error {
+	return r.db.WithContext(ctx).Model(&model.Billing{}).Where("id = ?", billingID).Update("status", status).Error
+}
+
+func (r *billingRepository) UpdateDelinquencyPolicy(ctx context.Context, billingID uint, policyID *uint) error {
+	return r.db.WithContext(ctx).Model(&model.Billing{}).Where("id = ?", billingID).Update("delinquency_policy_id", policyID).Error
+}
+
+func (r *billingRepository) UpdateDelinquencyStatus(ctx context.Context, billingID uint, status string) error {
+	return r.db.WithContext(ctx).Model(&model.Billing{}).Where("id = ?", billingID).Update("delinquency_status", status).Error
+}
+
+func (r *billingRepository) FindIDsByStatus(ctx context.Context, status string, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.Billing{}).
+		Where("status = ? AND id > ?", status, afterID).
+		Order("id").Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *billingRepository) WriteOff(ctx context.Context, billingID uint, amount int, reason string, writtenOffAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.Billing{}).Where("id = ?", billingID).Updates(map[string]any{
+		"status":             model.BillingStatusWrittenOff,
+		"outstanding":        0,
+		"written_off_amount": amount,
+		"write_off_reason":   reason,
+		"written_off_at":     writtenOffAt,
+	}).Error
+}
+
+func (r *billingRepository) AddRecoveredAmount(ctx context.Context, billingID uint, amount int) error {
+	return r.db.WithContext(ctx).Model(&model.Billing{}).Where("id = ?", billingID).
+		Update("recovered_amount", gorm.Expr("recovered_amount + ?", amount)).Error
+}
+
+func (r *billingRepository) SummarizeWriteOffs(ctx context.Context) (*WriteOffSummary, error) {
+	var summary WriteOffSummary
+	err := r.db.WithContext(ctx).Model(&model.Billing{}).
+		Select("COUNT(*) AS written_off_count, COALESCE(SUM(written_off_amount), 0) AS written_off_amount, COALESCE(SUM(recovered_amount), 0) AS recovered_amount").
+		Where("status = ?", model.BillingStatusWrittenOff).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (r *billingRepository) FindOldestUnpaidDueDates(ctx context.Context, status string) ([]BillingDueDate, error) {
+	var dueDates []BillingDueDate
+	err := r.db.WithContext(ctx).Model(&model.Billing{}).
+		Select("billings.id AS billing_id, billings.outstanding, MIN(payments.due_date) AS oldest_unpaid_due_date").
+		Joins("LEFT JOIN payments ON payments.billing_id = billings.id AND payments.paid = ? AND payments.deleted_at IS NULL", false).
+		Where("billings.status = ?", status).
+		Group("billings.id").
+		Scan(&dueDates).Error
+	if err != nil {
+		return nil, err
+	}
+	return dueDates, nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CollectionRepository interface {
+	WithTransaction(trx *gorm.DB) CollectionRepository
+	CreateAgent(ctx context.Context, agent *model.CollectionAgent) error
+	UpdateAgent(ctx context.Context, agent *model.CollectionAgent) error
+	FindAgents(ctx context.Context) ([]model.CollectionAgent, error)
+	FindAgentByID(ctx context.Context, ID uint) (*model.CollectionAgent, error)
+	FindNextAgentForUpdate(ctx context.Context, region string) (*model.CollectionAgent, error)
+	CreateCase(ctx context.Context, collectionCase *model.CollectionCase) error
+	UpdateCase(ctx context.Context, collectionCase *model.CollectionCase) error
+	FindCaseByID(ctx context.Context, ID uint) (*model.CollectionCase, error)
+	FindOpenCaseByBillingID(ctx context.Context, billingID uint) (*model.CollectionCase, error)
+	FindCasesByBillingID(ctx context.Context, billingID uint) ([]model.CollectionCase, error)
+	FindOpenCasesByAgentID(ctx context.Context, agentID *uint, now time.Time, amountUnit, limit, offset int) ([]model.CollectionCase, error)
+	FindBillingIDsToSync(ctx context.Context, afterID uint, limit int) ([]uint, error)
+	CreateAttempt(ctx context.Context, attempt *model.ContactAttempt) error
+}
+
+type collectionRepository struct {
+	db *gorm.DB
+}
+
+func NewCollectionRepository(db *gorm.DB) CollectionRepository {
+	return &collectionRepository{db}
+}
+
+func (r *collectionRepository) WithTransaction(trx *gorm.DB) CollectionRepository {
+	return &collectionRepository{trx}
+}
+
+func (r *collectionRepository) CreateAgent(ctx context.Context, agent *model.CollectionAgent) error {
+	return r.db.WithContext(ctx).Create(agent).Error
+}
+
+func (r *collectionRepository) UpdateAgent(ctx context.Context, agent *model.CollectionAgent) error {
+	return r.db.WithContext(ctx).Save(agent).Error
+}
+
+func (r *collectionRepository) FindAgents(ctx context.Context) ([]model.CollectionAgent, error) {
+	var agents []model.CollectionAgent
+	if err := r.db.WithContext(ctx).Order("id").Find(&agents).Error; err != nil {
+		return nil, err
+	}
+	return agents, nil
+}
+
+func (r *collectionRepository) FindAgentByID(ctx context.Context, ID uint) (*model.CollectionAgent, error) {
+	var agent model.CollectionAgent
+	if err := r.db.WithContext(ctx).First(&agent, ID).Error; err != nil {
+		return nil, err
+	}
+	return &agent, nil
+}
+
+func (r *collectionRepository) FindNextAgentForUpdate(ctx context.Context, region string) (*model.CollectionAgent, error) {
+	var agent model.CollectionAgent
+	query := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Where("active = ?", true)
+	if region != "" {
+		query = query.Where("region = ?", region)
+	}
+	if err := query.Order("last_assigned_at NULLS FIRST").Order("id").First(&agent).Error; err != nil {
+		return nil, err
+	}
+	return &agent, nil
+}
+
+func (r *collectionRepository) CreateCase(ctx context.Context, collectionCase *model.CollectionCase) error {
+	return r.db.WithContext(ctx).Create(collectionCase).Error
+}
+
+func (r *collectionRepository) UpdateCase(ctx context.Context, collectionCase *model.CollectionCase) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(collectionCase).Error
+}
+
+func (r *collectionRepository) FindCaseByID(ctx context.Context, ID uint) (*model.CollectionCase, error) {
+	var collectionCase model.CollectionCase
+	err := r.db.WithContext(ctx).Preload("Attempts", func(db *gorm.DB) *gorm.DB {
+		return db.Order("attempted_at, id")
+	}).First(&collectionCase, ID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collectionCase, nil
+}
+
+func (r *collectionRepository) FindOpenCaseByBillingID(ctx context.Context, billingID uint) (*model.CollectionCase, error) {
+	var collectionCase model.CollectionCase
+	err := r.db.WithContext(ctx).
+		Where("billing_id = ? AND status = ?", billingID, model.CollectionCaseStatusOpen).
+		First(&collectionCase).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collectionCase, nil
+}
+
+func (r *collectionRepository) FindCasesByBillingID(ctx context.Context, billingID uint) ([]model.CollectionCase, error) {
+	var cases []model.CollectionCase
+	if err := r.db.WithContext(ctx).Where("billing_id = ?", billingID).Order("id").Find(&cases).Error; err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
+
+func (r *collectionRepository) FindOpenCasesByAgentID(ctx context.Context, agentID *uint, now time.Time, amountUnit, limit, offset int) ([]model.CollectionCase, error) {
+	overdue := r.db.Model(&model.Payment{}).
+		Select("billing_id, MIN(due_date) AS oldest_due_date, SUM(amount + late_fee) AS overdue_amount").
+		Where("paid = ? AND due_date < ?", false, now).
+		Group("billing_id")
+	priority := "CASE WHEN overdue.oldest_due_date IS NULL THEN 0 ELSE FLOOR(EXTRACT(EPOCH FROM (?::timestamptz - overdue.oldest_due_date)) / 86400)::int + 1 END"
+	vars := []any{now}
+	if amountUnit > 0 {
+		priority += " + COALESCE(overdue.overdue_amount, 0) / ?"
+		vars = append(vars, amountUnit)
+	}
+	query := r.db.WithContext(ctx).Preload("Billing.Payments").
+		Select("collection_cases.*, "+priority+" AS priority_score", vars...).
+		Joins("LEFT JOIN (?) AS overdue ON overdue.billing_id = collection_cases.billing_id", overdue).
+		Where("collection_cases.status = ?", model.CollectionCaseStatusOpen)
+	if agentID != nil {
+		query = query.Where("collection_cases.agent_id = ?", *agentID)
+	} else {
+		query = query.Where("collection_cases.agent_id IS NULL")
+	}
+	var cases []model.CollectionCase
+	err := query.Order("priority_score DESC, collection_cases.opened_at, collection_cases.id").
+		Limit(limit).Offset(offset).Find(&cases).Error
+	if err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
+
+func (r *collectionRepository) FindBillingIDsToSync(ctx context.Context, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	openCase := r.db.Model(&model.CollectionCase{}).Select("1").
+		Where("collection_cases.billing_id = billings.id AND collection_cases.status = ?", model.CollectionCaseStatusOpen)
+	err := r.db.WithContext(ctx).Model(&model.Billing{}).
+		Where("billings.id > ?", afterID).
+		Where("(billings.status = ? AND billings.delinquency_status = ?) <> EXISTS (?)", model.BillingStatusActive, model.DelinquencyStatusDelinquent, openCase).
+		Order("billings.id").Limit(limit).Pluck("billings.id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *collectionRepository) CreateAttempt(ctx context.Context, attempt *model.ContactAttempt) error {
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CustomerExposureRepository interface {
+	WithTransaction(trx *gorm.DB) CustomerExposureRepository
+	Upsert(ctx context.Context, limit *model.CustomerExposureLimit) error
+	FindByCustomerID(ctx context.Context, customerID uint) (*model.CustomerExposureLimit, error)
+	FindByCustomerIDForUpdate(ctx context.Context, customerID uint) (*model.CustomerExposureLimit, error)
+	Delete(ctx context.Context, customerID uint) error
+}
+
+type customerExposureRepository struct {
+	db *gorm.DB
+}
+
+func NewCustomerExposureRepository(db *gorm.DB) CustomerExposureRepository {
+	return &customerExposureRepository{db}
+}
+
+func (r *customerExposureRepository) WithTransaction(trx *gorm.DB) CustomerExposureRepository {
+	return &customerExposureRepository{trx}
+}
+
+func (r *customerExposureRepository) Upsert(ctx context.Context, limit *model.CustomerExposureLimit) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "customer_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"exposure_limit", "updated_at"}),
+	}).Create(limit).Error
+}
+
+func (r *customerExposureRepository) FindByCustomerID(ctx context.Context, customerID uint) (*model.CustomerExposureLimit, error) {
+	var limit model.CustomerExposureLimit
+	if err := r.db.WithContext(ctx).Where("customer_id = ?", customerID).First(&limit).Error; err != nil {
+		return nil, err
+	}
+	return &limit, nil
+}
+
+func (r *customerExposureRepository) FindByCustomerIDForUpdate(ctx context.Context, customerID uint) (*model.CustomerExposureLimit, error) {
+	var limit model.CustomerExposureLimit
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("customer_id = ?", customerID).First(&limit).Error
+	if err != nil {
+		return nil, err
+	}
+	return &limit, nil
+}
+
+func (r *customerExposureRepository) Delete(ctx context.Context, customerID uint) error {
+	return r.db.WithContext(ctx).Unscoped().Where("customer_id = ?", customerID).Delete(&model.CustomerExposureLimit{}).Error
+}
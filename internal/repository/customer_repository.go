@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CustomerRepository interface {
+	WithTransaction(trx *gorm.DB) CustomerRepository
+	Upsert(ctx context.Context, customer *model.Customer) error
+	CreateIfAbsent(ctx context.Context, customer *model.Customer) error
+	FindByID(ctx context.Context, id uint) (*model.Customer, error)
+}
+
+type customerRepository struct {
+	db *gorm.DB
+}
+
+func NewCustomerRepository(db *gorm.DB) CustomerRepository {
+	return &customerRepository{db}
+}
+
+func (r *customerRepository) WithTransaction(trx *gorm.DB) CustomerRepository {
+	return &customerRepository{trx}
+}
+
+func (r *customerRepository) Upsert(ctx context.Context, customer *model.Customer) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"name", "phone", "email", "preferred_language", "timezone", "region",
+			"sms_opt_in", "email_opt_in", "whatsapp_opt_in", "synced_at", "updated_at",
+		}),
+	}).Create(customer).Error
+}
+
+func (r *customerRepository) CreateIfAbsent(ctx context.Context, customer *model.Customer) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoNothing: true,
+	}).Create(customer).Error
+}
+
+func (r *customerRepository) FindByID(ctx context.Context, id uint) (*model.Customer, error) {
+	var customer model.Customer
+	if err := r.db.WithContext(ctx).First(&customer, id).Error; err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
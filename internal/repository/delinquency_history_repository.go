@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+)
+
+type DelinquencyHistoryRepository interface {
+	WithTransaction(trx *gorm.DB) DelinquencyHistoryRepository
+	Create(ctx context.Context, history *model.DelinquencyHistory) error
+	FindByBillingID(ctx context.Context, billingID uint) ([]model.DelinquencyHistory, error)
+}
+
+type delinquencyHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewDelinquencyHistoryRepository(db *gorm.DB) DelinquencyHistoryRepository {
+	return &delinquencyHistoryRepository{db}
+}
+
+func (r *delinquencyHistoryRepository) WithTransaction(trx *gorm.DB) DelinquencyHistoryRepository {
+	return &delinquencyHistoryRepository{trx}
+}
+
+func (r *delinquencyHistoryRepository) Create(ctx context.Context, history *model.DelinquencyHistory) error {
+	return r.db.WithContext(ctx).Create(history).Error
+}
+
+func (r *delinquencyHistoryRepository) FindByBillingID(ctx context.Context, billingID uint) ([]model.DelinquencyHistory, error) {
+	var histories []model.DelinquencyHistory
+	if err := r.db.WithContext(ctx).Where("billing_id = ?", billingID).Order("changed_at, id").Find(&histories).Error; err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
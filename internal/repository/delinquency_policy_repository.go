@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+)
+
+type DelinquencyPolicyRepository interface {
+	WithTransaction(trx *gorm.DB) DelinquencyPolicyRepository
+	Create(ctx context.Context, policy *model.DelinquencyPolicy) error
+	Update(ctx context.Context, policy *model.DelinquencyPolicy) error
+	FindAll(ctx context.Context) ([]model.DelinquencyPolicy, error)
+	FindByID(ctx context.Context, ID uint) (*model.DelinquencyPolicy, error)
+	FindByProductCode(ctx context.Context, productCode string) (*model.DelinquencyPolicy, error)
+}
+
+type delinquencyPolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewDelinquencyPolicyRepository(db *gorm.DB) DelinquencyPolicyRepository {
+	return &delinquencyPolicyRepository{db}
+}
+
+func (r *delinquencyPolicyRepository) WithTransaction(trx *gorm.DB) DelinquencyPolicyRepository {
+	return &delinquencyPolicyRepository{trx}
+}
+
+func (r *delinquencyPolicyRepository) Create(ctx context.Context, policy *model.DelinquencyPolicy) error {
+	return r.db.WithContext(ctx).Create(policy).Error
+}
+
+func (r *delinquencyPolicyRepository) Update(ctx context.Context, policy *model.DelinquencyPolicy) error {
+	return r.db.WithContext(ctx).Save(policy).Error
+}
+
+func (r *delinquencyPolicyRepository) FindAll(ctx context.Context) ([]model.DelinquencyPolicy, error) {
+	var policies []model.DelinquencyPolicy
+	if err := r.db.WithContext(ctx).Order("id").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *delinquencyPolicyRepository) FindByID(ctx context.Context, ID uint) (*model.DelinquencyPolicy, error) {
+	var policy model.DelinquencyPolicy
+	if err := r.db.WithContext(ctx).First(&policy, ID).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *delinquencyPolicyRepository) FindByProductCode(ctx context.Context, productCode string) (*model.DelinquencyPolicy, error) {
+	var policy model.DelinquencyPolicy
+	if err := r.db.WithContext(ctx).Where("product_code = ?", productCode).First(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+)
+
+type DunningRepository interface {
+	WithTransaction(trx *gorm.DB) DunningRepository
+	CreateStrategy(ctx context.Context, strategy *model.DunningStrategy) error
+	UpdateStrategy(ctx context.Context, strategy *model.DunningStrategy) error
+	FindStrategies(ctx context.Context) ([]model.DunningStrategy, error)
+	FindStrategyByID(ctx context.Context, ID uint) (*model.DunningStrategy, error)
+	FindStrategyByProductCode(ctx context.Context, productCode string) (*model.DunningStrategy, error)
+	CreateLog(ctx context.Context, log *model.DunningLog) error
+	FindLogsByBillingID(ctx context.Context, billingID uint) ([]model.DunningLog, error)
+}
+
+type dunningRepository struct {
+	db *gorm.DB
+}
+
+func NewDunningRepository(db *gorm.DB) DunningRepository {
+	return &dunningRepository{db}
+}
+
+func (r *dunningRepository) WithTransaction(trx *gorm.DB) DunningRepository {
+	return &dunningRepository{trx}
+}
+
+func (r *dunningRepository) CreateStrategy(ctx context.Context, strategy *model.DunningStrategy) error {
+	return r.db.WithContext(ctx).Create(strategy).Error
+}
+
+func (r *dunningRepository) UpdateStrategy(ctx context.Context, strategy *model.DunningStrategy) error {
+	return r.db.WithContext(ctx).Transaction(func(trx *gorm.DB) error {
+		if err := trx.Unscoped().Where("strategy_id = ?", strategy.ID).Delete(&model.DunningStep{}).Error; err != nil {
+			return err
+		}
+		for i := range strategy.Steps {
+			strategy.Steps[i].ID = 0
+			strategy.Steps[i].StrategyID = strategy.ID
+		}
+		return trx.Save(strategy).Error
+	})
+}
+
+func (r *dunningRepository) preloadSteps(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("days_past_due")
+	})
+}
+
+func (r *dunningRepository) FindStrategies(ctx context.Context) ([]model.DunningStrategy, error) {
+	var strategies []model.DunningStrategy
+	if err := r.preloadSteps(ctx).Order("id").Find(&strategies).Error; err != nil {
+		return nil, err
+	}
+	return strategies, nil
+}
+
+func (r *dunningRepository) FindStrategyByID(ctx context.Context, ID uint) (*model.DunningStrategy, error) {
+	var strategy model.DunningStrategy
+	if err := r.preloadSteps(ctx).First(&strategy, ID).Error; err != nil {
+		return nil, err
+	}
+	return &strategy, nil
+}
+
+func (r *dunningRepository) FindStrategyByProductCode(ctx context.Context, productCode string) (*model.DunningStrategy, error) {
+	var strategy model.DunningStrategy
+	if err := r.preloadSteps(ctx).Where("product_code = ?", productCode).First(&strategy).Error; err != nil {
+		return nil, err
+	}
+	return &strategy, nil
+}
+
+func (r *dunningRepository) CreateLog(ctx context.Context, log *model.DunningLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+func (r *dunningRepository) FindLogsByBillingID(ctx context.Context, billingID uint) ([]model.DunningLog, error) {
+	var logs []model.DunningLog
+	if err := r.db.WithContext(ctx).Where("billing_id = ?", billingID).Order("id").Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
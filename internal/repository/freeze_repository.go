@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+)
+
+type FreezeRepository interface {
+	WithTransaction(trx *gorm.DB) FreezeRepository
+	WithDB() *gorm.DB
+	Create(ctx context.Context, freeze *model.BillingFreeze) error
+	FindByBillingID(ctx context.Context, billingID uint) ([]model.BillingFreeze, error)
+}
+
+type freezeRepository struct {
+	db *gorm.DB
+}
+
+func NewFreezeRepository(db *gorm.DB) FreezeRepository {
+	return &freezeRepository{db}
+}
+
+func (r *freezeRepository) WithTransaction(trx *gorm.DB) FreezeRepository {
+	return &freezeRepository{trx}
+}
+
+func (r *freezeRepository) WithDB() *gorm.DB {
+	return r.db
+}
+
+func (r *freezeRepository) Create(ctx context.Context, freeze *model.BillingFreeze) error {
+	return r.db.WithContext(ctx).Create(freeze).Error
+}
+
+func (r *freezeRepository) FindByBillingID(ctx context.Context, billingID uint) ([]model.BillingFreeze, error) {
+	var freezes []model.BillingFreeze
+	if err := r.db.WithContext(ctx).Where("billing_id = ?", billingID).Order("start_date").Find(&freezes).Error; err != nil {
+		return nil, err
+	}
+	return freezes, nil
+}
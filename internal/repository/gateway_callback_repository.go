@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GatewayCallbackRepository interface {
+	WithTransaction(trx *gorm.DB) GatewayCallbackRepository
+	WithDB() *gorm.DB
+	CreateIfAbsent(ctx context.Context, callback *model.GatewayCallback) (bool, error)
+	Update(ctx context.Context, callback *model.GatewayCallback) error
+	UpdateIfFailed(ctx context.Context, callback *model.GatewayCallback) (bool, error)
+	FindByExternalID(ctx context.Context, provider, externalID string) (*model.GatewayCallback, error)
+	FindRecent(ctx context.Context, provider, status string, limit int) ([]model.GatewayCallback, error)
+	FindProcessedByExternalID(ctx context.Context, externalID string) (*model.GatewayCallback, error)
+}
+
+type gatewayCallbackRepository struct {
+	db *gorm.DB
+}
+
+func NewGatewayCallbackRepository(db *gorm.DB) GatewayCallbackRepository {
+	return &gatewayCallbackRepository{db}
+}
+
+func (r *gatewayCallbackRepository) WithTransaction(trx *gorm.DB) GatewayCallbackRepository {
+	return &gatewayCallbackRepository{trx}
+}
+
+func (r *gatewayCallbackRepository) WithDB() *gorm.DB {
+	return r.db
+}
+
+func (r *gatewayCallbackRepository) CreateIfAbsent(ctx context.Context, callback *model.GatewayCallback) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(callback)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *gatewayCallbackRepository) Update(ctx context.Context, callback *model.GatewayCallback) error {
+	return r.db.WithContext(ctx).Save(callback).Error
+}
+
+func (r *gatewayCallbackRepository) UpdateIfFailed(ctx context.Context, callback *model.GatewayCallback) (bool, error) {
+	result := r.db.WithContext(ctx).Model(callback).Where("status = ?", model.GatewayCallbackStatusFailed).Select("*").Updates(callback)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *gatewayCallbackRepository) FindByExternalID(ctx context.Context, provider, externalID string) (*model.GatewayCallback, error) {
+	var callback model.GatewayCallback
+	err := r.db.WithContext(ctx).Where("provider = ? AND external_id = ?", provider, externalID).First(&callback).Error
+	if err != nil {
+		return nil, err
+	}
+	return &callback, nil
+}
+
+func (r *gatewayCallbackRepository) FindRecent(ctx context.Context, provider, status string, limit int) ([]model.GatewayCallback, error) {
+	var callbacks []model.GatewayCallback
+	query := r.db.WithContext(ctx).Order("id DESC").Limit(limit)
+	if provider != "" {
+		query = query.Where("provider = ?", provider)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Find(&callbacks).Error; err != nil {
+		return nil, err
+	}
+	return callbacks, nil
+}
+
+func (r *gatewayCallbackRepository) FindProcessedByExternalID(ctx context.Context, externalID string) (*model.GatewayCallback, error) {
+	var callback model.GatewayCallback
+	err := r.db.WithContext(ctx).Where("external_id = ? AND status = ?", externalID, model.GatewayCallbackStatusProcessed).
+		Order("id").First(&callback).Error
+	if err != nil {
+		return nil, err
+	}
+	return &callback, nil
+}
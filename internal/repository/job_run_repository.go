@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+)
+
+type JobRunRepository interface {
+	Create(ctx context.Context, run *model.JobRun) error
+	Update(ctx context.Context, run *model.JobRun) error
+	FindRecent(ctx context.Context, jobName string, limit int) ([]model.JobRun, error)
+	HasCompletedSlot(ctx context.Context, jobName string, slot time.Time) (bool, error)
+}
+
+type jobRunRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRunRepository(db *gorm.DB) JobRunRepository {
+	return &jobRunRepository{db}
+}
+
+func (r *jobRunRepository) Create(ctx context.Context, run *model.JobRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+func (r *jobRunRepository) Update(ctx context.Context, run *model.JobRun) error {
+	return r.db.WithContext(ctx).Save(run).Error
+}
+
+func (r *jobRunRepository) FindRecent(ctx context.Context, jobName string, limit int) ([]model.JobRun, error) {
+	var runs []model.JobRun
+	query := r.db.WithContext(ctx).Order("started_at DESC, id DESC").Limit(limit)
+	if jobName != "" {
+		query = query.Where("job_name = ?", jobName)
+	}
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (r *jobRunRepository) HasCompletedSlot(ctx context.Context, jobName string, slot time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.JobRun{}).
+		Where("job_name = ? AND slot = ? AND status = ?", jobName, slot, model.JobRunStatusSuccess).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+)
+
+type LoanImportRepository interface {
+	WithTransaction(trx *gorm.DB) LoanImportRepository
+	WithDB() *gorm.DB
+	CreateImport(ctx context.Context, loanImport *model.LoanImport, rows []model.LoanImportRow) error
+	UpdateImport(ctx context.Context, loanImport *model.LoanImport) error
+	UpdateRow(ctx context.Context, row *model.LoanImportRow) error
+	FindImportByID(ctx context.Context, id uint, status string) (*model.LoanImport, error)
+	FindPendingImports(ctx context.Context, limit int) ([]model.LoanImport, error)
+	FindPendingRows(ctx context.Context, importID uint, limit int) ([]model.LoanImportRow, error)
+	ResetFailedRows(ctx context.Context, importID uint) (int64, error)
+	CountRowsByStatus(ctx context.Context, importID uint) (map[string]int, error)
+}
+
+type loanImportRepository struct {
+	db *gorm.DB
+}
+
+func NewLoanImportRepository(db *gorm.DB) LoanImportRepository {
+	return &loanImportRepository{db}
+}
+
+func (r *loanImportRepository) WithTransaction(trx *gorm.DB) LoanImportRepository {
+	return &loanImportRepository{trx}
+}
+
+func (r *loanImportRepository) WithDB() *gorm.DB {
+	return r.db
+}
+
+func (r *loanImportRepository) CreateImport(ctx context.Context, loanImport *model.LoanImport, rows []model.LoanImportRow) error {
+	return r.db.WithContext(ctx).Transaction(func(trx *gorm.DB) error {
+		if err := trx.Omit("Rows").Create(loanImport).Error; err != nil {
+			return err
+		}
+		for i := range rows {
+			rows[i].ImportID = loanImport.ID
+		}
+		return trx.CreateInBatches(rows, 500).Error
+	})
+}
+
+func (r *loanImportRepository) UpdateImport(ctx context.Context, loanImport *model.LoanImport) error {
+	return r.db.WithContext(ctx).Omit("Rows").Save(loanImport).Error
+}
+
+func (r *loanImportRepository) UpdateRow(ctx context.Context, row *model.LoanImportRow) error {
+	return r.db.WithContext(ctx).Save(row).Error
+}
+
+func (r *loanImportRepository) FindImportByID(ctx context.Context, id uint, status string) (*model.LoanImport, error) {
+	var loanImport model.LoanImport
+	err := r.db.WithContext(ctx).Preload("Rows", func(db *gorm.DB) *gorm.DB {
+		if status != "" {
+			db = db.Where("status = ?", status)
+		}
+		return db.Order("row_no")
+	}).First(&loanImport, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &loanImport, nil
+}
+
+func (r *loanImportRepository) FindPendingImports(ctx context.Context, limit int) ([]model.LoanImport, error) {
+	var loanImports []model.LoanImport
+	err := r.db.WithContext(ctx).
+		Where("status IN ?", []string{model.LoanImportStatusPending, model.LoanImportStatusRunning}).
+		Order("id").Limit(limit).Find(&loanImports).Error
+	if err != nil {
+		return nil, err
+	}
+	return loanImports, nil
+}
+
+func (r *loanImportRepository) FindPendingRows(ctx context.Context, importID uint, limit int) ([]model.LoanImportRow, error) {
+	var rows []model.LoanImportRow
+	err := r.db.WithContext(ctx).Where("import_id = ? AND status = ?", importID, model.LoanImportRowStatusPending).
+		Order("row_no").Limit(limit).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *loanImportRepository) ResetFailedRows(ctx context.Context, importID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.LoanImportRow{}).
+		Where("import_id = ? AND status = ?", importID, model.LoanImportRowStatusFailed).
+		Updates(map[string]any{"status": model.LoanImportRowStatusPending, "reason": ""})
+	return result.RowsAffected, result.Error
+}
+
+func (r *loanImportRepository) CountRowsByStatus(ctx context.Context, importID uint) (map[string]int, error) {
+	var results []struct {
+		Status string
+		Count  int
+	}
+	err := r.db.WithContext(ctx).Model(&model.LoanImportRow{}).Select("status, COUNT(*) AS count").
+		Where("import_id = ?", importID).Group("status").Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(results))
+	for _, result := range results {
+		counts[result.Status] = result.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+)
+
+type OutboxRepository interface {
+	WithTransaction(trx *gorm.DB) OutboxRepository
+	Create(ctx context.Context, event *model.OutboxEvent) error
+	FindPending(ctx context.Context, limit int) ([]model.OutboxEvent, error)
+	MarkPublished(ctx context.Context, ID uint, publishedAt time.Time) error
+	MarkFailed(ctx context.Context, ID uint, lastError string) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db}
+}
+
+func (r *outboxRepository) WithTransaction(trx *gorm.DB) OutboxRepository {
+	return &outboxRepository{trx}
+}
+
+func (r *outboxRepository) Create(ctx context.Context, event *model.OutboxEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *outboxRepository) FindPending(ctx context.Context, limit int) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	err := r.db.WithContext(ctx).
+		Where("status = ?", model.OutboxStatusPending).
+		Where("NOT EXISTS (SELECT 1 FROM outbox_events failed WHERE failed.billing_id = outbox_events.billing_id AND failed.status = ? AND failed.attempts > 0 AND failed.id < outbox_events.id)", model.OutboxStatusPending).
+		Order("attempts").Order("id").
+		Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, ID uint, publishedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.OutboxEvent{}).Where("id = ?", ID).Updates(map[string]any{
+		"status":       model.OutboxStatusPublished,
+		"published_at": publishedAt,
+		"attempts":     gorm.Expr("attempts + 1"),
+		"last_error":   "",
+	}).Error
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, ID uint, lastError string) error {
+	return r.db.WithContext(ctx).Model(&model.OutboxEvent{}).Where("id = ?", ID).Updates(map[string]any{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": lastError,
+	}).Error
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+)
+
+type PaymentBatchRepository interface {
+	WithTransaction(trx *gorm.DB) PaymentBatchRepository
+	WithDB() *gorm.DB
+	CreateBatch(ctx context.Context, batch *model.PaymentBatch, rows []model.PaymentBatchRow) error
+	UpdateBatch(ctx context.Context, batch *model.PaymentBatch) error
+	UpdateRow(ctx context.Context, row *model.PaymentBatchRow) error
+	FindBatchByID(ctx context.Context, id uint, status string) (*model.PaymentBatch, error)
+	ClaimBatch(ctx context.Context, batch *model.PaymentBatch, now, leaseExpiresAt time.Time) (bool, error)
+	RenewLease(ctx context.Context, batchID uint, leaseExpiresAt time.Time) error
+	FindPendingBatches(ctx context.Context, now time.Time, limit int) ([]model.PaymentBatch, error)
+	FindPendingRows(ctx context.Context, batchID uint) ([]model.PaymentBatchRow, error)
+	ExistsAppliedReference(ctx context.Context, reference string) (bool, error)
+	CountRowsByStatus(ctx context.Context, batchID uint) (map[string]int, error)
+}
+
+type paymentBatchRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentBatchRepository(db *gorm.DB) PaymentBatchRepository {
+	return &paymentBatchRepository{db}
+}
+
+func (r *paymentBatchRepository) WithTransaction(trx *gorm.DB) PaymentBatchRepository {
+	return &paymentBatchRepository{trx}
+}
+
+func (r *paymentBatchRepository) WithDB() *gorm.DB {
+	return r.db
+}
+
+func (r *paymentBatchRepository) CreateBatch(ctx context.Context, batch *model.PaymentBatch, rows []model.PaymentBatchRow) error {
+	return r.db.WithContext(ctx).Transaction(func(trx *gorm.DB) error {
+		if err := trx.Omit("Rows").Create(batch).Error; err != nil {
+			return err
+		}
+		for i := range rows {
+			rows[i].BatchID = batch.ID
+		}
+		return trx.CreateInBatches(rows, 500).Error
+	})
+}
+
+func (r *paymentBatchRepository) UpdateBatch(ctx context.Context, batch *model.PaymentBatch) error {
+	return r.db.WithContext(ctx).Omit("Rows").Save(batch).Error
+}
+
+func (r *paymentBatchRepository) UpdateRow(ctx context.Context, row *model.PaymentBatchRow) error {
+	return r.db.WithContext(ctx).Save(row).Error
+}
+
+func (r *paymentBatchRepository) FindBatchByID(ctx context.Context, id uint, status string) (*model.PaymentBatch, error) {
+	var batch model.PaymentBatch
+	err := r.db.WithContext(ctx).Preload("Rows", func(db *gorm.DB) *gorm.DB {
+		if status != "" {
+			db = db.Where("status = ?", status)
+		}
+		return db.Order("row_no")
+	}).First(&batch, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (r *paymentBatchRepository) ClaimBatch(ctx context.Context, batch *model.PaymentBatch, now, leaseExpiresAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.PaymentBatch{}).
+		Where("id = ? AND (status = ? OR (status = ? AND lease_expires_at < ?))", batch.ID, model.PaymentBatchStatusPending, model.PaymentBatchStatusRunning, now).
+		Updates(map[string]any{
+			"status":           model.PaymentBatchStatusRunning,
+			"started_at":       gorm.Expr("COALESCE(started_at, ?)", now),
+			"lease_expires_at": leaseExpiresAt,
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	return true, r.db.WithContext(ctx).First(batch, batch.ID).Error
+}
+
+func (r *paymentBatchRepository) RenewLease(ctx context.Context, batchID uint, leaseExpiresAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.PaymentBatch{}).Where("id = ?", batchID).Update("lease_expires_at", leaseExpiresAt).Error
+}
+
+func (r *paymentBatchRepository) FindPendingBatches(ctx context.Context, now time.Time, limit int) ([]model.PaymentBatch, error) {
+	var batches []model.PaymentBatch
+	err := r.db.WithContext(ctx).
+		Where("status = ? OR (status = ? AND lease_expires_at < ?)", model.PaymentBatchStatusPending, model.PaymentBatchStatusRunning, now).
+		Order("id").Limit(limit).Find(&batches).Error
+	if err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+func (r *paymentBatchRepository) FindPendingRows(ctx context.Context, batchID uint) ([]model.PaymentBatchRow, error) {
+	var rows []model.PaymentBatchRow
+	err := r.db.WithContext(ctx).Where("batch_id = ? AND status = ?", batchID, model.PaymentBatchRowStatusPending).
+		Order("row_no").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *paymentBatchRepository) ExistsAppliedReference(ctx context.Context, reference string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.PaymentBatchRow{}).
+		Where("reference = ? AND status = ?", reference, model.PaymentBatchRowStatusApplied).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *paymentBatchRepository) CountRowsByStatus(ctx context.Context, batchID uint) (map[string]int, error) {
+	var results []struct {
+		Status string
+		Count  int
+	}
+	err := r.db.WithContext(ctx).Model(&model.PaymentBatchRow{}).Select("status, COUNT(*) AS count").
+		Where("batch_id = ?", batchID).Group("status").Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(results))
+	for _, result := range results {
+		counts[result.Status] = result.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentPromiseRepository interface {
+	WithTransaction(trx *gorm.DB) PaymentPromiseRepository
+	WithDB() *gorm.DB
+	Create(ctx context.Context, promise *model.PaymentPromise) error
+	Update(ctx context.Context, promise *model.PaymentPromise) error
+	FindPendingByBillingIDForUpdate(ctx context.Context, billingID uint) (*model.PaymentPromise, error)
+	FindByBillingID(ctx context.Context, billingID uint) ([]model.PaymentPromise, error)
+	MarkExpiredBroken(ctx context.Context, now time.Time) (int64, error)
+}
+
+type paymentPromiseRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentPromiseRepository(db *gorm.DB) PaymentPromiseRepository {
+	return &paymentPromiseRepository{db}
+}
+
+func (r *paymentPromiseRepository) WithTransaction(trx *gorm.DB) PaymentPromiseRepository {
+	return &paymentPromiseRepository{trx}
+}
+
+func (r *paymentPromiseRepository) WithDB() *gorm.DB {
+	return r.db
+}
+
+func (r *paymentPromiseRepository) Create(ctx context.Context, promise *model.PaymentPromise) error {
+	return r.db.WithContext(ctx).Create(promise).Error
+}
+
+func (r *paymentPromiseRepository) Update(ctx context.Context, promise *model.PaymentPromise) error {
+	return r.db.WithContext(ctx).Save(promise).Error
+}
+
+func (r *paymentPromiseRepository) FindPendingByBillingIDForUpdate(ctx context.Context, billingID uint) (*model.PaymentPromise, error) {
+	var promise model.PaymentPromise
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("billing_id = ? AND status = ?", billingID, model.PaymentPromiseStatusPending).
+		First(&promise).Error
+	if err != nil {
+		return nil, err
+	}
+	return &promise, nil
+}
+
+func (r *paymentPromiseRepository) FindByBillingID(ctx context.Context, billingID uint) ([]model.PaymentPromise, error) {
+	var promises []model.PaymentPromise
+	if err := r.db.WithContext(ctx).Where("billing_id = ?", billingID).Order("promised_at").Find(&promises).Error; err != nil {
+		return nil, err
+	}
+	return promises, nil
+}
+
+func (r *paymentPromiseRepository) MarkExpiredBroken(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.PaymentPromise{}).
+		Where("status = ? AND expires_at <= ?", model.PaymentPromiseStatusPending, now).
+		Updates(map[string]any{
+			"status":      model.PaymentPromiseStatusBroken,
+			"resolved_at": now,
+		})
+	return result.RowsAffected, result.Error
+}
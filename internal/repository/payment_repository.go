@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/doddeeph/billing-engine/internal/model"
 	"gorm.io/gorm"
@@ -11,7 +12,10 @@ type PaymentRepository interface {
 	WithTransaction(trx *gorm.DB) PaymentRepository
 	WithDB() *gorm.DB
 	FindByBillingIdAndWeek(ctx context.Context, billingID uint, week int) (*model.Payment, error)
+	FindFirstUnpaid(ctx context.Context, billingID uint) (*model.Payment, error)
 	UpdatePaid(ctx context.Context, payment *model.Payment) (*model.Payment, error)
+	ShiftUnpaid(ctx context.Context, billingID uint, from time.Time, weeks int, now time.Time) (int64, error)
+	MarkOverdue(ctx context.Context, billingID uint, before time.Time, lateFee int) (int64, error)
 }
 
 type paymentRepository struct {
@@ -39,9 +43,39 @@ func (r *paymentRepository) FindByBillingIdAndWeek(ctx context.Context, billingI
 	return &payment, nil
 }
 
+func (r *paymentRepository) FindFirstUnpaid(ctx context.Context, billingID uint) (*model.Payment, error) {
+	var payment model.Payment
+	err := r.db.WithContext(ctx).Where("billing_id = ? AND paid = ?", billingID, false).Order("week").First(&payment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
 func (r *paymentRepository) UpdatePaid(ctx context.Context, payment *model.Payment) (*model.Payment, error) {
+	payment.Revision++
 	if err := r.db.WithContext(ctx).Save(&payment).Error; err != nil {
 		return nil, err
 	}
 	return payment, nil
 }
+
+func (r *paymentRepository) ShiftUnpaid(ctx context.Context, billingID uint, from time.Time, weeks int, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.Payment{}).
+		Where("billing_id = ? AND paid = ? AND due_date >= ?", billingID, false, from).
+		Updates(map[string]any{
+			"start_date": gorm.Expr("start_date + make_interval(weeks => ?)", weeks),
+			"due_date":   gorm.Expr("due_date + make_interval(weeks => ?)", weeks),
+			"overdue":    gorm.Expr("CASE WHEN due_date + make_interval(weeks => ?) > ? THEN false ELSE overdue END", weeks, now),
+			"late_fee":   gorm.Expr("CASE WHEN due_date + make_interval(weeks => ?) > ? THEN 0 ELSE late_fee END", weeks, now),
+			"revision":   gorm.Expr("revision + 1"),
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *paymentRepository) MarkOverdue(ctx context.Context, billingID uint, before time.Time, lateFee int) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.Payment{}).
+		Where("billing_id = ? AND paid = ? AND overdue = ? AND due_date < ?", billingID, false, false, before).
+		Updates(map[string]any{"overdue": true, "late_fee": lateFee, "revision": gorm.Expr("revision + 1")})
+	return result.RowsAffected, result.Error
+}
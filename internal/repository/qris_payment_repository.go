@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+)
+
+type QRISPaymentRepository interface {
+	Create(ctx context.Context, payment *model.QRISPayment) error
+	FindByReference(ctx context.Context, reference string) (*model.QRISPayment, error)
+}
+
+type qrisPaymentRepository struct {
+	db *gorm.DB
+}
+
+func NewQRISPaymentRepository(db *gorm.DB) QRISPaymentRepository {
+	return &qrisPaymentRepository{db}
+}
+
+func (r *qrisPaymentRepository) Create(ctx context.Context, payment *model.QRISPayment) error {
+	return r.db.WithContext(ctx).Create(payment).Error
+}
+
+func (r *qrisPaymentRepository) FindByReference(ctx context.Context, reference string) (*model.QRISPayment, error) {
+	var payment model.QRISPayment
+	if err := r.db.WithContext(ctx).Where("reference = ?", reference).First(&payment).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+)
+
+type RecoveryRepository interface {
+	WithTransaction(trx *gorm.DB) RecoveryRepository
+	Create(ctx context.Context, recovery *model.Recovery) error
+	FindByBillingID(ctx context.Context, billingID uint) ([]model.Recovery, error)
+}
+
+type recoveryRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryRepository(db *gorm.DB) RecoveryRepository {
+	return &recoveryRepository{db}
+}
+
+func (r *recoveryRepository) WithTransaction(trx *gorm.DB) RecoveryRepository {
+	return &recoveryRepository{trx}
+}
+
+func (r *recoveryRepository) Create(ctx context.Context, recovery *model.Recovery) error {
+	return r.db.WithContext(ctx).Create(recovery).Error
+}
+
+func (r *recoveryRepository) FindByBillingID(ctx context.Context, billingID uint) ([]model.Recovery, error) {
+	var recoveries []model.Recovery
+	if err := r.db.WithContext(ctx).Where("billing_id = ?", billingID).Order("recovered_at").Find(&recoveries).Error; err != nil {
+		return nil, err
+	}
+	return recoveries, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReminderTarget struct {
+	PaymentID     uint
+	BillingID     uint
+	CustomerID    uint
+	LoanID        uint
+	Week          int
+	Amount        int
+	LateFee       int
+	DueDate       time.Time
+	Paid          bool
+	BillingStatus string
+}
+
+type ReminderRepository interface {
+	FindTargets(ctx context.Context, dueFrom, dueTo time.Time, afterPaymentID uint, limit int) ([]ReminderTarget, error)
+	FindTarget(ctx context.Context, paymentID uint) (*ReminderTarget, error)
+	CreateIfAbsent(ctx context.Context, reminders []model.Reminder) (int, error)
+	FindDue(ctx context.Context, now time.Time, limit int) ([]model.Reminder, error)
+	Update(ctx context.Context, reminder *model.Reminder) error
+	FindByBillingID(ctx context.Context, billingID uint) ([]model.Reminder, error)
+	FindRecent(ctx context.Context, status string, limit int) ([]model.Reminder, error)
+}
+
+type reminderRepository struct {
+	db *gorm.DB
+}
+
+func NewReminderRepository(db *gorm.DB) ReminderRepository {
+	return &reminderRepository{db}
+}
+
+func (r *reminderRepository) targets(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.Payment{}).
+		Select("payments.id AS payment_id, payments.billing_id, billings.customer_id, billings.loan_id, payments.week, payments.amount, payments.late_fee, payments.due_date, payments.paid, billings.status AS billing_status").
+		Joins("JOIN billings ON billings.id = payments.billing_id AND billings.deleted_at IS NULL")
+}
+
+func (r *reminderRepository) FindTargets(ctx context.Context, dueFrom, dueTo time.Time, afterPaymentID uint, limit int) ([]ReminderTarget, error) {
+	var targets []ReminderTarget
+	err := r.targets(ctx).
+		Where("payments.paid = ? AND billings.status = ?", false, model.BillingStatusActive).
+		Where("payments.due_date BETWEEN ? AND ? AND payments.id > ?", dueFrom, dueTo, afterPaymentID).
+		Order("payments.id").Limit(limit).
+		Scan(&targets).Error
+	if err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+func (r *reminderRepository) FindTarget(ctx context.Context, paymentID uint) (*ReminderTarget, error) {
+	var targets []ReminderTarget
+	if err := r.targets(ctx).Where("payments.id = ?", paymentID).Scan(&targets).Error; err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &targets[0], nil
+}
+
+func (r *reminderRepository) CreateIfAbsent(ctx context.Context, reminders []model.Reminder) (int, error) {
+	if len(reminders) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "payment_id"}, {Name: "due_date"}, {Name: "day_offset"}, {Name: "channel"}},
+		DoNothing: true,
+	}).Create(&reminders)
+	return int(result.RowsAffected), result.Error
+}
+
+func (r *reminderRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]model.Reminder, error) {
+	var reminders []model.Reminder
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", model.ReminderStatusScheduled, now).
+		Order("next_attempt_at, id").Limit(limit).Find(&reminders).Error
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+func (r *reminderRepository) Update(ctx context.Context, reminder *model.Reminder) error {
+	return r.db.WithContext(ctx).Save(reminder).Error
+}
+
+func (r *reminderRepository) FindByBillingID(ctx context.Context, billingID uint) ([]model.Reminder, error) {
+	var reminders []model.Reminder
+	if err := r.db.WithContext(ctx).Where("billing_id = ?", billingID).Order("scheduled_at, id").Find(&reminders).Error; err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+func (r *reminderRepository) FindRecent(ctx context.Context, status string, limit int) ([]model.Reminder, error) {
+	var reminders []model.Reminder
+	query := r.db.WithContext(ctx)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("id DESC").Limit(limit).Find(&reminders).Error; err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
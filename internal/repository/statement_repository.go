@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StatementRepository interface {
+	WithTransaction(trx *gorm.DB) StatementRepository
+	WithDB() *gorm.DB
+	CreateImportIfAbsent(ctx context.Context, statementImport *model.StatementImport) (bool, error)
+	UpdateImport(ctx context.Context, statementImport *model.StatementImport) error
+	CreateLines(ctx context.Context, lines []model.StatementLine) error
+	UpdatePendingLine(ctx context.Context, line *model.StatementLine) (bool, error)
+	FindImportByID(ctx context.Context, id uint, status string) (*model.StatementImport, error)
+	FindImportByChecksum(ctx context.Context, checksum string) (*model.StatementImport, error)
+	FindImports(ctx context.Context, limit int) ([]model.StatementImport, error)
+	FindLineByPaymentID(ctx context.Context, paymentID uint) (*model.StatementLine, error)
+	FindUnreconciledPayments(ctx context.Context, billingID uint, amount int, from, to time.Time) ([]model.Payment, error)
+	FindUnpaidByAmountDue(ctx context.Context, amount int, limit int) ([]model.Payment, error)
+}
+
+type statementRepository struct {
+	db *gorm.DB
+}
+
+func NewStatementRepository(db *gorm.DB) StatementRepository {
+	return &statementRepository{db}
+}
+
+func (r *statementRepository) WithTransaction(trx *gorm.DB) StatementRepository {
+	return &statementRepository{trx}
+}
+
+func (r *statementRepository) WithDB() *gorm.DB {
+	return r.db
+}
+
+func (r *statementRepository) CreateImportIfAbsent(ctx context.Context, statementImport *model.StatementImport) (bool, error) {
+	result := r.db.WithContext(ctx).Omit("Lines").Clauses(clause.OnConflict{DoNothing: true}).Create(statementImport)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *statementRepository) UpdateImport(ctx context.Context, statementImport *model.StatementImport) error {
+	return r.db.WithContext(ctx).Omit("Lines").Save(statementImport).Error
+}
+
+func (r *statementRepository) CreateLines(ctx context.Context, lines []model.StatementLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&lines).Error
+}
+
+func (r *statementRepository) UpdatePendingLine(ctx context.Context, line *model.StatementLine) (bool, error) {
+	result := r.db.WithContext(ctx).Model(line).Where("status = ?", model.StatementLineStatusPending).Select("*").Updates(line)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *statementRepository) FindImportByID(ctx context.Context, id uint, status string) (*model.StatementImport, error) {
+	var statementImport model.StatementImport
+	err := r.db.WithContext(ctx).Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		if status != "" {
+			db = db.Where("status = ?", status)
+		}
+		return db.Order("line_no")
+	}).First(&statementImport, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &statementImport, nil
+}
+
+func (r *statementRepository) FindImportByChecksum(ctx context.Context, checksum string) (*model.StatementImport, error) {
+	var statementImport model.StatementImport
+	err := r.db.WithContext(ctx).Where("checksum = ?", checksum).First(&statementImport).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.FindImportByID(ctx, statementImport.ID, "")
+}
+
+func (r *statementRepository) FindImports(ctx context.Context, limit int) ([]model.StatementImport, error) {
+	var statementImports []model.StatementImport
+	if err := r.db.WithContext(ctx).Order("id DESC").Limit(limit).Find(&statementImports).Error; err != nil {
+		return nil, err
+	}
+	return statementImports, nil
+}
+
+func (r *statementRepository) FindLineByPaymentID(ctx context.Context, paymentID uint) (*model.StatementLine, error) {
+	var line model.StatementLine
+	if err := r.db.WithContext(ctx).Where("payment_id = ?", paymentID).First(&line).Error; err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
+
+func (r *statementRepository) FindUnreconciledPayments(ctx context.Context, billingID uint, amount int, from, to time.Time) ([]model.Payment, error) {
+	var payments []model.Payment
+	query := r.db.WithContext(ctx).
+		Where("paid = ? AND amount + late_fee = ? AND paid_date >= ? AND paid_date < ?", true, amount, from, to).
+		Where("NOT EXISTS (SELECT 1 FROM statement_lines sl WHERE sl.payment_id = payments.id AND sl.deleted_at IS NULL)")
+	if billingID != 0 {
+		query = query.Where("billing_id = ?", billingID)
+	}
+	if err := query.Order("paid_date").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *statementRepository) FindUnpaidByAmountDue(ctx context.Context, amount int, limit int) ([]model.Payment, error) {
+	var payments []model.Payment
+	err := r.db.WithContext(ctx).
+		Joins("JOIN billings b ON b.id = payments.billing_id AND b.deleted_at IS NULL").
+		Where("b.status = ? AND payments.paid = ? AND payments.amount + payments.late_fee = ?", model.BillingStatusActive, false, amount).
+		Where("payments.week = (SELECT MIN(p.week) FROM payments p WHERE p.billing_id = payments.billing_id AND p.paid = ? AND p.deleted_at IS NULL)", false).
+		Order("payments.billing_id").Limit(limit).Find(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VirtualAccountRepository interface {
+	WithTransaction(trx *gorm.DB) VirtualAccountRepository
+	CreateIfAbsent(ctx context.Context, virtualAccount *model.VirtualAccount) error
+	FindByNumber(ctx context.Context, number string) (*model.VirtualAccount, error)
+	FindByBillingID(ctx context.Context, billingID uint) ([]model.VirtualAccount, error)
+}
+
+type virtualAccountRepository struct {
+	db *gorm.DB
+}
+
+func NewVirtualAccountRepository(db *gorm.DB) VirtualAccountRepository {
+	return &virtualAccountRepository{db}
+}
+
+func (r *virtualAccountRepository) WithTransaction(trx *gorm.DB) VirtualAccountRepository {
+	return &virtualAccountRepository{trx}
+}
+
+func (r *virtualAccountRepository) CreateIfAbsent(ctx context.Context, virtualAccount *model.VirtualAccount) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "billing_id"}, {Name: "bank_code"}},
+		DoNothing: true,
+	}).Create(virtualAccount).Error
+}
+
+func (r *virtualAccountRepository) FindByNumber(ctx context.Context, number string) (*model.VirtualAccount, error) {
+	var virtualAccount model.VirtualAccount
+	if err := r.db.WithContext(ctx).Where("number = ?", number).First(&virtualAccount).Error; err != nil {
+		return nil, err
+	}
+	return &virtualAccount, nil
+}
+
+func (r *virtualAccountRepository) FindByBillingID(ctx context.Context, billingID uint) ([]model.VirtualAccount, error) {
+	var virtualAccounts []model.VirtualAccount
+	if err := r.db.WithContext(ctx).Where("billing_id = ?", billingID).Order("id").Find(&virtualAccounts).Error; err != nil {
+		return nil, err
+	}
+	return virtualAccounts, nil
+}
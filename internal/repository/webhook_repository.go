@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *model.WebhookSubscription) error
+	UpdateSubscription(ctx context.Context, subscription *model.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, ID uint) error
+	FindSubscriptionByID(ctx context.Context, ID uint) (*model.WebhookSubscription, error)
+	FindSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	FindActiveSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error)
+	CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+	UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+	FindDeliveryByID(ctx context.Context, ID uint) (*model.WebhookDelivery, error)
+	FindDeliveries(ctx context.Context, subscriptionID uint, status string, limit int) ([]model.WebhookDelivery, error)
+	FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription *model.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Create(subscription).Error
+}
+
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, subscription *model.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Save(subscription).Error
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, ID uint) error {
+	return r.db.WithContext(ctx).Delete(&model.WebhookSubscription{}, ID).Error
+}
+
+func (r *webhookRepository) FindSubscriptionByID(ctx context.Context, ID uint) (*model.WebhookSubscription, error) {
+	var subscription model.WebhookSubscription
+	if err := r.db.WithContext(ctx).First(&subscription, ID).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *webhookRepository) FindSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+	if err := r.db.WithContext(ctx).Order("id").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) FindActiveSubscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	var subscriptions []model.WebhookSubscription
+	if err := r.db.WithContext(ctx).Where("active = ?", true).Order("id").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(delivery).Error
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Omit("Subscription").Save(delivery).Error
+}
+
+func (r *webhookRepository) FindDeliveryByID(ctx context.Context, ID uint) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	if err := r.db.WithContext(ctx).First(&delivery, ID).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) FindDeliveries(ctx context.Context, subscriptionID uint, status string, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	query := r.db.WithContext(ctx).Order("id DESC").Limit(limit)
+	if subscriptionID != 0 {
+		query = query.Where("subscription_id = ?", subscriptionID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepository) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := r.db.WithContext(ctx).Preload("Subscription").
+		Where("status = ? AND next_attempt_at <= ?", model.WebhookDeliveryStatusPending, now).
+		Order("next_attempt_at, id").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
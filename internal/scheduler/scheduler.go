@@ -0,0 +1,174 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/lock"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/robfig/cron/v3"
+)
+
+type JobResult struct {
+	Processed int
+	Failed    int
+}
+
+type JobFunc func(ctx context.Context) (JobResult, error)
+
+type job struct {
+	name string
+	fn   JobFunc
+	mu   sync.Mutex
+}
+
+type Scheduler struct {
+	cfg     *config.SchedulerConfig
+	loc     *time.Location
+	now     func() time.Time
+	cron    *cron.Cron
+	runRepo repository.JobRunRepository
+	locker  lock.Locker
+	jobs    map[string]*job
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewScheduler(runRepo repository.JobRunRepository, locker lock.Locker, cfg *config.SchedulerConfig) (*Scheduler, error) {
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scheduler timezone: %s", err.Error())
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		cfg:     cfg,
+		loc:     loc,
+		now:     time.Now,
+		cron:    cron.New(cron.WithLocation(loc)),
+		runRepo: runRepo,
+		locker:  locker,
+		jobs:    make(map[string]*job),
+		ctx:     ctx,
+		cancel:  cancel,
+	}, nil
+}
+
+func (s *Scheduler) Register(name string, fn JobFunc) error {
+	j := &job{name: name, fn: fn}
+	s.jobs[name] = j
+	spec := s.cfg.Jobs[name]
+	if spec == "" {
+		log.Printf("Job %s has no schedule, it can only be triggered manually", name)
+		return nil
+	}
+	if _, err := s.cron.AddFunc(spec, func() { s.execute(j) }); err != nil {
+		return fmt.Errorf("invalid schedule %q for job %s: %s", spec, name, err.Error())
+	}
+	log.Printf("Job %s scheduled at %q", name, spec)
+	return nil
+}
+
+func (s *Scheduler) Start() {
+	if !s.cfg.Enabled {
+		log.Println("Scheduler is disabled.")
+		return
+	}
+	s.cron.Start()
+	log.Println("Scheduler started.")
+}
+
+func (s *Scheduler) Stop() {
+	s.cancel()
+	<-s.cron.Stop().Done()
+	s.wg.Wait()
+	log.Println("Scheduler stopped.")
+}
+
+func (s *Scheduler) Trigger(name string) error {
+	j, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("Job %s is not registered.", name)
+	}
+	if !j.mu.TryLock() {
+		return fmt.Errorf("Job %s is already running.", name)
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer j.mu.Unlock()
+		s.run(j, nil)
+	}()
+	return nil
+}
+
+func (s *Scheduler) execute(j *job) {
+	if !j.mu.TryLock() {
+		log.Printf("Job %s is still running, skipping this schedule", j.name)
+		return
+	}
+	defer j.mu.Unlock()
+	s.wg.Add(1)
+	defer s.wg.Done()
+	slot := s.now().In(s.loc).Truncate(time.Minute)
+	s.run(j, &slot)
+}
+
+func (s *Scheduler) run(j *job, slot *time.Time) {
+	jobLock, acquired, err := s.locker.TryLock(s.ctx, "job:"+j.name)
+	if err != nil {
+		log.Printf("Failed to acquire lock for job %s: %v", j.name, err)
+		return
+	}
+	if !acquired {
+		log.Printf("Job %s is running on another instance, skipping", j.name)
+		return
+	}
+	defer func() {
+		if err := jobLock.Release(context.Background()); err != nil {
+			log.Printf("Failed to release lock for job %s: %v", j.name, err)
+		}
+	}()
+
+	if slot != nil {
+		completed, err := s.runRepo.HasCompletedSlot(s.ctx, j.name, *slot)
+		if err != nil {
+			log.Printf("Failed to check slot %s of job %s: %v", slot.Format(time.RFC3339), j.name, err)
+			return
+		}
+		if completed {
+			log.Printf("Job %s already completed the %s run, skipping", j.name, slot.Format(time.RFC3339))
+			return
+		}
+	}
+
+	run := &model.JobRun{
+		JobName:   j.name,
+		Slot:      slot,
+		Status:    model.JobRunStatusRunning,
+		StartedAt: time.Now(),
+	}
+	if err := s.runRepo.Create(s.ctx, run); err != nil {
+		log.Printf("Failed to record start of job %s: %v", j.name, err)
+		return
+	}
+	result, err := j.fn(s.ctx)
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Processed = result.Processed
+	run.Failed = result.Failed
+	run.Status = model.JobRunStatusSuccess
+	if err != nil {
+		run.Status = model.JobRunStatusFailed
+		run.Error = err.Error()
+	}
+	if err := s.runRepo.Update(context.Background(), run); err != nil {
+		log.Printf("Failed to record finish of job %s: %v", j.name, err)
+	}
+	log.Printf("Job %s finished with status %s: %d processed, %d failed", j.name, run.Status, run.Processed, run.Failed)
+}
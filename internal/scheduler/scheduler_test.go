@@ -0,0 +1,190 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/lock"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeJobRunRepository struct {
+	mu   sync.Mutex
+	runs []model.JobRun
+}
+
+func (r *fakeJobRunRepository) Create(ctx context.Context, run *model.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run.ID = uint(len(r.runs) + 1)
+	r.runs = append(r.runs, *run)
+	return nil
+}
+
+func (r *fakeJobRunRepository) Update(ctx context.Context, run *model.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs[run.ID-1] = *run
+	return nil
+}
+
+func (r *fakeJobRunRepository) FindRecent(ctx context.Context, jobName string, limit int) ([]model.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]model.JobRun(nil), r.runs...), nil
+}
+
+func (r *fakeJobRunRepository) HasCompletedSlot(ctx context.Context, jobName string, slot time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, run := range r.runs {
+		if run.JobName == jobName && run.Slot != nil && run.Slot.Equal(slot) && run.Status == model.JobRunStatusSuccess {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+type fakeLock struct {
+	locker *fakeLocker
+	name   string
+}
+
+func (l *fakeLocker) TryLock(ctx context.Context, name string) (lock.Lock, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held == nil {
+		l.held = make(map[string]bool)
+	}
+	if l.held[name] {
+		return nil, false, nil
+	}
+	l.held[name] = true
+	return &fakeLock{locker: l, name: name}, true, nil
+}
+
+func (l *fakeLock) Release(ctx context.Context) error {
+	l.locker.mu.Lock()
+	defer l.locker.mu.Unlock()
+	delete(l.locker.held, l.name)
+	return nil
+}
+
+func TestRegister_InvalidSchedule(t *testing.T) {
+	cfg := &config.SchedulerConfig{Timezone: "Asia/Jakarta", Jobs: map[string]string{"broken": "not a cron"}}
+	s, err := NewScheduler(&fakeJobRunRepository{}, &fakeLocker{}, cfg)
+	assert.NoError(t, err)
+
+	err = s.Register("broken", func(ctx context.Context) (JobResult, error) { return JobResult{}, nil })
+	assert.Error(t, err)
+}
+
+func TestTrigger_RecordsJobRun(t *testing.T) {
+	repo := &fakeJobRunRepository{}
+	cfg := &config.SchedulerConfig{Timezone: "Asia/Jakarta", Jobs: map[string]string{"sweep": "55 23 * * *"}}
+	s, err := NewScheduler(repo, &fakeLocker{}, cfg)
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Register("sweep", func(ctx context.Context) (JobResult, error) {
+		return JobResult{Processed: 3, Failed: 1}, errors.New("billing 2: boom")
+	}))
+	assert.NoError(t, s.Trigger("sweep"))
+	s.Stop()
+
+	runs, _ := repo.FindRecent(context.Background(), "sweep", 10)
+	if assert.Len(t, runs, 1) {
+		assert.Equal(t, "sweep", runs[0].JobName)
+		assert.Equal(t, model.JobRunStatusFailed, runs[0].Status)
+		assert.Equal(t, 3, runs[0].Processed)
+		assert.Equal(t, 1, runs[0].Failed)
+		assert.Equal(t, "billing 2: boom", runs[0].Error)
+		assert.NotNil(t, runs[0].FinishedAt)
+	}
+	assert.Error(t, s.Trigger("unknown"))
+}
+
+func TestTrigger_RejectsConcurrentRun(t *testing.T) {
+	cfg := &config.SchedulerConfig{Timezone: "Asia/Jakarta", Jobs: map[string]string{}}
+	s, err := NewScheduler(&fakeJobRunRepository{}, &fakeLocker{}, cfg)
+	assert.NoError(t, err)
+
+	release := make(chan struct{})
+	assert.NoError(t, s.Register("slow", func(ctx context.Context) (JobResult, error) {
+		<-release
+		return JobResult{Processed: 1}, nil
+	}))
+	assert.NoError(t, s.Trigger("slow"))
+	time.Sleep(10 * time.Millisecond)
+	assert.Error(t, s.Trigger("slow"))
+	close(release)
+	s.Stop()
+}
+
+func TestTrigger_SkipsJobLockedByAnotherInstance(t *testing.T) {
+	repo := &fakeJobRunRepository{}
+	locker := &fakeLocker{}
+	cfg := &config.SchedulerConfig{Timezone: "Asia/Jakarta", Jobs: map[string]string{}}
+	s, err := NewScheduler(repo, locker, cfg)
+	assert.NoError(t, err)
+
+	ran := false
+	assert.NoError(t, s.Register("sweep", func(ctx context.Context) (JobResult, error) {
+		ran = true
+		return JobResult{}, nil
+	}))
+	other, acquired, _ := locker.TryLock(context.Background(), "job:sweep")
+	assert.True(t, acquired)
+
+	assert.NoError(t, s.Trigger("sweep"))
+	s.Stop()
+	assert.False(t, ran)
+	runs, _ := repo.FindRecent(context.Background(), "sweep", 10)
+	assert.Empty(t, runs)
+	assert.NoError(t, other.Release(context.Background()))
+}
+
+func TestExecute_SkipsCompletedSlot(t *testing.T) {
+	repo := &fakeJobRunRepository{}
+	cfg := &config.SchedulerConfig{Timezone: "Asia/Jakarta", Jobs: map[string]string{}}
+	s, err := NewScheduler(repo, &fakeLocker{}, cfg)
+	assert.NoError(t, err)
+
+	runs := 0
+	fail := true
+	assert.NoError(t, s.Register("sweep", func(ctx context.Context) (JobResult, error) {
+		runs++
+		if fail {
+			return JobResult{}, errors.New("boom")
+		}
+		return JobResult{Processed: 1}, nil
+	}))
+	slot := time.Date(2025, 9, 1, 23, 55, 0, 0, time.UTC)
+	s.now = func() time.Time { return slot.Add(2 * time.Second) }
+
+	s.execute(s.jobs["sweep"])
+	fail = false
+	s.execute(s.jobs["sweep"])
+	s.execute(s.jobs["sweep"])
+	s.now = func() time.Time { return slot.Add(24 * time.Hour) }
+	s.execute(s.jobs["sweep"])
+	s.Stop()
+
+	assert.Equal(t, 3, runs)
+	recorded, _ := repo.FindRecent(context.Background(), "sweep", 10)
+	if assert.Len(t, recorded, 3) {
+		assert.True(t, slot.Equal(*recorded[0].Slot))
+		assert.Equal(t, model.JobRunStatusFailed, recorded[0].Status)
+		assert.Equal(t, model.JobRunStatusSuccess, recorded[1].Status)
+		assert.True(t, slot.Add(24*time.Hour).Equal(*recorded[2].Slot))
+	}
+}
@@ -2,45 +2,64 @@ package service
 
 import (
 	"context"
-	"os"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/doddeeph/billing-engine/internal/config"
 	"github.com/doddeeph/billing-engine/internal/dto"
 	"github.com/doddeeph/billing-engine/internal/model"
 	"github.com/doddeeph/billing-engine/internal/repository"
 	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/doddeeph/billing-engine/pkg/events"
 	"gorm.io/gorm"
 )
 
 type BillingService interface {
 	WithTransaction(tx *gorm.DB) BillingService
 	CreateBilling(ctx context.Context, req dto.CreateBillingRequest) (*model.Billing, error)
+	ImportBilling(ctx context.Context, req dto.ImportBillingRequest) (*model.Billing, error)
 	GetBilling(ctx context.Context, id uint) (*model.Billing, error)
-	IsDelinquent(ctx context.Context, id uint) (*model.Billing, bool, error)
+	GetBillingForUpdate(ctx context.Context, id uint) (*model.Billing, error)
+	GetBillingByLoanID(ctx context.Context, loanID uint) (*model.Billing, error)
+	ListBillings(ctx context.Context, query dto.BillingListQuery) (*dto.BillingListResponse, error)
+	IsDelinquent(ctx context.Context, id uint) (*model.Billing, *dto.Delinquency, error)
 	UpdateOutstanding(ctx context.Context, billingID uint, balance int) error
+	UpdateStatus(ctx context.Context, billingID uint, status string) error
+	GetAging(billing *model.Billing) dto.Aging
+	GetPortfolioAging(ctx context.Context) (*dto.PortfolioAgingResponse, error)
 }
 
-type billingServiceImpl struct {
-	repo             repository.BillingRepository
-	missedPaymentMax int
-}
+const maxBillingListLimit = 100
 
-func getMissedPaymentMax() int {
-	missedPaymentMaxStr := os.Getenv("MISSED_PAYMENT_MAX")
-	missedPaymentMax, err := strconv.Atoi(missedPaymentMaxStr)
-	if err != nil {
-		missedPaymentMax = 2
-	}
-	return missedPaymentMax
+type billingServiceImpl struct {
+	repo         repository.BillingRepository
+	customerRepo repository.CustomerRepository
+	exposureRepo repository.CustomerExposureRepository
+	policySvc    DelinquencyPolicyService
+	outboxSvc    OutboxService
+	vaSvc        VirtualAccountService
+	cfg          *config.BillingConfig
 }
 
-func NewBillingService(repo repository.BillingRepository) BillingService {
-	return &billingServiceImpl{repo: repo, missedPaymentMax: getMissedPaymentMax()}
+func NewBillingService(repo repository.BillingRepository, customerRepo repository.CustomerRepository, exposureRepo repository.CustomerExposureRepository, policySvc DelinquencyPolicyService, outboxSvc OutboxService, vaSvc VirtualAccountService, cfg *config.BillingConfig) BillingService {
+	return &billingServiceImpl{repo: repo, customerRepo: customerRepo, exposureRepo: exposureRepo, policySvc: policySvc, outboxSvc: outboxSvc, vaSvc: vaSvc, cfg: cfg}
 }
 
 func (svc *billingServiceImpl) WithTransaction(tx *gorm.DB) BillingService {
-	return &billingServiceImpl{repo: svc.repo.WithTransaction(tx), missedPaymentMax: getMissedPaymentMax()}
+	return &billingServiceImpl{
+		repo:         svc.repo.WithTransaction(tx),
+		customerRepo: svc.customerRepo.WithTransaction(tx),
+		exposureRepo: svc.exposureRepo.WithTransaction(tx),
+		policySvc:    svc.policySvc.WithTransaction(tx),
+		outboxSvc:    svc.outboxSvc.WithTransaction(tx),
+		vaSvc:        svc.vaSvc.WithTransaction(tx),
+		cfg:          svc.cfg,
+	}
 }
 
 func (svc *billingServiceImpl) CreateBilling(ctx context.Context, req dto.CreateBillingRequest) (*model.Billing, error) {
@@ -59,43 +78,367 @@ func (svc *billingServiceImpl) CreateBilling(ctx context.Context, req dto.Create
 		}
 	}
 	billing := &model.Billing{
-		CustomerID:   req.CustomerID,
-		LoanID:       req.LoanID,
-		LoanAmount:   req.LoanAmount,
-		LoanWeeks:    req.LoanWeeks,
-		LoanInterest: req.LoanInterest,
-		Outstanding:  outstandingBalance,
-		Payments:     payments,
-	}
-	if err := svc.repo.Create(ctx, billing); err != nil {
+		CustomerID:          req.CustomerID,
+		LoanID:              req.LoanID,
+		LoanAmount:          req.LoanAmount,
+		LoanWeeks:           req.LoanWeeks,
+		LoanInterest:        req.LoanInterest,
+		Outstanding:         outstandingBalance,
+		Status:              model.BillingStatusActive,
+		ProductCode:         req.ProductCode,
+		DelinquencyPolicyID: req.DelinquencyPolicyID,
+		Payments:            payments,
+	}
+	if err := svc.persistBilling(ctx, billing, true); err != nil {
 		return nil, err
 	}
 	return billing, nil
 }
 
+func (svc *billingServiceImpl) ImportBilling(ctx context.Context, req dto.ImportBillingRequest) (*model.Billing, error) {
+	billing, err := buildImportedBilling(req, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := svc.persistBilling(ctx, billing, false); err != nil {
+		return nil, err
+	}
+	return billing, nil
+}
+
+func (svc *billingServiceImpl) persistBilling(ctx context.Context, billing *model.Billing, checkExposure bool) error {
+	err := svc.repo.WithDB().Transaction(func(trx *gorm.DB) error {
+		if checkExposure {
+			if err := svc.checkExposure(ctx, trx, billing); err != nil {
+				return err
+			}
+		}
+		if err := svc.customerRepo.WithTransaction(trx).CreateIfAbsent(ctx, &model.Customer{ID: billing.CustomerID}); err != nil {
+			return err
+		}
+		if err := svc.repo.WithTransaction(trx).Create(ctx, billing); err != nil {
+			return err
+		}
+		return svc.outboxSvc.WithTransaction(trx).Record(ctx, billing.ID, events.TypeBillingCreated, events.BillingCreated{
+			BillingID:    billing.ID,
+			CustomerID:   billing.CustomerID,
+			LoanID:       billing.LoanID,
+			LoanAmount:   billing.LoanAmount,
+			LoanWeeks:    billing.LoanWeeks,
+			LoanInterest: billing.LoanInterest,
+			Outstanding:  billing.Outstanding,
+			ProductCode:  billing.ProductCode,
+			CreatedAt:    billing.CreatedAt,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	virtualAccounts, err := svc.vaSvc.IssueForBilling(ctx, billing)
+	if err != nil {
+		log.Printf("Failed to issue virtual accounts for billing %d: %v", billing.ID, err)
+	}
+	billing.VirtualAccounts = virtualAccounts
+	return nil
+}
+
+func (svc *billingServiceImpl) checkExposure(ctx context.Context, trx *gorm.DB, billing *model.Billing) error {
+	limit, err := svc.exposureRepo.WithTransaction(trx).FindByCustomerIDForUpdate(ctx, billing.CustomerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	current, err := svc.repo.WithTransaction(trx).SumOutstandingByCustomerID(ctx, billing.CustomerID, model.BillingStatusActive)
+	if err != nil {
+		return err
+	}
+	if exposure := current + billing.Outstanding; exposure > limit.ExposureLimit {
+		return fmt.Errorf("Customer %d exposure of %d would exceed the limit of %d.", billing.CustomerID, exposure, limit.ExposureLimit)
+	}
+	return nil
+}
+
+func buildImportedBilling(req dto.ImportBillingRequest, now time.Time) (*model.Billing, error) {
+	if req.LoanID == 0 || req.LoanAmount <= 0 || req.LoanWeeks <= 0 || req.LoanInterest < 0 {
+		return nil, fmt.Errorf("Loan requires loanId, a positive loanAmount and loanWeeks.")
+	}
+	if req.StartDate.IsZero() || req.StartDate.After(now) {
+		return nil, fmt.Errorf("Loan %d requires a startDate that is not in the future.", req.LoanID)
+	}
+	total := req.LoanAmount + (req.LoanAmount * req.LoanInterest / 100)
+
+	var payments []model.Payment
+	if len(req.Installments) == 0 {
+		weeklyAmount := total / req.LoanWeeks
+		weeklyDateRanges := utils.GenerateWeeklyDateRanges(req.StartDate, req.LoanWeeks)
+		for i := range req.LoanWeeks {
+			payments = append(payments, model.Payment{
+				Amount:    weeklyAmount,
+				Week:      i + 1,
+				StartDate: weeklyDateRanges[i+1].StartOfWeek,
+				DueDate:   weeklyDateRanges[i+1].EndOfWeek,
+			})
+		}
+	} else {
+		if len(req.Installments) != req.LoanWeeks {
+			return nil, fmt.Errorf("Loan %d has %d installments for %d loan weeks.", req.LoanID, len(req.Installments), req.LoanWeeks)
+		}
+		scheduled := 0
+		for i, installment := range req.Installments {
+			if installment.Week != i+1 {
+				return nil, fmt.Errorf("Loan %d installments must be ordered by week starting at 1.", req.LoanID)
+			}
+			if installment.Amount <= 0 || installment.LateFee < 0 || installment.DueDate.IsZero() {
+				return nil, fmt.Errorf("Loan %d installment %d requires a positive amount and a dueDate.", req.LoanID, installment.Week)
+			}
+			startDate := utils.GetWeekDateRange(installment.DueDate).StartOfWeek
+			if installment.StartDate != nil {
+				startDate = *installment.StartDate
+			}
+			scheduled += installment.Amount
+			payments = append(payments, model.Payment{
+				Amount:    installment.Amount,
+				Week:      installment.Week,
+				LateFee:   installment.LateFee,
+				Overdue:   installment.LateFee > 0,
+				StartDate: startDate,
+				DueDate:   installment.DueDate,
+			})
+		}
+		if scheduled != total {
+			return nil, fmt.Errorf("Loan %d installments total %d, expected %d.", req.LoanID, scheduled, total)
+		}
+	}
+
+	outstanding := total
+	for _, payment := range payments {
+		outstanding += payment.LateFee
+	}
+	for _, paid := range req.Payments {
+		if paid.Week < 1 || paid.Week > len(payments) {
+			return nil, fmt.Errorf("Loan %d payment is outside %d loan week.", req.LoanID, req.LoanWeeks)
+		}
+		payment := &payments[paid.Week-1]
+		if payment.Paid {
+			return nil, fmt.Errorf("Loan %d week %d is paid more than once.", req.LoanID, paid.Week)
+		}
+		if paid.Amount < payment.Amount+payment.LateFee {
+			return nil, fmt.Errorf("Insufficient loan amount paid for loan %d week %d.", req.LoanID, paid.Week)
+		}
+		if paid.PaidDate.IsZero() || paid.PaidDate.Before(req.StartDate) || paid.PaidDate.After(now) {
+			return nil, fmt.Errorf("Loan %d week %d paidDate must be between startDate and now.", req.LoanID, paid.Week)
+		}
+		paidDate := paid.PaidDate
+		payment.Paid = true
+		payment.PaidDate = &paidDate
+		outstanding -= payment.Amount + payment.LateFee
+	}
+	if req.Outstanding != nil && *req.Outstanding != outstanding {
+		return nil, fmt.Errorf("Loan %d outstanding %d does not match the schedule, expected %d.", req.LoanID, *req.Outstanding, outstanding)
+	}
+
+	status := model.BillingStatusActive
+	if len(req.Payments) == len(payments) {
+		status = model.BillingStatusClosed
+	}
+	return &model.Billing{
+		CustomerID:          req.CustomerID,
+		LoanID:              req.LoanID,
+		LoanAmount:          req.LoanAmount,
+		LoanWeeks:           req.LoanWeeks,
+		LoanInterest:        req.LoanInterest,
+		Outstanding:         outstanding,
+		Status:              status,
+		ProductCode:         req.ProductCode,
+		DelinquencyPolicyID: req.DelinquencyPolicyID,
+		Payments:            payments,
+		CommonModel:         model.CommonModel{CreatedAt: req.StartDate},
+	}, nil
+}
+
 func (svc *billingServiceImpl) GetBilling(ctx context.Context, id uint) (*model.Billing, error) {
 	return svc.repo.FindByID(ctx, id)
 }
 
-func (svc *billingServiceImpl) IsDelinquent(ctx context.Context, id uint) (*model.Billing, bool, error) {
-	billing, err := svc.repo.FindByID(ctx, id)
+func (svc *billingServiceImpl) GetBillingForUpdate(ctx context.Context, id uint) (*model.Billing, error) {
+	return svc.repo.FindByIDForUpdate(ctx, id)
+}
+
+func (svc *billingServiceImpl) GetBillingByLoanID(ctx context.Context, loanID uint) (*model.Billing, error) {
+	return svc.repo.FindByLoanID(ctx, loanID)
+}
+
+func (svc *billingServiceImpl) ListBillings(ctx context.Context, query dto.BillingListQuery) (*dto.BillingListResponse, error) {
+	filter, err := buildBillingListFilter(query)
 	if err != nil {
-		return nil, false, err
+		return nil, err
+	}
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	billings, err := svc.repo.FindByListFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.BillingListResponse{Billings: []dto.BillingSummary{}}
+	if len(billings) > limit {
+		billings = billings[:limit]
+		resp.NextCursor = encodeBillingCursor(billings[limit-1].ID)
+	}
+	for _, billing := range billings {
+		resp.Billings = append(resp.Billings, dto.BillingSummary{
+			BillingID:         billing.ID,
+			CustomerID:        billing.CustomerID,
+			LoanID:            billing.LoanID,
+			LoanAmount:        billing.LoanAmount,
+			LoanWeeks:         billing.LoanWeeks,
+			LoanInterest:      billing.LoanInterest,
+			Outstanding:       billing.Outstanding,
+			ProductCode:       billing.ProductCode,
+			Status:            billing.Status,
+			DelinquencyStatus: billing.DelinquencyStatus,
+			CreatedAt:         billing.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+func buildBillingListFilter(query dto.BillingListQuery) (repository.BillingListFilter, error) {
+	filter := repository.BillingListFilter{
+		CustomerID:        query.CustomerID,
+		Status:            strings.ToUpper(query.Status),
+		DelinquencyStatus: strings.ToUpper(query.DelinquencyStatus),
+		Limit:             query.Limit,
+	}
+	switch filter.Status {
+	case "", model.BillingStatusActive, model.BillingStatusClosed, model.BillingStatusWrittenOff:
+	default:
+		return filter, fmt.Errorf("Unsupported billing status %s.", query.Status)
+	}
+	switch filter.DelinquencyStatus {
+	case "", model.DelinquencyStatusCurrent, model.DelinquencyStatusDelinquent:
+	default:
+		return filter, fmt.Errorf("Unsupported delinquency status %s.", query.DelinquencyStatus)
 	}
-	missed := 0
-	for _, p := range billing.Payments {
-		if !p.Paid {
-			missed++
-		} else if p.Paid {
-			missed = 0
+	if filter.Limit <= 0 || filter.Limit > maxBillingListLimit {
+		return filter, fmt.Errorf("Limit must be between 1 and %d.", maxBillingListLimit)
+	}
+	if query.CreatedFrom != "" {
+		createdFrom, err := time.ParseInLocation(time.DateOnly, query.CreatedFrom, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid createdFrom date %s.", query.CreatedFrom)
 		}
-		if missed >= svc.missedPaymentMax {
-			break
+		filter.CreatedFrom = &createdFrom
+	}
+	if query.CreatedTo != "" {
+		createdTo, err := time.ParseInLocation(time.DateOnly, query.CreatedTo, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid createdTo date %s.", query.CreatedTo)
 		}
+		createdTo = createdTo.AddDate(0, 0, 1)
+		filter.CreatedTo = &createdTo
+	}
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return filter, fmt.Errorf("Created date range is invalid.")
+	}
+	if query.Cursor != "" {
+		beforeID, err := decodeBillingCursor(query.Cursor)
+		if err != nil {
+			return filter, err
+		}
+		filter.BeforeID = beforeID
+	}
+	return filter, nil
+}
+
+func encodeBillingCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func decodeBillingCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid cursor.")
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("Invalid cursor.")
+	}
+	return uint(id), nil
+}
+
+func (svc *billingServiceImpl) IsDelinquent(ctx context.Context, id uint) (*model.Billing, *dto.Delinquency, error) {
+	billing, err := svc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
 	}
-	return billing, missed >= svc.missedPaymentMax, nil
+	policy, err := svc.policySvc.ResolvePolicy(ctx, billing)
+	if err != nil {
+		return nil, nil, err
+	}
+	delinquency := svc.policySvc.Evaluate(policy, billing, time.Now())
+	return billing, &delinquency, nil
 }
 
 func (svc *billingServiceImpl) UpdateOutstanding(ctx context.Context, billingID uint, balance int) error {
 	return svc.repo.UpdateOutstanding(ctx, billingID, balance)
 }
+
+func (svc *billingServiceImpl) UpdateStatus(ctx context.Context, billingID uint, status string) error {
+	return svc.repo.UpdateStatus(ctx, billingID, status)
+}
+
+func oldestUnpaidDueDate(payments []model.Payment) *time.Time {
+	var oldest *time.Time
+	for i := range payments {
+		if payments[i].Paid {
+			continue
+		}
+		if oldest == nil || payments[i].DueDate.Before(*oldest) {
+			oldest = &payments[i].DueDate
+		}
+	}
+	return oldest
+}
+
+func (svc *billingServiceImpl) getAging(oldestDueDate *time.Time, now time.Time) dto.Aging {
+	dpd := 0
+	if oldestDueDate != nil {
+		dpd = utils.DaysPastDue(*oldestDueDate, now)
+	}
+	return dto.Aging{
+		DaysPastDue: dpd,
+		AgingBucket: utils.GetAgingBucket(dpd, svc.cfg.AgingBuckets),
+	}
+}
+
+func (svc *billingServiceImpl) GetAging(billing *model.Billing) dto.Aging {
+	return svc.getAging(oldestUnpaidDueDate(billing.Payments), time.Now())
+}
+
+func (svc *billingServiceImpl) GetPortfolioAging(ctx context.Context) (*dto.PortfolioAgingResponse, error) {
+	dueDates, err := svc.repo.FindOldestUnpaidDueDates(ctx, model.BillingStatusActive)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	labels := utils.AgingBucketLabels(svc.cfg.AgingBuckets)
+	buckets := make([]dto.AgingBucketSummary, len(labels))
+	index := make(map[string]int, len(labels))
+	for i, label := range labels {
+		buckets[i] = dto.AgingBucketSummary{Bucket: label}
+		index[label] = i
+	}
+	resp := &dto.PortfolioAgingResponse{AsOf: now}
+	for _, d := range dueDates {
+		aging := svc.getAging(d.OldestUnpaidDueDate, now)
+		bucket := &buckets[index[aging.AgingBucket]]
+		bucket.Count++
+		bucket.Outstanding += d.Outstanding
+		resp.TotalCount++
+		resp.TotalOutstanding += d.Outstanding
+	}
+	resp.Buckets = buckets
+	return resp, nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func testImportRequest(startDate time.Time) dto.ImportBillingRequest {
+	return dto.ImportBillingRequest{
+		CreateBillingDTO: dto.CreateBillingDTO{
+			CustomerID:   1,
+			LoanID:       9001,
+			LoanAmount:   400000,
+			LoanInterest: 10,
+			LoanWeeks:    4,
+		},
+		StartDate: startDate,
+	}
+}
+
+func TestBuildImportedBilling_GeneratedSchedule(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	req := testImportRequest(now.AddDate(0, 0, -21))
+	req.Payments = []dto.ImportPayment{
+		{Week: 1, Amount: 110000, PaidDate: now.AddDate(0, 0, -14)},
+		{Week: 2, Amount: 110000, PaidDate: now.AddDate(0, 0, -7)},
+	}
+	outstanding := 220000
+	req.Outstanding = &outstanding
+
+	billing, err := buildImportedBilling(req, now)
+	assert.NoError(t, err)
+	assert.Equal(t, model.BillingStatusActive, billing.Status)
+	assert.Equal(t, 220000, billing.Outstanding)
+	assert.Len(t, billing.Payments, 4)
+	assert.True(t, billing.Payments[1].Paid)
+	assert.False(t, billing.Payments[2].Paid)
+	assert.Equal(t, req.StartDate, billing.CreatedAt)
+
+	req.Payments = append(req.Payments, dto.ImportPayment{Week: 3, Amount: 110000, PaidDate: now}, dto.ImportPayment{Week: 4, Amount: 110000, PaidDate: now})
+	zero := 0
+	req.Outstanding = &zero
+	billing, err = buildImportedBilling(req, now)
+	assert.NoError(t, err)
+	assert.Equal(t, model.BillingStatusClosed, billing.Status)
+}
+
+func TestBuildImportedBilling_Schedule(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	req := testImportRequest(now.AddDate(0, 0, -30))
+	for week := 1; week <= 4; week++ {
+		req.Installments = append(req.Installments, dto.ImportInstallment{Week: week, Amount: 110000, DueDate: req.StartDate.AddDate(0, 0, 7*week)})
+	}
+	req.Installments[0].LateFee = 5000
+	req.Payments = []dto.ImportPayment{{Week: 1, Amount: 115000, PaidDate: now}}
+
+	billing, err := buildImportedBilling(req, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 330000, billing.Outstanding)
+	assert.True(t, billing.Payments[0].Overdue)
+
+	req.Installments[3].Amount = 100000
+	_, err = buildImportedBilling(req, now)
+	assert.EqualError(t, err, "Loan 9001 installments total 430000, expected 440000.")
+}
+
+func TestBuildImportedBilling_Invalid(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	_, err := buildImportedBilling(testImportRequest(now.AddDate(0, 0, 1)), now)
+	assert.Error(t, err)
+
+	req := testImportRequest(now.AddDate(0, 0, -21))
+	req.Payments = []dto.ImportPayment{{Week: 1, Amount: 100000, PaidDate: now}}
+	_, err = buildImportedBilling(req, now)
+	assert.EqualError(t, err, "Insufficient loan amount paid for loan 9001 week 1.")
+
+	req.Payments = []dto.ImportPayment{{Week: 1, Amount: 110000, PaidDate: now}, {Week: 1, Amount: 110000, PaidDate: now}}
+	_, err = buildImportedBilling(req, now)
+	assert.EqualError(t, err, "Loan 9001 week 1 is paid more than once.")
+
+	req.Payments = []dto.ImportPayment{{Week: 5, Amount: 110000, PaidDate: now}}
+	_, err = buildImportedBilling(req, now)
+	assert.Error(t, err)
+
+	req.Payments = nil
+	outstanding := 1
+	req.Outstanding = &outstanding
+	_, err = buildImportedBilling(req, now)
+	assert.EqualError(t, err, "Loan 9001 outstanding 1 does not match the schedule, expected 440000.")
+}
+
+func TestBuildBillingListFilter(t *testing.T) {
+	filter, err := buildBillingListFilter(dto.BillingListQuery{
+		Status:            "active",
+		DelinquencyStatus: "delinquent",
+		CreatedFrom:       "2025-09-01",
+		CreatedTo:         "2025-09-30",
+		Cursor:            encodeBillingCursor(42),
+		Limit:             20,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, model.BillingStatusActive, filter.Status)
+	assert.Equal(t, model.DelinquencyStatusDelinquent, filter.DelinquencyStatus)
+	assert.Equal(t, uint(42), filter.BeforeID)
+	assert.Equal(t, "2025-10-01", filter.CreatedTo.Format(time.DateOnly))
+
+	_, err = buildBillingListFilter(dto.BillingListQuery{Status: "OPEN", Limit: 20})
+	assert.EqualError(t, err, "Unsupported billing status OPEN.")
+	_, err = buildBillingListFilter(dto.BillingListQuery{Limit: 500})
+	assert.Error(t, err)
+	_, err = buildBillingListFilter(dto.BillingListQuery{CreatedFrom: "2025-09-30", CreatedTo: "2025-09-01", Limit: 20})
+	assert.EqualError(t, err, "Created date range is invalid.")
+	_, err = buildBillingListFilter(dto.BillingListQuery{Cursor: "not-a-cursor", Limit: 20})
+	assert.EqualError(t, err, "Invalid cursor.")
+}
@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"gorm.io/gorm"
+)
+
+type CollectionService interface {
+	WithTransaction(tx *gorm.DB) CollectionService
+	SyncCase(ctx context.Context, billingID uint, now time.Time) (*model.CollectionCase, error)
+	SyncCases(ctx context.Context) (int, int, error)
+	CloseCase(ctx context.Context, billingID uint, reason string, now time.Time) error
+	CreateAgent(ctx context.Context, req dto.CollectionAgentRequest) (*model.CollectionAgent, error)
+	UpdateAgent(ctx context.Context, id uint, req dto.CollectionAgentRequest) (*model.CollectionAgent, error)
+	GetAgents(ctx context.Context) ([]model.CollectionAgent, error)
+	GetAgentQueue(ctx context.Context, agentID uint, limit, offset int) ([]dto.CollectionQueueItem, error)
+	GetUnassignedQueue(ctx context.Context, limit, offset int) ([]dto.CollectionQueueItem, error)
+	GetCase(ctx context.Context, id uint) (*model.CollectionCase, error)
+	GetBillingCases(ctx context.Context, billingID uint) ([]model.CollectionCase, error)
+	AssignCase(ctx context.Context, id uint, req dto.AssignCollectionCaseRequest) (*model.CollectionCase, error)
+	LogContactAttempt(ctx context.Context, id uint, req dto.ContactAttemptRequest) (*model.ContactAttempt, error)
+}
+
+type collectionServiceImpl struct {
+	repo         repository.CollectionRepository
+	billingRepo  repository.BillingRepository
+	customerRepo repository.CustomerRepository
+	billingCfg   *config.BillingConfig
+	cfg          *config.CollectionsConfig
+}
+
+func NewCollectionService(repo repository.CollectionRepository, billingRepo repository.BillingRepository, customerRepo repository.CustomerRepository, billingCfg *config.BillingConfig, cfg *config.CollectionsConfig) CollectionService {
+	return &collectionServiceImpl{repo: repo, billingRepo: billingRepo, customerRepo: customerRepo, billingCfg: billingCfg, cfg: cfg}
+}
+
+func (svc *collectionServiceImpl) WithTransaction(tx *gorm.DB) CollectionService {
+	return svc.withTransaction(tx)
+}
+
+func (svc *collectionServiceImpl) withTransaction(tx *gorm.DB) *collectionServiceImpl {
+	return &collectionServiceImpl{
+		repo:         svc.repo.WithTransaction(tx),
+		billingRepo:  svc.billingRepo.WithTransaction(tx),
+		customerRepo: svc.customerRepo.WithTransaction(tx),
+		billingCfg:   svc.billingCfg,
+		cfg:          svc.cfg,
+	}
+}
+
+func (svc *collectionServiceImpl) SyncCase(ctx context.Context, billingID uint, now time.Time) (*model.CollectionCase, error) {
+	var collectionCase *model.CollectionCase
+	err := svc.billingRepo.WithDB().Transaction(func(trx *gorm.DB) error {
+		billing, err := svc.billingRepo.WithTransaction(trx).FindByIDForUpdate(ctx, billingID)
+		if err != nil {
+			return err
+		}
+		trxSvc := svc.withTransaction(trx)
+		if billing.Status != model.BillingStatusActive || billing.DelinquencyStatus != model.DelinquencyStatusDelinquent {
+			return trxSvc.CloseCase(ctx, billing.ID, collectionCloseReason(billing), now)
+		}
+		collectionCase, err = trxSvc.openCase(ctx, billing, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return collectionCase, nil
+}
+
+func (svc *collectionServiceImpl) SyncCases(ctx context.Context) (int, int, error) {
+	processed, failed := 0, 0
+	var errs []error
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return processed, failed, errors.Join(append(errs, err)...)
+		}
+		billingIDs, err := svc.repo.FindBillingIDsToSync(ctx, afterID, svc.billingCfg.EvaluationBatchSize)
+		if err != nil {
+			return processed, failed, errors.Join(append(errs, err)...)
+		}
+		if len(billingIDs) == 0 {
+			return processed, failed, errors.Join(errs...)
+		}
+		for _, billingID := range billingIDs {
+			if _, err := svc.SyncCase(ctx, billingID, time.Now()); err != nil {
+				failed++
+				errs = append(errs, fmt.Errorf("billing %d: %w", billingID, err))
+			} else {
+				processed++
+			}
+		}
+		afterID = billingIDs[len(billingIDs)-1]
+	}
+}
+
+func collectionCloseReason(billing *model.Billing) string {
+	switch billing.Status {
+	case model.BillingStatusWrittenOff:
+		return "Billing is written off."
+	case model.BillingStatusClosed:
+		return "Billing is closed."
+	}
+	return "Billing is cured."
+}
+
+func (svc *collectionServiceImpl) openCase(ctx context.Context, billing *model.Billing, now time.Time) (*model.CollectionCase, error) {
+	existing, err := svc.repo.FindOpenCaseByBillingID(ctx, billing.ID)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	customer, err := svc.customerRepo.FindByID(ctx, billing.CustomerID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	collectionCase := &model.CollectionCase{
+		BillingID:  billing.ID,
+		CustomerID: billing.CustomerID,
+		Status:     model.CollectionCaseStatusOpen,
+		OpenedAt:   now,
+	}
+	if customer != nil {
+		collectionCase.Region = customer.Region
+	}
+	agent, err := svc.nextAgent(ctx, collectionCase.Region)
+	if err != nil {
+		return nil, err
+	}
+	if agent != nil {
+		assignCollectionCase(collectionCase, agent, now)
+		if err := svc.repo.UpdateAgent(ctx, agent); err != nil {
+			return nil, err
+		}
+	}
+	if err := svc.repo.CreateCase(ctx, collectionCase); err != nil {
+		return nil, err
+	}
+	return collectionCase, nil
+}
+
+func (svc *collectionServiceImpl) nextAgent(ctx context.Context, region string) (*model.CollectionAgent, error) {
+	if region != "" {
+		agent, err := svc.repo.FindNextAgentForUpdate(ctx, region)
+		if err == nil {
+			return agent, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	agent, err := svc.repo.FindNextAgentForUpdate(ctx, "")
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return agent, err
+}
+
+func assignCollectionCase(collectionCase *model.CollectionCase, agent *model.CollectionAgent, now time.Time) {
+	collectionCase.AgentID = &agent.ID
+	collectionCase.AssignedAt = &now
+	agent.LastAssignedAt = &now
+}
+
+func (svc *collectionServiceImpl) CloseCase(ctx context.Context, billingID uint, reason string, now time.Time) error {
+	collectionCase, err := svc.repo.FindOpenCaseByBillingID(ctx, billingID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	collectionCase.Status = model.CollectionCaseStatusClosed
+	collectionCase.ClosedAt = &now
+	collectionCase.CloseReason = reason
+	return svc.repo.UpdateCase(ctx, collectionCase)
+}
+
+func applyCollectionAgentRequest(agent *model.CollectionAgent, req dto.CollectionAgentRequest) error {
+	agent.Name = strings.TrimSpace(req.Name)
+	if agent.Name == "" {
+		return fmt.Errorf("Agent name is required.")
+	}
+	agent.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if agent.Email != "" {
+		if _, err := mail.ParseAddress(agent.Email); err != nil {
+			return fmt.Errorf("Invalid email address %s.", req.Email)
+		}
+	}
+	agent.Region = strings.ToUpper(strings.TrimSpace(req.Region))
+	if req.Active != nil {
+		agent.Active = *req.Active
+	}
+	return nil
+}
+
+func (svc *collectionServiceImpl) CreateAgent(ctx context.Context, req dto.CollectionAgentRequest) (*model.CollectionAgent, error) {
+	agent := &model.CollectionAgent{Active: true}
+	if err := applyCollectionAgentRequest(agent, req); err != nil {
+		return nil, err
+	}
+	if err := svc.repo.CreateAgent(ctx, agent); err != nil {
+		return nil, err
+	}
+	return agent, nil
+}
+
+func (svc *collectionServiceImpl) UpdateAgent(ctx context.Context, id uint, req dto.CollectionAgentRequest) (*model.CollectionAgent, error) {
+	agent, err := svc.repo.FindAgentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyCollectionAgentRequest(agent, req); err != nil {
+		return nil, err
+	}
+	if err := svc.repo.UpdateAgent(ctx, agent); err != nil {
+		return nil, err
+	}
+	return agent, nil
+}
+
+func (svc *collectionServiceImpl) GetAgents(ctx context.Context) ([]model.CollectionAgent, error) {
+	return svc.repo.FindAgents(ctx)
+}
+
+func (svc *collectionServiceImpl) GetAgentQueue(ctx context.Context, agentID uint, limit, offset int) ([]dto.CollectionQueueItem, error) {
+	if _, err := svc.repo.FindAgentByID(ctx, agentID); err != nil {
+		return nil, err
+	}
+	return svc.queue(ctx, &agentID, limit, offset)
+}
+
+func (svc *collectionServiceImpl) GetUnassignedQueue(ctx context.Context, limit, offset int) ([]dto.CollectionQueueItem, error) {
+	return svc.queue(ctx, nil, limit, offset)
+}
+
+func (svc *collectionServiceImpl) queue(ctx context.Context, agentID *uint, limit, offset int) ([]dto.CollectionQueueItem, error) {
+	now := time.Now()
+	cases, err := svc.repo.FindOpenCasesByAgentID(ctx, agentID, now, svc.cfg.PriorityAmountUnit, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]dto.CollectionQueueItem, 0, len(cases))
+	for i := range cases {
+		items = append(items, collectionQueueItem(&cases[i], now, svc.cfg.PriorityAmountUnit))
+	}
+	return items, nil
+}
+
+func (svc *collectionServiceImpl) GetCase(ctx context.Context, id uint) (*model.CollectionCase, error) {
+	return svc.repo.FindCaseByID(ctx, id)
+}
+
+func (svc *collectionServiceImpl) GetBillingCases(ctx context.Context, billingID uint) ([]model.CollectionCase, error) {
+	if _, err := svc.billingRepo.FindByID(ctx, billingID); err != nil {
+		return nil, err
+	}
+	return svc.repo.FindCasesByBillingID(ctx, billingID)
+}
+
+func (svc *collectionServiceImpl) AssignCase(ctx context.Context, id uint, req dto.AssignCollectionCaseRequest) (*model.CollectionCase, error) {
+	var collectionCase *model.CollectionCase
+	err := svc.billingRepo.WithDB().Transaction(func(trx *gorm.DB) error {
+		trxRepo := svc.repo.WithTransaction(trx)
+		var err error
+		if collectionCase, err = trxRepo.FindCaseByID(ctx, id); err != nil {
+			return err
+		}
+		if collectionCase.Status != model.CollectionCaseStatusOpen {
+			return fmt.Errorf("Collection case %d is closed.", collectionCase.ID)
+		}
+		agent, err := trxRepo.FindAgentByID(ctx, req.AgentID)
+		if err != nil {
+			return err
+		}
+		if !agent.Active {
+			return fmt.Errorf("Collection agent %d is inactive.", agent.ID)
+		}
+		assignCollectionCase(collectionCase, agent, time.Now())
+		if err := trxRepo.UpdateAgent(ctx, agent); err != nil {
+			return err
+		}
+		return trxRepo.UpdateCase(ctx, collectionCase)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return collectionCase, nil
+}
+
+func validateContactAttemptRequest(req dto.ContactAttemptRequest, now time.Time) error {
+	switch req.Channel {
+	case model.ContactChannelCall, model.ContactChannelSMS, model.ContactChannelEmail, model.ContactChannelWhatsapp, model.ContactChannelVisit:
+	default:
+		return fmt.Errorf("Unknown contact channel %q.", req.Channel)
+	}
+	switch req.Outcome {
+	case model.ContactOutcomeNoAnswer, model.ContactOutcomeUnreachable, model.ContactOutcomeWrongNumber, model.ContactOutcomeContacted,
+		model.ContactOutcomePromisedToPay, model.ContactOutcomeRefusedToPay, model.ContactOutcomeDisputed, model.ContactOutcomeCallback:
+	default:
+		return fmt.Errorf("Unknown contact outcome %q.", req.Outcome)
+	}
+	if req.AttemptedAt != nil && req.AttemptedAt.After(now) {
+		return fmt.Errorf("Contact attempt time cannot be in the future.")
+	}
+	return nil
+}
+
+func (svc *collectionServiceImpl) LogContactAttempt(ctx context.Context, id uint, req dto.ContactAttemptRequest) (*model.ContactAttempt, error) {
+	now := time.Now()
+	req.Channel = strings.ToUpper(req.Channel)
+	req.Outcome = strings.ToUpper(req.Outcome)
+	if err := validateContactAttemptRequest(req, now); err != nil {
+		return nil, err
+	}
+	attempt := &model.ContactAttempt{
+		CaseID:      id,
+		AgentID:     req.AgentID,
+		Channel:     req.Channel,
+		Outcome:     req.Outcome,
+		Notes:       strings.TrimSpace(req.Notes),
+		AttemptedAt: now,
+	}
+	if req.AttemptedAt != nil {
+		attempt.AttemptedAt = *req.AttemptedAt
+	}
+	err := svc.billingRepo.WithDB().Transaction(func(trx *gorm.DB) error {
+		trxRepo := svc.repo.WithTransaction(trx)
+		collectionCase, err := trxRepo.FindCaseByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if collectionCase.Status != model.CollectionCaseStatusOpen {
+			return fmt.Errorf("Collection case %d is closed.", collectionCase.ID)
+		}
+		if attempt.AgentID == nil {
+			attempt.AgentID = collectionCase.AgentID
+		} else if _, err := trxRepo.FindAgentByID(ctx, *attempt.AgentID); err != nil {
+			return err
+		}
+		if err := trxRepo.CreateAttempt(ctx, attempt); err != nil {
+			return err
+		}
+		if collectionCase.LastContactAt == nil || !attempt.AttemptedAt.Before(*collectionCase.LastContactAt) {
+			collectionCase.LastContactAt = &attempt.AttemptedAt
+			collectionCase.LastOutcome = attempt.Outcome
+		}
+		return trxRepo.UpdateCase(ctx, collectionCase)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+func collectionPriority(dpd, overdueAmount, amountUnit int) int {
+	if amountUnit <= 0 {
+		return dpd
+	}
+	return dpd + overdueAmount/amountUnit
+}
+
+func collectionQueueItem(collectionCase *model.CollectionCase, now time.Time, amountUnit int) dto.CollectionQueueItem {
+	item := dto.CollectionQueueItem{
+		CaseID:        collectionCase.ID,
+		BillingID:     collectionCase.BillingID,
+		CustomerID:    collectionCase.CustomerID,
+		AgentID:       collectionCase.AgentID,
+		Region:        collectionCase.Region,
+		OpenedAt:      collectionCase.OpenedAt,
+		LastContactAt: collectionCase.LastContactAt,
+		LastOutcome:   collectionCase.LastOutcome,
+	}
+	if collectionCase.Billing != nil {
+		item.LoanID = collectionCase.Billing.LoanID
+		item.Outstanding = collectionCase.Billing.Outstanding
+		oldest, overdueAmount := overdueInstallments(collectionCase.Billing.Payments, now)
+		if oldest != nil {
+			item.DaysPastDue = utils.DaysPastDue(oldest.DueDate, now)
+		}
+		item.OverdueAmount = overdueAmount
+	}
+	item.PriorityScore = collectionPriority(item.DaysPastDue, item.OverdueAmount, amountUnit)
+	return item
+}
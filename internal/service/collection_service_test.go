@@ -0,0 +1,65 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionQueueItem(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	payments := testPayments(now, 4, map[int]int{1: 0})
+	for i := range payments {
+		payments[i].Amount = 110000
+	}
+	agentID := uint(3)
+	collectionCase := &model.CollectionCase{
+		ID:        1,
+		BillingID: 2,
+		AgentID:   &agentID,
+		Billing:   &model.Billing{LoanID: 1001, Outstanding: 330000, Payments: payments},
+	}
+
+	item := collectionQueueItem(collectionCase, now, 100000)
+	assert.Equal(t, uint(1001), item.LoanID)
+	assert.Equal(t, 15, item.DaysPastDue)
+	assert.Equal(t, 330000, item.OverdueAmount)
+	assert.Equal(t, 18, item.PriorityScore)
+	assert.Equal(t, &agentID, item.AgentID)
+
+	assert.Equal(t, 15, collectionQueueItem(collectionCase, now, 0).PriorityScore)
+}
+
+func TestCollectionCloseReason(t *testing.T) {
+	assert.Equal(t, "Billing is cured.", collectionCloseReason(&model.Billing{Status: model.BillingStatusActive}))
+	assert.Equal(t, "Billing is written off.", collectionCloseReason(&model.Billing{Status: model.BillingStatusWrittenOff}))
+	assert.Equal(t, "Billing is closed.", collectionCloseReason(&model.Billing{Status: model.BillingStatusClosed}))
+}
+
+func TestValidateContactAttemptRequest(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, validateContactAttemptRequest(dto.ContactAttemptRequest{Channel: model.ContactChannelCall, Outcome: model.ContactOutcomeNoAnswer}, now))
+	assert.EqualError(t, validateContactAttemptRequest(dto.ContactAttemptRequest{Channel: "FAX", Outcome: model.ContactOutcomeNoAnswer}, now), `Unknown contact channel "FAX".`)
+	assert.EqualError(t, validateContactAttemptRequest(dto.ContactAttemptRequest{Channel: model.ContactChannelVisit, Outcome: "GONE"}, now), `Unknown contact outcome "GONE".`)
+	later := now.Add(time.Hour)
+	assert.EqualError(t, validateContactAttemptRequest(dto.ContactAttemptRequest{Channel: model.ContactChannelSMS, Outcome: model.ContactOutcomeContacted, AttemptedAt: &later}, now), "Contact attempt time cannot be in the future.")
+}
+
+func TestApplyCollectionAgentRequest(t *testing.T) {
+	agent := &model.CollectionAgent{Active: true}
+	assert.NoError(t, applyCollectionAgentRequest(agent, dto.CollectionAgentRequest{Name: " Sari ", Email: "Sari@Example.com", Region: " jakarta "}))
+	assert.Equal(t, "Sari", agent.Name)
+	assert.Equal(t, "sari@example.com", agent.Email)
+	assert.Equal(t, "JAKARTA", agent.Region)
+	assert.True(t, agent.Active)
+
+	inactive := false
+	assert.NoError(t, applyCollectionAgentRequest(agent, dto.CollectionAgentRequest{Name: "Sari", Active: &inactive}))
+	assert.False(t, agent.Active)
+
+	assert.EqualError(t, applyCollectionAgentRequest(agent, dto.CollectionAgentRequest{}), "Agent name is required.")
+	assert.EqualError(t, applyCollectionAgentRequest(agent, dto.CollectionAgentRequest{Name: "Sari", Email: "sari"}), "Invalid email address sari.")
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"gorm.io/gorm"
+)
+
+var customerPhonePattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+type CustomerService interface {
+	UpsertCustomer(ctx context.Context, customerID uint, req dto.CustomerRequest) (*model.Customer, error)
+	GetCustomer(ctx context.Context, customerID uint) (*model.Customer, error)
+	GetSummary(ctx context.Context, customerID uint) (*dto.CustomerSummaryResponse, error)
+	SetExposureLimit(ctx context.Context, customerID uint, req dto.ExposureLimitRequest) (*model.CustomerExposureLimit, error)
+	RemoveExposureLimit(ctx context.Context, customerID uint) error
+}
+
+type customerServiceImpl struct {
+	repo         repository.CustomerRepository
+	billingRepo  repository.BillingRepository
+	exposureRepo repository.CustomerExposureRepository
+	billingSvc   BillingService
+	policySvc    DelinquencyPolicyService
+	cfg          *config.CustomerConfig
+}
+
+func NewCustomerService(repo repository.CustomerRepository, billingRepo repository.BillingRepository, exposureRepo repository.CustomerExposureRepository, billingSvc BillingService, policySvc DelinquencyPolicyService, cfg *config.CustomerConfig) CustomerService {
+	return &customerServiceImpl{repo: repo, billingRepo: billingRepo, exposureRepo: exposureRepo, billingSvc: billingSvc, policySvc: policySvc, cfg: cfg}
+}
+
+func (svc *customerServiceImpl) UpsertCustomer(ctx context.Context, customerID uint, req dto.CustomerRequest) (*model.Customer, error) {
+	customer, err := buildCustomer(customerID, req, svc.cfg)
+	if err != nil {
+		return nil, err
+	}
+	syncedAt := time.Now()
+	customer.SyncedAt = &syncedAt
+	if err := svc.repo.Upsert(ctx, customer); err != nil {
+		return nil, err
+	}
+	return svc.repo.FindByID(ctx, customerID)
+}
+
+func (svc *customerServiceImpl) GetCustomer(ctx context.Context, customerID uint) (*model.Customer, error) {
+	return svc.repo.FindByID(ctx, customerID)
+}
+
+func buildCustomer(customerID uint, req dto.CustomerRequest, cfg *config.CustomerConfig) (*model.Customer, error) {
+	customer := &model.Customer{
+		ID:                customerID,
+		Name:              strings.TrimSpace(req.Name),
+		Phone:             normalizePhone(req.Phone),
+		Email:             strings.ToLower(strings.TrimSpace(req.Email)),
+		PreferredLanguage: strings.ToLower(strings.TrimSpace(req.PreferredLanguage)),
+		Timezone:          strings.TrimSpace(req.Timezone),
+		Region:            strings.ToUpper(strings.TrimSpace(req.Region)),
+		SMSOptIn:          req.SMSOptIn,
+		EmailOptIn:        req.EmailOptIn,
+		WhatsappOptIn:     req.WhatsappOptIn,
+	}
+	if customer.Phone != "" && !customerPhonePattern.MatchString(customer.Phone) {
+		return nil, fmt.Errorf("Invalid phone number %s.", req.Phone)
+	}
+	if customer.Email != "" {
+		if _, err := mail.ParseAddress(customer.Email); err != nil {
+			return nil, fmt.Errorf("Invalid email address %s.", req.Email)
+		}
+	}
+	if customer.PreferredLanguage == "" {
+		customer.PreferredLanguage = cfg.DefaultLanguage
+	}
+	if !slices.Contains(cfg.Languages, customer.PreferredLanguage) {
+		return nil, fmt.Errorf("Unsupported language %s.", customer.PreferredLanguage)
+	}
+	if customer.Timezone == "" {
+		customer.Timezone = cfg.DefaultTimezone
+	}
+	if _, err := time.LoadLocation(customer.Timezone); err != nil {
+		return nil, fmt.Errorf("Invalid timezone %s.", customer.Timezone)
+	}
+	if (customer.SMSOptIn || customer.WhatsappOptIn) && customer.Phone == "" {
+		return nil, fmt.Errorf("SMS and WhatsApp opt-ins require a phone number.")
+	}
+	if customer.EmailOptIn && customer.Email == "" {
+		return nil, fmt.Errorf("Email opt-in requires an email address.")
+	}
+	return customer, nil
+}
+
+func normalizePhone(phone string) string {
+	phone = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '(' || r == ')' {
+			return -1
+		}
+		return r
+	}, phone)
+	switch {
+	case strings.HasPrefix(phone, "0"):
+		return "+62" + phone[1:]
+	case strings.HasPrefix(phone, "62"):
+		return "+" + phone
+	}
+	return phone
+}
+
+func (svc *customerServiceImpl) GetSummary(ctx context.Context, customerID uint) (*dto.CustomerSummaryResponse, error) {
+	billings, err := svc.billingRepo.FindByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	resp := &dto.CustomerSummaryResponse{
+		CustomerID:           customerID,
+		AsOf:                 now,
+		BillingCount:         len(billings),
+		DelinquentBillingIDs: []uint{},
+	}
+	for i := range billings {
+		billing := &billings[i]
+		if billing.Status != model.BillingStatusActive {
+			continue
+		}
+		resp.ActiveBillingCount++
+		resp.TotalOutstanding += billing.Outstanding
+		for j := range billing.Payments {
+			payment := &billing.Payments[j]
+			if payment.Paid {
+				continue
+			}
+			if payment.DueDate.Before(now) {
+				resp.TotalOverdue += payment.Amount + payment.LateFee
+			} else if resp.NextDueDate == nil || payment.DueDate.Before(*resp.NextDueDate) {
+				resp.NextDueDate = &payment.DueDate
+			}
+		}
+		aging := svc.billingSvc.GetAging(billing)
+		if resp.WorstAgingBucket == "" || aging.DaysPastDue > resp.WorstDaysPastDue {
+			resp.WorstDaysPastDue = aging.DaysPastDue
+			resp.WorstAgingBucket = aging.AgingBucket
+		}
+		policy, err := svc.policySvc.ResolvePolicy(ctx, billing)
+		if err != nil {
+			return nil, err
+		}
+		if svc.policySvc.Evaluate(policy, billing, now).IsDelinquent {
+			resp.IsDelinquent = true
+			resp.DelinquentBillingIDs = append(resp.DelinquentBillingIDs, billing.ID)
+		}
+	}
+	limit, err := svc.exposureRepo.FindByCustomerID(ctx, customerID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if limit != nil {
+		available := max(limit.ExposureLimit-resp.TotalOutstanding, 0)
+		resp.ExposureLimit = &limit.ExposureLimit
+		resp.AvailableExposure = &available
+	}
+	return resp, nil
+}
+
+func (svc *customerServiceImpl) SetExposureLimit(ctx context.Context, customerID uint, req dto.ExposureLimitRequest) (*model.CustomerExposureLimit, error) {
+	if req.ExposureLimit <= 0 {
+		return nil, fmt.Errorf("Exposure limit must be positive.")
+	}
+	limit := &model.CustomerExposureLimit{CustomerID: customerID, ExposureLimit: req.ExposureLimit}
+	if err := svc.exposureRepo.Upsert(ctx, limit); err != nil {
+		return nil, err
+	}
+	return svc.exposureRepo.FindByCustomerID(ctx, customerID)
+}
+
+func (svc *customerServiceImpl) RemoveExposureLimit(ctx context.Context, customerID uint) error {
+	return svc.exposureRepo.Delete(ctx, customerID)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCustomerConfig = &config.CustomerConfig{
+	DefaultLanguage: "id",
+	DefaultTimezone: "Asia/Jakarta",
+	Languages:       []string{"id", "en"},
+}
+
+func TestBuildCustomer(t *testing.T) {
+	customer, err := buildCustomer(7, dto.CustomerRequest{
+		Name:          " Budi Santoso ",
+		Phone:         "0812-3456-7890",
+		Email:         "Budi@Example.com",
+		SMSOptIn:      true,
+		WhatsappOptIn: true,
+	}, testCustomerConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), customer.ID)
+	assert.Equal(t, "Budi Santoso", customer.Name)
+	assert.Equal(t, "+6281234567890", customer.Phone)
+	assert.Equal(t, "budi@example.com", customer.Email)
+	assert.Equal(t, "id", customer.PreferredLanguage)
+	assert.Equal(t, "Asia/Jakarta", customer.Timezone)
+
+	customer, err = buildCustomer(7, dto.CustomerRequest{Phone: "6281234567890", PreferredLanguage: "EN", Timezone: "Asia/Makassar"}, testCustomerConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "+6281234567890", customer.Phone)
+	assert.Equal(t, "en", customer.PreferredLanguage)
+	assert.Equal(t, "Asia/Makassar", customer.Timezone)
+}
+
+func TestBuildCustomer_Invalid(t *testing.T) {
+	_, err := buildCustomer(7, dto.CustomerRequest{Phone: "12ab"}, testCustomerConfig)
+	assert.EqualError(t, err, "Invalid phone number 12ab.")
+	_, err = buildCustomer(7, dto.CustomerRequest{Email: "budi"}, testCustomerConfig)
+	assert.EqualError(t, err, "Invalid email address budi.")
+	_, err = buildCustomer(7, dto.CustomerRequest{PreferredLanguage: "fr"}, testCustomerConfig)
+	assert.EqualError(t, err, "Unsupported language fr.")
+	_, err = buildCustomer(7, dto.CustomerRequest{Timezone: "Mars/Olympus"}, testCustomerConfig)
+	assert.EqualError(t, err, "Invalid timezone Mars/Olympus.")
+	_, err = buildCustomer(7, dto.CustomerRequest{SMSOptIn: true}, testCustomerConfig)
+	assert.EqualError(t, err, "SMS and WhatsApp opt-ins require a phone number.")
+	_, err = buildCustomer(7, dto.CustomerRequest{EmailOptIn: true}, testCustomerConfig)
+	assert.EqualError(t, err, "Email opt-in requires an email address.")
+}
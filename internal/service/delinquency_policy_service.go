@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"gorm.io/gorm"
+)
+
+type DelinquencyPolicyService interface {
+	WithTransaction(tx *gorm.DB) DelinquencyPolicyService
+	CreatePolicy(ctx context.Context, req dto.DelinquencyPolicyRequest) (*model.DelinquencyPolicy, error)
+	UpdatePolicy(ctx context.Context, id uint, req dto.DelinquencyPolicyRequest) (*model.DelinquencyPolicy, error)
+	GetPolicies(ctx context.Context) ([]model.DelinquencyPolicy, error)
+	AssignPolicy(ctx context.Context, billingID uint, req dto.AssignDelinquencyPolicyRequest) (*model.Billing, error)
+	ResolvePolicy(ctx context.Context, billing *model.Billing) (*model.DelinquencyPolicy, error)
+	Evaluate(policy *model.DelinquencyPolicy, billing *model.Billing, now time.Time) dto.Delinquency
+}
+
+type delinquencyPolicyServiceImpl struct {
+	repo          repository.DelinquencyPolicyRepository
+	billingRepo   repository.BillingRepository
+	defaultPolicy model.DelinquencyPolicy
+	promiseCfg    *config.PaymentPromiseConfig
+}
+
+func NewDelinquencyPolicyService(repo repository.DelinquencyPolicyRepository, billingRepo repository.BillingRepository, cfg *config.BillingConfig, promiseCfg *config.PaymentPromiseConfig) DelinquencyPolicyService {
+	return &delinquencyPolicyServiceImpl{
+		repo:        repo,
+		billingRepo: billingRepo,
+		defaultPolicy: model.DelinquencyPolicy{
+			Code:      model.DefaultDelinquencyPolicyCode,
+			Rule:      model.DelinquencyRuleConsecutiveMisses,
+			Threshold: cfg.MissedPaymentMax,
+			CureRule:  model.CureRuleArrearsCleared,
+		},
+		promiseCfg: promiseCfg,
+	}
+}
+
+func (svc *delinquencyPolicyServiceImpl) WithTransaction(tx *gorm.DB) DelinquencyPolicyService {
+	return &delinquencyPolicyServiceImpl{
+		repo:          svc.repo.WithTransaction(tx),
+		billingRepo:   svc.billingRepo.WithTransaction(tx),
+		defaultPolicy: svc.defaultPolicy,
+		promiseCfg:    svc.promiseCfg,
+	}
+}
+
+func validateDelinquencyPolicyRequest(req dto.DelinquencyPolicyRequest) error {
+	if req.Code == "" {
+		return fmt.Errorf("Policy code is required.")
+	}
+	if req.Code == model.DefaultDelinquencyPolicyCode {
+		return fmt.Errorf("Policy code %s is reserved.", model.DefaultDelinquencyPolicyCode)
+	}
+	switch req.Rule {
+	case model.DelinquencyRuleConsecutiveMisses, model.DelinquencyRuleDPDThreshold:
+	case model.DelinquencyRuleWindowMisses:
+		if req.WindowWeeks <= 0 {
+			return fmt.Errorf("Rule %s requires a positive windowWeeks.", req.Rule)
+		}
+	default:
+		return fmt.Errorf("Unknown delinquency rule %q.", req.Rule)
+	}
+	if req.Threshold <= 0 {
+		return fmt.Errorf("Policy threshold must be positive.")
+	}
+	switch req.CureRule {
+	case "", model.CureRuleArrearsCleared:
+	case model.CureRuleOnTimePayments:
+		if req.CurePayments <= 0 {
+			return fmt.Errorf("Cure rule %s requires a positive curePayments.", req.CureRule)
+		}
+	default:
+		return fmt.Errorf("Unknown cure rule %q.", req.CureRule)
+	}
+	return nil
+}
+
+func applyDelinquencyPolicyRequest(policy *model.DelinquencyPolicy, req dto.DelinquencyPolicyRequest) {
+	policy.Code = req.Code
+	policy.ProductCode = req.ProductCode
+	if policy.ProductCode != nil && *policy.ProductCode == "" {
+		policy.ProductCode = nil
+	}
+	policy.Rule = req.Rule
+	policy.Threshold = req.Threshold
+	policy.WindowWeeks = req.WindowWeeks
+	policy.CureRule = req.CureRule
+	if policy.CureRule == "" {
+		policy.CureRule = model.CureRuleArrearsCleared
+	}
+	policy.CurePayments = req.CurePayments
+}
+
+func (svc *delinquencyPolicyServiceImpl) CreatePolicy(ctx context.Context, req dto.DelinquencyPolicyRequest) (*model.DelinquencyPolicy, error) {
+	if err := validateDelinquencyPolicyRequest(req); err != nil {
+		return nil, err
+	}
+	policy := &model.DelinquencyPolicy{}
+	applyDelinquencyPolicyRequest(policy, req)
+	if err := svc.repo.Create(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (svc *delinquencyPolicyServiceImpl) UpdatePolicy(ctx context.Context, id uint, req dto.DelinquencyPolicyRequest) (*model.DelinquencyPolicy, error) {
+	if err := validateDelinquencyPolicyRequest(req); err != nil {
+		return nil, err
+	}
+	policy, err := svc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	applyDelinquencyPolicyRequest(policy, req)
+	if err := svc.repo.Update(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (svc *delinquencyPolicyServiceImpl) GetPolicies(ctx context.Context) ([]model.DelinquencyPolicy, error) {
+	policies, err := svc.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]model.DelinquencyPolicy{svc.defaultPolicy}, policies...), nil
+}
+
+func (svc *delinquencyPolicyServiceImpl) AssignPolicy(ctx context.Context, billingID uint, req dto.AssignDelinquencyPolicyRequest) (*model.Billing, error) {
+	billing, err := svc.billingRepo.FindByID(ctx, billingID)
+	if err != nil {
+		return nil, err
+	}
+	if req.PolicyID != nil {
+		if _, err := svc.repo.FindByID(ctx, *req.PolicyID); err != nil {
+			return nil, err
+		}
+	}
+	if err := svc.billingRepo.UpdateDelinquencyPolicy(ctx, billing.ID, req.PolicyID); err != nil {
+		return nil, err
+	}
+	billing.DelinquencyPolicyID = req.PolicyID
+	return billing, nil
+}
+
+func (svc *delinquencyPolicyServiceImpl) ResolvePolicy(ctx context.Context, billing *model.Billing) (*model.DelinquencyPolicy, error) {
+	if billing.DelinquencyPolicyID != nil {
+		return svc.repo.FindByID(ctx, *billing.DelinquencyPolicyID)
+	}
+	if billing.ProductCode != "" {
+		policy, err := svc.repo.FindByProductCode(ctx, billing.ProductCode)
+		if err == nil {
+			return policy, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	policy := svc.defaultPolicy
+	return &policy, nil
+}
+
+func (svc *delinquencyPolicyServiceImpl) Evaluate(policy *model.DelinquencyPolicy, billing *model.Billing, now time.Time) dto.Delinquency {
+	if isFrozen(billing.Freezes, now) {
+		return dto.Delinquency{PolicyCode: policy.Code, Frozen: true}
+	}
+	delinquency := evaluateDelinquency(policy, billing.Payments, now)
+	if delinquency.IsDelinquent && svc.promiseCfg.SuppressDelinquency && hasPendingPromise(billing.Promises, now) {
+		delinquency.IsDelinquent = false
+		delinquency.Promised = true
+	}
+	return delinquency
+}
+
+func evaluateDelinquency(policy *model.DelinquencyPolicy, payments []model.Payment, now time.Time) dto.Delinquency {
+	result := dto.Delinquency{PolicyCode: policy.Code}
+	due := make([]model.Payment, 0, len(payments))
+	for _, p := range payments {
+		if p.DueDate.Before(now) {
+			due = append(due, p)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].Week < due[j].Week })
+
+	if triggered, reason := evaluateDelinquencyRule(policy, due, now, false); triggered {
+		result.IsDelinquent = true
+		result.Rule = policy.Rule
+		result.Reason = reason
+		return result
+	}
+	if policy.CureRule != model.CureRuleOnTimePayments {
+		return result
+	}
+	triggered, _ := evaluateDelinquencyRule(policy, due, now, true)
+	if !triggered {
+		return result
+	}
+	onTime := 0
+	for i := len(due) - 1; i >= 0 && paymentLateness(due[i], now) == 0; i-- {
+		onTime++
+	}
+	if onTime < policy.CurePayments {
+		result.IsDelinquent = true
+		result.Rule = policy.CureRule
+		result.Reason = fmt.Sprintf("%s triggered, cure requires %d on-time payments, %d made", policy.Rule, policy.CurePayments, onTime)
+	}
+	return result
+}
+
+func paymentLateness(p model.Payment, now time.Time) int {
+	if p.Paid && p.PaidDate != nil {
+		return utils.DaysPastDue(p.DueDate, *p.PaidDate)
+	}
+	if p.Paid {
+		return 0
+	}
+	return utils.DaysPastDue(p.DueDate, now)
+}
+
+func evaluateDelinquencyRule(policy *model.DelinquencyPolicy, due []model.Payment, now time.Time, includeLatePaid bool) (bool, string) {
+	missed := func(p model.Payment) bool {
+		if !p.Paid {
+			return true
+		}
+		return includeLatePaid && paymentLateness(p, now) > 0
+	}
+	switch policy.Rule {
+	case model.DelinquencyRuleConsecutiveMisses:
+		run, longest := 0, 0
+		for _, p := range due {
+			if missed(p) {
+				run++
+			} else {
+				run = 0
+			}
+			longest = max(longest, run)
+		}
+		return longest >= policy.Threshold, fmt.Sprintf("%d consecutive missed installments, threshold %d", longest, policy.Threshold)
+	case model.DelinquencyRuleWindowMisses:
+		windowStart := now.AddDate(0, 0, -7*policy.WindowWeeks)
+		count := 0
+		for _, p := range due {
+			if !p.DueDate.Before(windowStart) && missed(p) {
+				count++
+			}
+		}
+		return count >= policy.Threshold, fmt.Sprintf("%d missed installments in the last %d weeks, threshold %d", count, policy.WindowWeeks, policy.Threshold)
+	case model.DelinquencyRuleDPDThreshold:
+		dpd := 0
+		for _, p := range due {
+			if missed(p) {
+				dpd = max(dpd, paymentLateness(p, now))
+			}
+		}
+		return dpd >= policy.Threshold, fmt.Sprintf("%d days past due, threshold %d", dpd, policy.Threshold)
+	}
+	return false, ""
+}
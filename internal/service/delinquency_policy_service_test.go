@@ -0,0 +1,94 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPayments(now time.Time, weeks int, paid map[int]int) []model.Payment {
+	payments := make([]model.Payment, weeks)
+	for i := range payments {
+		week := i + 1
+		dueDate := now.AddDate(0, 0, 7*(week-weeks))
+		payments[i] = model.Payment{Week: week, DueDate: dueDate.Add(-time.Hour)}
+		if lateDays, ok := paid[week]; ok {
+			paidDate := dueDate.Add(-2*time.Hour).AddDate(0, 0, lateDays)
+			payments[i].Paid = true
+			payments[i].PaidDate = &paidDate
+		}
+	}
+	return payments
+}
+
+func TestEvaluateDelinquency_ConsecutiveMisses(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	policy := &model.DelinquencyPolicy{Code: "C2", Rule: model.DelinquencyRuleConsecutiveMisses, Threshold: 2}
+
+	result := evaluateDelinquency(policy, testPayments(now, 4, map[int]int{1: 0, 2: 0, 3: 0}), now)
+	assert.False(t, result.IsDelinquent)
+	assert.Equal(t, "C2", result.PolicyCode)
+
+	result = evaluateDelinquency(policy, testPayments(now, 4, map[int]int{1: 0, 2: 0}), now)
+	assert.True(t, result.IsDelinquent)
+	assert.Equal(t, model.DelinquencyRuleConsecutiveMisses, result.Rule)
+
+	result = evaluateDelinquency(policy, testPayments(now, 4, map[int]int{1: 0, 3: 0}), now)
+	assert.False(t, result.IsDelinquent)
+}
+
+func TestEvaluateDelinquency_IgnoresInstallmentsNotDue(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	policy := &model.DelinquencyPolicy{Rule: model.DelinquencyRuleConsecutiveMisses, Threshold: 2}
+	payments := testPayments(now.AddDate(0, 0, 21), 4, map[int]int{})
+
+	result := evaluateDelinquency(policy, payments, now)
+	assert.False(t, result.IsDelinquent)
+}
+
+func TestEvaluateDelinquency_WindowMisses(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	policy := &model.DelinquencyPolicy{Rule: model.DelinquencyRuleWindowMisses, Threshold: 3, WindowWeeks: 6}
+
+	result := evaluateDelinquency(policy, testPayments(now, 10, map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0, 6: 0, 8: 0, 10: 0}), now)
+	assert.False(t, result.IsDelinquent)
+
+	result = evaluateDelinquency(policy, testPayments(now, 10, map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 6: 0, 8: 0}), now)
+	assert.True(t, result.IsDelinquent)
+	assert.Equal(t, model.DelinquencyRuleWindowMisses, result.Rule)
+}
+
+func TestEvaluateDelinquency_DPDThreshold(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	policy := &model.DelinquencyPolicy{Rule: model.DelinquencyRuleDPDThreshold, Threshold: 14}
+
+	result := evaluateDelinquency(policy, testPayments(now, 4, map[int]int{1: 0, 2: 0}), now)
+	assert.False(t, result.IsDelinquent)
+
+	result = evaluateDelinquency(policy, testPayments(now, 4, map[int]int{1: 0}), now)
+	assert.True(t, result.IsDelinquent)
+	assert.Equal(t, model.DelinquencyRuleDPDThreshold, result.Rule)
+}
+
+func TestEvaluateDelinquency_OnTimePaymentsCure(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	policy := &model.DelinquencyPolicy{
+		Rule:         model.DelinquencyRuleConsecutiveMisses,
+		Threshold:    2,
+		CureRule:     model.CureRuleOnTimePayments,
+		CurePayments: 2,
+	}
+
+	result := evaluateDelinquency(policy, testPayments(now, 5, map[int]int{1: 0, 2: 10, 3: 3, 4: 0}), now)
+	assert.True(t, result.IsDelinquent)
+	assert.Equal(t, model.CureRuleOnTimePayments, result.Rule)
+
+	result = evaluateDelinquency(policy, testPayments(now, 5, map[int]int{1: 0, 2: 10, 3: 3, 4: 0, 5: 0}), now)
+	assert.False(t, result.IsDelinquent)
+
+	policy.CureRule = model.CureRuleArrearsCleared
+	result = evaluateDelinquency(policy, testPayments(now, 5, map[int]int{1: 0, 2: 10, 3: 3, 4: 0}), now)
+	assert.False(t, result.IsDelinquent)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/doddeeph/billing-engine/pkg/events"
+	"gorm.io/gorm"
+)
+
+type BillingEvaluationStep func(ctx context.Context, trx *gorm.DB, billing *model.Billing, now time.Time) error
+
+type DelinquencyService interface {
+	WithTransaction(tx *gorm.DB) DelinquencyService
+	EvaluateBilling(ctx context.Context, billingID uint, source string) (*model.DelinquencyHistory, error)
+	EvaluateActiveBillings(ctx context.Context, steps ...BillingEvaluationStep) (*dto.DelinquencyEvaluationResult, error)
+	GetHistory(ctx context.Context, billingID uint) ([]model.DelinquencyHistory, error)
+}
+
+type delinquencyServiceImpl struct {
+	repo        repository.DelinquencyHistoryRepository
+	billingRepo repository.BillingRepository
+	policySvc   DelinquencyPolicyService
+	outboxSvc   OutboxService
+	cfg         *config.BillingConfig
+}
+
+func NewDelinquencyService(repo repository.DelinquencyHistoryRepository, billingRepo repository.BillingRepository, policySvc DelinquencyPolicyService, outboxSvc OutboxService, cfg *config.BillingConfig) DelinquencyService {
+	return &delinquencyServiceImpl{repo: repo, billingRepo: billingRepo, policySvc: policySvc, outboxSvc: outboxSvc, cfg: cfg}
+}
+
+func (svc *delinquencyServiceImpl) WithTransaction(tx *gorm.DB) DelinquencyService {
+	return &delinquencyServiceImpl{
+		repo:        svc.repo.WithTransaction(tx),
+		billingRepo: svc.billingRepo.WithTransaction(tx),
+		policySvc:   svc.policySvc.WithTransaction(tx),
+		outboxSvc:   svc.outboxSvc.WithTransaction(tx),
+		cfg:         svc.cfg,
+	}
+}
+
+func (svc *delinquencyServiceImpl) EvaluateBilling(ctx context.Context, billingID uint, source string) (*model.DelinquencyHistory, error) {
+	billing, err := svc.billingRepo.FindByID(ctx, billingID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := svc.policySvc.ResolvePolicy(ctx, billing)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	delinquency := svc.policySvc.Evaluate(policy, billing, now)
+	if delinquency.Frozen || delinquency.Promised {
+		return nil, nil
+	}
+	status := model.DelinquencyStatusCurrent
+	if delinquency.IsDelinquent {
+		status = model.DelinquencyStatusDelinquent
+	}
+	if status == billing.DelinquencyStatus {
+		return nil, nil
+	}
+	history := &model.DelinquencyHistory{
+		BillingID:  billing.ID,
+		FromStatus: billing.DelinquencyStatus,
+		ToStatus:   status,
+		PolicyCode: delinquency.PolicyCode,
+		Rule:       delinquency.Rule,
+		Reason:     delinquency.Reason,
+		Source:     source,
+		ChangedAt:  now,
+	}
+	if err := svc.billingRepo.UpdateDelinquencyStatus(ctx, billing.ID, status); err != nil {
+		return nil, err
+	}
+	if err := svc.repo.Create(ctx, history); err != nil {
+		return nil, err
+	}
+	if status == model.DelinquencyStatusDelinquent {
+		err = svc.outboxSvc.Record(ctx, billing.ID, events.TypeBillingDelinquent, events.BillingDelinquent{
+			BillingID:    billing.ID,
+			CustomerID:   billing.CustomerID,
+			LoanID:       billing.LoanID,
+			PolicyCode:   history.PolicyCode,
+			Rule:         history.Rule,
+			Reason:       history.Reason,
+			Source:       history.Source,
+			DelinquentAt: history.ChangedAt,
+		})
+	} else {
+		err = svc.outboxSvc.Record(ctx, billing.ID, events.TypeBillingCured, events.BillingCured{
+			BillingID:  billing.ID,
+			CustomerID: billing.CustomerID,
+			LoanID:     billing.LoanID,
+			PolicyCode: history.PolicyCode,
+			Source:     history.Source,
+			CuredAt:    history.ChangedAt,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (svc *delinquencyServiceImpl) EvaluateActiveBillings(ctx context.Context, steps ...BillingEvaluationStep) (*dto.DelinquencyEvaluationResult, error) {
+	result := &dto.DelinquencyEvaluationResult{}
+	var errs []error
+	var afterID uint
+	now := time.Now()
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, errors.Join(append(errs, err)...)
+		}
+		billingIDs, err := svc.billingRepo.FindIDsByStatus(ctx, model.BillingStatusActive, afterID, svc.cfg.EvaluationBatchSize)
+		if err != nil {
+			return result, errors.Join(append(errs, err)...)
+		}
+		if len(billingIDs) == 0 {
+			return result, errors.Join(errs...)
+		}
+		for _, billingID := range billingIDs {
+			var history *model.DelinquencyHistory
+			err := svc.billingRepo.WithDB().Transaction(func(trx *gorm.DB) error {
+				if len(steps) > 0 {
+					billing, err := svc.billingRepo.WithTransaction(trx).FindByIDForUpdate(ctx, billingID)
+					if err != nil {
+						return err
+					}
+					for _, step := range steps {
+						if err := step(ctx, trx, billing, now); err != nil {
+							return err
+						}
+					}
+				}
+				var err error
+				history, err = svc.WithTransaction(trx).EvaluateBilling(ctx, billingID, model.DelinquencySourceEvaluator)
+				return err
+			})
+			if err != nil {
+				result.Failed++
+				errs = append(errs, fmt.Errorf("billing %d: %w", billingID, err))
+				continue
+			}
+			result.Evaluated++
+			if history != nil {
+				result.Changed++
+			}
+		}
+		afterID = billingIDs[len(billingIDs)-1]
+	}
+}
+
+func (svc *delinquencyServiceImpl) GetHistory(ctx context.Context, billingID uint) ([]model.DelinquencyHistory, error) {
+	if _, err := svc.billingRepo.FindByID(ctx, billingID); err != nil {
+		return nil, err
+	}
+	return svc.repo.FindByBillingID(ctx, billingID)
+}
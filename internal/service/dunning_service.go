@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/notification"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"gorm.io/gorm"
+)
+
+type DunningService interface {
+	CreateStrategy(ctx context.Context, req dto.DunningStrategyRequest) (*model.DunningStrategy, error)
+	UpdateStrategy(ctx context.Context, id uint, req dto.DunningStrategyRequest) (*model.DunningStrategy, error)
+	GetStrategies(ctx context.Context) ([]model.DunningStrategy, error)
+	ResolveStrategy(ctx context.Context, billing *model.Billing) (*model.DunningStrategy, error)
+	GetBillingLog(ctx context.Context, billingID uint) ([]model.DunningLog, error)
+	RunDunning(ctx context.Context, now time.Time) (int, int, error)
+}
+
+type dunningServiceImpl struct {
+	repo            repository.DunningRepository
+	billingRepo     repository.BillingRepository
+	customerRepo    repository.CustomerRepository
+	channels        map[string]notification.Channel
+	renderer        *notification.Renderer
+	billingCfg      *config.BillingConfig
+	customerCfg     *config.CustomerConfig
+	promiseCfg      *config.PaymentPromiseConfig
+	defaultStrategy model.DunningStrategy
+}
+
+func NewDunningService(repo repository.DunningRepository, billingRepo repository.BillingRepository, customerRepo repository.CustomerRepository, channels map[string]notification.Channel, renderer *notification.Renderer, billingCfg *config.BillingConfig, customerCfg *config.CustomerConfig, promiseCfg *config.PaymentPromiseConfig, cfg *config.DunningConfig) DunningService {
+	defaultStrategy := model.DunningStrategy{Code: model.DefaultDunningStrategyCode}
+	for _, step := range cfg.DefaultSteps {
+		defaultStrategy.Steps = append(defaultStrategy.Steps, model.DunningStep{DaysPastDue: step.DaysPastDue, Action: step.Action})
+	}
+	return &dunningServiceImpl{
+		repo:            repo,
+		billingRepo:     billingRepo,
+		customerRepo:    customerRepo,
+		channels:        channels,
+		renderer:        renderer,
+		billingCfg:      billingCfg,
+		customerCfg:     customerCfg,
+		promiseCfg:      promiseCfg,
+		defaultStrategy: defaultStrategy,
+	}
+}
+
+func validateDunningStrategyRequest(req dto.DunningStrategyRequest) error {
+	if req.Code == "" {
+		return fmt.Errorf("Strategy code is required.")
+	}
+	if req.Code == model.DefaultDunningStrategyCode {
+		return fmt.Errorf("Strategy code %s is reserved.", model.DefaultDunningStrategyCode)
+	}
+	if len(req.Steps) == 0 {
+		return fmt.Errorf("Strategy requires at least one step.")
+	}
+	seen := make(map[int]bool, len(req.Steps))
+	for _, step := range req.Steps {
+		if step.DaysPastDue <= 0 {
+			return fmt.Errorf("Step daysPastDue must be positive.")
+		}
+		if seen[step.DaysPastDue] {
+			return fmt.Errorf("Duplicate step at %d days past due.", step.DaysPastDue)
+		}
+		seen[step.DaysPastDue] = true
+		switch strings.ToUpper(step.Action) {
+		case model.DunningActionSMS, model.DunningActionEmail, model.DunningActionWhatsapp, model.DunningActionCall, model.DunningActionLetter:
+		default:
+			return fmt.Errorf("Unknown dunning action %q.", step.Action)
+		}
+	}
+	return nil
+}
+
+func applyDunningStrategyRequest(strategy *model.DunningStrategy, req dto.DunningStrategyRequest) {
+	strategy.Code = req.Code
+	strategy.ProductCode = req.ProductCode
+	if strategy.ProductCode != nil && *strategy.ProductCode == "" {
+		strategy.ProductCode = nil
+	}
+	strategy.Steps = make([]model.DunningStep, 0, len(req.Steps))
+	for _, step := range req.Steps {
+		strategy.Steps = append(strategy.Steps, model.DunningStep{DaysPastDue: step.DaysPastDue, Action: strings.ToUpper(step.Action)})
+	}
+	sort.Slice(strategy.Steps, func(i, j int) bool { return strategy.Steps[i].DaysPastDue < strategy.Steps[j].DaysPastDue })
+}
+
+func (svc *dunningServiceImpl) CreateStrategy(ctx context.Context, req dto.DunningStrategyRequest) (*model.DunningStrategy, error) {
+	if err := validateDunningStrategyRequest(req); err != nil {
+		return nil, err
+	}
+	strategy := &model.DunningStrategy{}
+	applyDunningStrategyRequest(strategy, req)
+	if err := svc.repo.CreateStrategy(ctx, strategy); err != nil {
+		return nil, err
+	}
+	return strategy, nil
+}
+
+func (svc *dunningServiceImpl) UpdateStrategy(ctx context.Context, id uint, req dto.DunningStrategyRequest) (*model.DunningStrategy, error) {
+	if err := validateDunningStrategyRequest(req); err != nil {
+		return nil, err
+	}
+	strategy, err := svc.repo.FindStrategyByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	applyDunningStrategyRequest(strategy, req)
+	if err := svc.repo.UpdateStrategy(ctx, strategy); err != nil {
+		return nil, err
+	}
+	return strategy, nil
+}
+
+func (svc *dunningServiceImpl) GetStrategies(ctx context.Context) ([]model.DunningStrategy, error) {
+	strategies, err := svc.repo.FindStrategies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]model.DunningStrategy{svc.defaultStrategy}, strategies...), nil
+}
+
+func (svc *dunningServiceImpl) ResolveStrategy(ctx context.Context, billing *model.Billing) (*model.DunningStrategy, error) {
+	if billing.ProductCode != "" {
+		strategy, err := svc.repo.FindStrategyByProductCode(ctx, billing.ProductCode)
+		if err == nil {
+			return strategy, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	strategy := svc.defaultStrategy
+	return &strategy, nil
+}
+
+func (svc *dunningServiceImpl) GetBillingLog(ctx context.Context, billingID uint) ([]model.DunningLog, error) {
+	return svc.repo.FindLogsByBillingID(ctx, billingID)
+}
+
+func (svc *dunningServiceImpl) RunDunning(ctx context.Context, now time.Time) (int, int, error) {
+	processed, failed := 0, 0
+	var errs []error
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return processed, failed, errors.Join(append(errs, err)...)
+		}
+		billingIDs, err := svc.billingRepo.FindIDsByStatus(ctx, model.BillingStatusActive, afterID, svc.billingCfg.EvaluationBatchSize)
+		if err != nil {
+			return processed, failed, errors.Join(append(errs, err)...)
+		}
+		if len(billingIDs) == 0 {
+			return processed, failed, errors.Join(errs...)
+		}
+		for _, billingID := range billingIDs {
+			entry, err := svc.dunBilling(ctx, billingID, now)
+			if err != nil {
+				failed++
+				errs = append(errs, fmt.Errorf("billing %d: %w", billingID, err))
+				continue
+			}
+			if entry != nil && entry.Status == model.DunningStatusFailed {
+				failed++
+				continue
+			}
+			processed++
+		}
+		afterID = billingIDs[len(billingIDs)-1]
+	}
+}
+
+func (svc *dunningServiceImpl) dunBilling(ctx context.Context, billingID uint, now time.Time) (*model.DunningLog, error) {
+	billing, err := svc.billingRepo.FindByID(ctx, billingID)
+	if err != nil {
+		return nil, err
+	}
+	if isFrozen(billing.Freezes, now) {
+		return nil, nil
+	}
+	if svc.promiseCfg.SuppressDunning && hasPendingPromise(billing.Promises, now) {
+		return nil, nil
+	}
+	logs, err := svc.repo.FindLogsByBillingID(ctx, billing.ID)
+	if err != nil {
+		return nil, err
+	}
+	cycle, open, lastStep := dunningProgress(logs)
+	oldest, overdueAmount := overdueInstallments(billing.Payments, now)
+	dpd := 0
+	if oldest != nil {
+		dpd = utils.DaysPastDue(oldest.DueDate, now)
+	}
+	strategy, err := svc.ResolveStrategy(ctx, billing)
+	if err != nil {
+		return nil, err
+	}
+	entry := &model.DunningLog{
+		BillingID:     billing.ID,
+		Cycle:         cycle,
+		StrategyCode:  strategy.Code,
+		DaysPastDue:   dpd,
+		OverdueAmount: overdueAmount,
+		TriggeredAt:   now,
+	}
+	if dpd == 0 {
+		if !open {
+			return nil, nil
+		}
+		entry.Status = model.DunningStatusStopped
+		entry.Reason = "Billing is cured."
+		return entry, svc.repo.CreateLog(ctx, entry)
+	}
+	if !open {
+		entry.Cycle++
+		lastStep = 0
+	}
+	step := nextDunningStep(strategy.Steps, dpd, lastStep)
+	if step == nil {
+		return nil, nil
+	}
+	entry.StepDaysPastDue = step.DaysPastDue
+	entry.Action = step.Action
+	if err := svc.runStep(ctx, billing, oldest, entry); err != nil {
+		return nil, err
+	}
+	return entry, svc.repo.CreateLog(ctx, entry)
+}
+
+func (svc *dunningServiceImpl) runStep(ctx context.Context, billing *model.Billing, oldest *model.Payment, entry *model.DunningLog) error {
+	customer, err := svc.customerRepo.FindByID(ctx, billing.CustomerID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	language := svc.customerCfg.DefaultLanguage
+	if customer != nil && customer.PreferredLanguage != "" {
+		language = customer.PreferredLanguage
+	}
+	data := dunningTemplateData(billing, oldest, customer, entry, language, customerLocation(customer, svc.customerCfg))
+	switch entry.Action {
+	case model.DunningActionCall:
+		if customer == nil || customer.Phone == "" {
+			entry.Status = model.DunningStatusSkipped
+			entry.Reason = "Customer has no phone number."
+			return nil
+		}
+		entry.Status = model.DunningStatusCreated
+		entry.Recipient = customer.Phone
+	case model.DunningActionLetter:
+		subject, body, err := svc.renderer.Render(language, notification.TemplateLetter, data)
+		if err != nil {
+			entry.Status = model.DunningStatusFailed
+			entry.Reason = err.Error()
+			return nil
+		}
+		entry.Status = model.DunningStatusCreated
+		entry.Subject = subject
+		entry.Body = body
+	case model.DunningActionSMS, model.DunningActionEmail, model.DunningActionWhatsapp:
+		entry.Recipient = reminderRecipient(customer, entry.Action)
+		if entry.Recipient == "" {
+			entry.Status = model.DunningStatusSkipped
+			entry.Reason = fmt.Sprintf("Customer opted out of %s.", entry.Action)
+			return nil
+		}
+		ref, err := svc.send(ctx, entry, language, data)
+		if err != nil {
+			entry.Status = model.DunningStatusFailed
+			entry.Reason = err.Error()
+			return nil
+		}
+		entry.Status = model.DunningStatusSent
+		entry.ProviderRef = ref
+	default:
+		entry.Status = model.DunningStatusFailed
+		entry.Reason = fmt.Sprintf("Unknown dunning action %q.", entry.Action)
+	}
+	return nil
+}
+
+func (svc *dunningServiceImpl) send(ctx context.Context, entry *model.DunningLog, language string, data notification.TemplateData) (string, error) {
+	subject, body, err := svc.renderer.Render(language, notification.TemplateDunning, data)
+	if err != nil {
+		return "", err
+	}
+	entry.Subject = subject
+	entry.Body = body
+	channel, ok := svc.channels[entry.Action]
+	if !ok {
+		return "", fmt.Errorf("no adapter for channel %s", entry.Action)
+	}
+	return channel.Send(ctx, notification.Message{
+		Reference: fmt.Sprintf("dunning-%d-%d-%d", entry.BillingID, entry.Cycle, entry.StepDaysPastDue),
+		Channel:   entry.Action,
+		To:        entry.Recipient,
+		Subject:   subject,
+		Body:      body,
+	})
+}
+
+func dunningProgress(logs []model.DunningLog) (int, bool, int) {
+	cycle, open, lastStep := 0, false, 0
+	for _, entry := range logs {
+		if entry.Cycle != cycle {
+			cycle = entry.Cycle
+			lastStep = 0
+		}
+		switch entry.Status {
+		case model.DunningStatusStopped:
+			open = false
+		case model.DunningStatusFailed:
+			open = true
+		default:
+			open = true
+			lastStep = max(lastStep, entry.StepDaysPastDue)
+		}
+	}
+	return cycle, open, lastStep
+}
+
+func nextDunningStep(steps []model.DunningStep, dpd, lastStep int) *model.DunningStep {
+	var next *model.DunningStep
+	for i := range steps {
+		if steps[i].DaysPastDue > lastStep && steps[i].DaysPastDue <= dpd && (next == nil || steps[i].DaysPastDue > next.DaysPastDue) {
+			next = &steps[i]
+		}
+	}
+	return next
+}
+
+func overdueInstallments(payments []model.Payment, now time.Time) (*model.Payment, int) {
+	var oldest *model.Payment
+	amount := 0
+	for i := range payments {
+		if payments[i].Paid || !now.After(payments[i].DueDate) {
+			continue
+		}
+		amount += payments[i].Amount + payments[i].LateFee
+		if oldest == nil || payments[i].DueDate.Before(oldest.DueDate) {
+			oldest = &payments[i]
+		}
+	}
+	return oldest, amount
+}
+
+func dunningTemplateData(billing *model.Billing, oldest *model.Payment, customer *model.Customer, entry *model.DunningLog, language string, loc *time.Location) notification.TemplateData {
+	data := notification.TemplateData{
+		LoanID: billing.LoanID,
+		Amount: notification.FormatRupiah(entry.OverdueAmount),
+		Days:   entry.DaysPastDue,
+	}
+	if customer != nil {
+		data.CustomerName = customer.Name
+	}
+	if oldest != nil {
+		data.Week = oldest.Week
+		data.DueDate = notification.FormatDate(oldest.DueDate.In(loc), language)
+	}
+	for _, virtualAccount := range billing.VirtualAccounts {
+		data.VirtualAccounts = append(data.VirtualAccounts, notification.VirtualAccount{BankCode: virtualAccount.BankCode, Number: virtualAccount.Number})
+	}
+	return data
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var testDunningSteps = []model.DunningStep{
+	{DaysPastDue: 1, Action: model.DunningActionSMS},
+	{DaysPastDue: 7, Action: model.DunningActionCall},
+	{DaysPastDue: 30, Action: model.DunningActionLetter},
+}
+
+func TestNextDunningStep(t *testing.T) {
+	assert.Nil(t, nextDunningStep(testDunningSteps, 0, 0))
+	assert.Equal(t, model.DunningActionSMS, nextDunningStep(testDunningSteps, 1, 0).Action)
+	assert.Nil(t, nextDunningStep(testDunningSteps, 6, 1))
+	assert.Equal(t, model.DunningActionCall, nextDunningStep(testDunningSteps, 8, 1).Action)
+	assert.Equal(t, model.DunningActionLetter, nextDunningStep(testDunningSteps, 45, 0).Action)
+	assert.Nil(t, nextDunningStep(testDunningSteps, 45, 30))
+}
+
+func TestDunningProgress(t *testing.T) {
+	cycle, open, lastStep := dunningProgress(nil)
+	assert.Equal(t, 0, cycle)
+	assert.False(t, open)
+	assert.Equal(t, 0, lastStep)
+
+	logs := []model.DunningLog{
+		{Cycle: 1, StepDaysPastDue: 1, Status: model.DunningStatusSent},
+		{Cycle: 1, StepDaysPastDue: 7, Status: model.DunningStatusFailed},
+	}
+	cycle, open, lastStep = dunningProgress(logs)
+	assert.Equal(t, 1, cycle)
+	assert.True(t, open)
+	assert.Equal(t, 1, lastStep)
+
+	logs = append(logs, model.DunningLog{Cycle: 1, StepDaysPastDue: 7, Status: model.DunningStatusCreated}, model.DunningLog{Cycle: 1, Status: model.DunningStatusStopped})
+	cycle, open, lastStep = dunningProgress(logs)
+	assert.Equal(t, 1, cycle)
+	assert.False(t, open)
+
+	logs = append(logs, model.DunningLog{Cycle: 2, StepDaysPastDue: 1, Status: model.DunningStatusSkipped})
+	cycle, open, lastStep = dunningProgress(logs)
+	assert.Equal(t, 2, cycle)
+	assert.True(t, open)
+	assert.Equal(t, 1, lastStep)
+}
+
+func TestOverdueInstallments(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	payments := testPayments(now, 4, map[int]int{1: 0})
+	payments[2].LateFee = 5000
+
+	oldest, amount := overdueInstallments(payments, now)
+	assert.Equal(t, 2, oldest.Week)
+	assert.Equal(t, 5000, amount)
+
+	oldest, amount = overdueInstallments(payments[:1], now)
+	assert.Nil(t, oldest)
+	assert.Equal(t, 0, amount)
+}
+
+func TestValidateDunningStrategyRequest(t *testing.T) {
+	valid := dto.DunningStrategyRequest{Code: "PAYLATER", Steps: []dto.DunningStepRequest{{DaysPastDue: 1, Action: "sms"}, {DaysPastDue: 14, Action: "CALL"}}}
+	assert.NoError(t, validateDunningStrategyRequest(valid))
+
+	cases := map[string]dto.DunningStrategyRequest{
+		"Strategy code is required.":           {Steps: valid.Steps},
+		"Strategy code DEFAULT is reserved.":   {Code: model.DefaultDunningStrategyCode, Steps: valid.Steps},
+		"Strategy requires at least one step.": {Code: "PAYLATER"},
+		"Step daysPastDue must be positive.":   {Code: "PAYLATER", Steps: []dto.DunningStepRequest{{DaysPastDue: 0, Action: "SMS"}}},
+		"Duplicate step at 1 days past due.":   {Code: "PAYLATER", Steps: []dto.DunningStepRequest{{DaysPastDue: 1, Action: "SMS"}, {DaysPastDue: 1, Action: "EMAIL"}}},
+		"Unknown dunning action \"VISIT\".":    {Code: "PAYLATER", Steps: []dto.DunningStepRequest{{DaysPastDue: 1, Action: "VISIT"}}},
+	}
+	for msg, req := range cases {
+		assert.EqualError(t, validateDunningStrategyRequest(req), msg)
+	}
+
+	strategy := &model.DunningStrategy{}
+	applyDunningStrategyRequest(strategy, dto.DunningStrategyRequest{Code: "PAYLATER", Steps: []dto.DunningStepRequest{{DaysPastDue: 14, Action: "call"}, {DaysPastDue: 1, Action: "sms"}}})
+	assert.Equal(t, []model.DunningStep{{DaysPastDue: 1, Action: model.DunningActionSMS}, {DaysPastDue: 14, Action: model.DunningActionCall}}, strategy.Steps)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"gorm.io/gorm"
+)
+
+type FreezeService interface {
+	FreezeBilling(ctx context.Context, billingID uint, req dto.FreezeRequest) (*model.BillingFreeze, error)
+	FreezeBillings(ctx context.Context, req dto.BatchFreezeRequest) ([]model.BillingFreeze, error)
+	GetFreezes(ctx context.Context, billingID uint) ([]model.BillingFreeze, error)
+}
+
+type freezeServiceImpl struct {
+	repo        repository.FreezeRepository
+	paymentRepo repository.PaymentRepository
+	billingRepo repository.BillingRepository
+}
+
+func NewFreezeService(repo repository.FreezeRepository, paymentRepo repository.PaymentRepository, billingRepo repository.BillingRepository) FreezeService {
+	return &freezeServiceImpl{repo: repo, paymentRepo: paymentRepo, billingRepo: billingRepo}
+}
+
+func validateFreezeRequest(req dto.FreezeRequest) error {
+	if req.StartDate.IsZero() || req.EndDate.IsZero() {
+		return fmt.Errorf("Freeze start and end date are required.")
+	}
+	if !req.EndDate.After(req.StartDate) {
+		return fmt.Errorf("Freeze end date must be after start date.")
+	}
+	if req.Reason == "" {
+		return fmt.Errorf("Freeze reason is required.")
+	}
+	return nil
+}
+
+func freezeWeeks(start, end time.Time) int {
+	return int(math.Ceil(end.Sub(start).Hours() / (24 * 7)))
+}
+
+func (svc *freezeServiceImpl) FreezeBilling(ctx context.Context, billingID uint, req dto.FreezeRequest) (*model.BillingFreeze, error) {
+	if err := validateFreezeRequest(req); err != nil {
+		return nil, err
+	}
+	var freeze *model.BillingFreeze
+	err := svc.repo.WithDB().Transaction(func(trx *gorm.DB) error {
+		var err error
+		freeze, err = svc.freeze(ctx, trx, billingID, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return freeze, nil
+}
+
+func (svc *freezeServiceImpl) FreezeBillings(ctx context.Context, req dto.BatchFreezeRequest) ([]model.BillingFreeze, error) {
+	if err := validateFreezeRequest(req.FreezeRequest); err != nil {
+		return nil, err
+	}
+	filter := repository.BillingFilter{
+		IDs:         req.Filter.BillingIDs,
+		CustomerIDs: req.Filter.CustomerIDs,
+		LoanIDs:     req.Filter.LoanIDs,
+	}
+	if len(filter.IDs) == 0 && len(filter.CustomerIDs) == 0 && len(filter.LoanIDs) == 0 {
+		return nil, fmt.Errorf("Freeze filter must not be empty.")
+	}
+	filter.Status = model.BillingStatusActive
+	billingIDs, err := svc.billingRepo.FindIDsByFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(billingIDs) == 0 {
+		return nil, fmt.Errorf("No active billing matches the freeze filter.")
+	}
+	freezes := make([]model.BillingFreeze, 0, len(billingIDs))
+	err = svc.repo.WithDB().Transaction(func(trx *gorm.DB) error {
+		for _, billingID := range billingIDs {
+			freeze, err := svc.freeze(ctx, trx, billingID, req.FreezeRequest)
+			if err != nil {
+				return err
+			}
+			freezes = append(freezes, *freeze)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return freezes, nil
+}
+
+func (svc *freezeServiceImpl) freeze(ctx context.Context, trx *gorm.DB, billingID uint, req dto.FreezeRequest) (*model.BillingFreeze, error) {
+	trxBillingRepo := svc.billingRepo.WithTransaction(trx)
+	billing, err := trxBillingRepo.FindByIDForUpdate(ctx, billingID)
+	if err != nil {
+		return nil, err
+	}
+	if billing.Outstanding <= 0 {
+		return nil, fmt.Errorf("Billing %d has no outstanding to freeze.", billing.ID)
+	}
+	if overlapsFreeze(billing.Freezes, req.StartDate, req.EndDate) {
+		return nil, fmt.Errorf("Freeze overlaps an existing freeze of billing %d.", billing.ID)
+	}
+	freeze := &model.BillingFreeze{
+		BillingID:    billing.ID,
+		StartDate:    req.StartDate,
+		EndDate:      req.EndDate,
+		Reason:       req.Reason,
+		ShiftedWeeks: freezeWeeks(req.StartDate, req.EndDate),
+	}
+	now := time.Now()
+	if waived := waivedLateFees(billing.Payments, req.StartDate, freeze.ShiftedWeeks, now); waived > 0 {
+		if err := trxBillingRepo.UpdateOutstanding(ctx, billing.ID, billing.Outstanding-waived); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := svc.paymentRepo.WithTransaction(trx).ShiftUnpaid(ctx, billing.ID, req.StartDate, freeze.ShiftedWeeks, now); err != nil {
+		return nil, err
+	}
+	if err := svc.repo.WithTransaction(trx).Create(ctx, freeze); err != nil {
+		return nil, err
+	}
+	return freeze, nil
+}
+
+func (svc *freezeServiceImpl) GetFreezes(ctx context.Context, billingID uint) ([]model.BillingFreeze, error) {
+	return svc.repo.FindByBillingID(ctx, billingID)
+}
+
+func overlapsFreeze(freezes []model.BillingFreeze, start, end time.Time) bool {
+	for _, f := range freezes {
+		if start.Before(f.EndDate) && f.StartDate.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+func waivedLateFees(payments []model.Payment, from time.Time, weeks int, now time.Time) int {
+	waived := 0
+	for _, p := range payments {
+		if !p.Paid && !p.DueDate.Before(from) && p.DueDate.AddDate(0, 0, 7*weeks).After(now) {
+			waived += p.LateFee
+		}
+	}
+	return waived
+}
+
+func isFrozen(freezes []model.BillingFreeze, at time.Time) bool {
+	for _, f := range freezes {
+		if !at.Before(f.StartDate) && at.Before(f.EndDate) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverlapsFreeze(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 8, d, 0, 0, 0, 0, time.UTC) }
+	freezes := []model.BillingFreeze{{StartDate: day(10), EndDate: day(20)}}
+
+	assert.True(t, overlapsFreeze(freezes, day(5), day(11)))
+	assert.True(t, overlapsFreeze(freezes, day(12), day(15)))
+	assert.True(t, overlapsFreeze(freezes, day(19), day(25)))
+	assert.False(t, overlapsFreeze(freezes, day(1), day(10)))
+	assert.False(t, overlapsFreeze(freezes, day(20), day(30)))
+	assert.False(t, overlapsFreeze(nil, day(1), day(30)))
+}
+
+func TestWaivedLateFees(t *testing.T) {
+	now := time.Date(2025, 8, 20, 12, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2025, 8, d, 0, 0, 0, 0, time.UTC) }
+	payments := []model.Payment{
+		{Week: 1, DueDate: day(1), Overdue: true, LateFee: 5000},
+		{Week: 2, DueDate: day(8), Overdue: true, LateFee: 5000},
+		{Week: 3, DueDate: day(15), Overdue: true, LateFee: 5000},
+		{Week: 4, DueDate: day(22)},
+		{Week: 5, DueDate: day(10), Paid: true, LateFee: 5000},
+	}
+
+	assert.Equal(t, 5000, waivedLateFees(payments, day(5), 1, now))
+	assert.Equal(t, 10000, waivedLateFees(payments, day(5), 2, now))
+	assert.Equal(t, 0, waivedLateFees(payments, day(16), 4, now))
+}
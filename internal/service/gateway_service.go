@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/gateway"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"gorm.io/gorm"
+)
+
+var errDuplicateCallback = errors.New("duplicate callback")
+
+type GatewayService interface {
+	HandleCallback(ctx context.Context, providerName string, header http.Header, body []byte) (*model.GatewayCallback, bool, error)
+	GetCallbacks(ctx context.Context, provider, status string, limit int) ([]model.GatewayCallback, error)
+}
+
+type gatewayServiceImpl struct {
+	repo       repository.GatewayCallbackRepository
+	paymentSvc PaymentService
+	vaSvc      VirtualAccountService
+	qrisSvc    QRISService
+	registry   *gateway.Registry
+}
+
+func NewGatewayService(repo repository.GatewayCallbackRepository, paymentSvc PaymentService, vaSvc VirtualAccountService, qrisSvc QRISService, registry *gateway.Registry) GatewayService {
+	return &gatewayServiceImpl{repo: repo, paymentSvc: paymentSvc, vaSvc: vaSvc, qrisSvc: qrisSvc, registry: registry}
+}
+
+func (svc *gatewayServiceImpl) HandleCallback(ctx context.Context, providerName string, header http.Header, body []byte) (*model.GatewayCallback, bool, error) {
+	provider, ok := svc.registry.Get(providerName)
+	if !ok {
+		return nil, false, fmt.Errorf("%w %q.", gateway.ErrUnknownProvider, providerName)
+	}
+	cb, err := provider.ParseCallback(header, body)
+	if err != nil {
+		return nil, false, err
+	}
+	if cb.BillingID == 0 && cb.VirtualAccount != "" {
+		cb.BillingID, err = svc.vaSvc.ResolveBillingID(ctx, cb.VirtualAccount)
+		if err != nil {
+			return nil, false, fmt.Errorf("Unknown virtual account %s.", cb.VirtualAccount)
+		}
+	}
+	if cb.BillingID == 0 && cb.Reference != "" {
+		cb.BillingID, err = svc.qrisSvc.ResolveBillingID(ctx, cb.Reference)
+		if err != nil {
+			return nil, false, fmt.Errorf("Unknown QRIS reference %s.", cb.Reference)
+		}
+	}
+
+	callback, err := svc.repo.FindByExternalID(ctx, provider.Name(), cb.ExternalID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+	if callback != nil && callback.Status != model.GatewayCallbackStatusFailed {
+		return callback, true, nil
+	}
+	isNew := callback == nil
+	if isNew {
+		callback = &model.GatewayCallback{Provider: provider.Name(), ExternalID: cb.ExternalID}
+	} else {
+		callback.Attempts++
+	}
+	paidAt := time.Now()
+	if cb.PaidAt != nil {
+		paidAt = *cb.PaidAt
+	}
+	callback.BillingID = cb.BillingID
+	callback.Amount = cb.Amount
+	callback.Payload = body
+	callback.PaidAt = cb.PaidAt
+	callback.ReceivedAt = time.Now()
+	callback.Error = ""
+	callback.PaymentID = nil
+	callback.RecoveryID = nil
+
+	if !cb.Paid {
+		callback.Status = model.GatewayCallbackStatusIgnored
+		return svc.saveCallback(ctx, callback, isNew)
+	}
+
+	err = svc.repo.WithDB().Transaction(func(trx *gorm.DB) error {
+		trxRepo := svc.repo.WithTransaction(trx)
+		callback.Status = model.GatewayCallbackStatusProcessed
+		claimed, err := svc.claimCallback(ctx, trxRepo, callback, isNew)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return errDuplicateCallback
+		}
+		paymentResp, err := svc.paymentSvc.WithTransaction(trx).ApplyNextInstallment(ctx, cb.BillingID, cb.Amount, paidAt)
+		if err != nil {
+			return err
+		}
+		if paymentResp.Payment != nil {
+			callback.PaymentID = &paymentResp.Payment.ID
+		}
+		if paymentResp.Recovery != nil {
+			callback.RecoveryID = &paymentResp.Recovery.ID
+		}
+		return trxRepo.Update(ctx, callback)
+	})
+	if errors.Is(err, errDuplicateCallback) {
+		callback, err := svc.repo.FindByExternalID(ctx, provider.Name(), cb.ExternalID)
+		return callback, true, err
+	}
+	if err != nil {
+		if isNew {
+			callback.ID = 0
+		}
+		callback.Status = model.GatewayCallbackStatusFailed
+		callback.Error = err.Error()
+		callback.PaymentID = nil
+		callback.RecoveryID = nil
+		return svc.saveCallback(ctx, callback, isNew)
+	}
+	return callback, false, nil
+}
+
+func (svc *gatewayServiceImpl) claimCallback(ctx context.Context, repo repository.GatewayCallbackRepository, callback *model.GatewayCallback, isNew bool) (bool, error) {
+	if isNew {
+		return repo.CreateIfAbsent(ctx, callback)
+	}
+	return repo.UpdateIfFailed(ctx, callback)
+}
+
+func (svc *gatewayServiceImpl) saveCallback(ctx context.Context, callback *model.GatewayCallback, isNew bool) (*model.GatewayCallback, bool, error) {
+	saved, err := svc.claimCallback(ctx, svc.repo, callback, isNew)
+	if err != nil {
+		return nil, false, err
+	}
+	if !saved {
+		existing, err := svc.repo.FindByExternalID(ctx, callback.Provider, callback.ExternalID)
+		return existing, true, err
+	}
+	return callback, false, nil
+}
+
+func (svc *gatewayServiceImpl) GetCallbacks(ctx context.Context, provider, status string, limit int) ([]model.GatewayCallback, error) {
+	return svc.repo.FindRecent(ctx, provider, status, limit)
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"gorm.io/gorm"
+)
+
+const loanImportChunkSize = 500
+
+type LoanImportService interface {
+	SubmitImport(ctx context.Context, data []byte) (*model.LoanImport, error)
+	ProcessPending(ctx context.Context) (int, error)
+	RetryImport(ctx context.Context, id uint) (*model.LoanImport, error)
+	GetImport(ctx context.Context, id uint, status string) (*model.LoanImport, error)
+}
+
+type loanImportServiceImpl struct {
+	repo        repository.LoanImportRepository
+	billingRepo repository.BillingRepository
+	billingSvc  BillingService
+}
+
+func NewLoanImportService(repo repository.LoanImportRepository, billingRepo repository.BillingRepository, billingSvc BillingService) LoanImportService {
+	return &loanImportServiceImpl{repo: repo, billingRepo: billingRepo, billingSvc: billingSvc}
+}
+
+func (svc *loanImportServiceImpl) SubmitImport(ctx context.Context, data []byte) (*model.LoanImport, error) {
+	rows, err := parseLoanImportRows(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("Loan import has no rows.")
+	}
+	loanImport := &model.LoanImport{
+		Status:    model.LoanImportStatusPending,
+		TotalRows: len(rows),
+	}
+	for _, row := range rows {
+		if row.Status == model.LoanImportRowStatusPending {
+			loanImport.PendingRows++
+		} else {
+			loanImport.FailedRows++
+		}
+	}
+	if err := svc.repo.CreateImport(ctx, loanImport, rows); err != nil {
+		return nil, err
+	}
+	return loanImport, nil
+}
+
+func (svc *loanImportServiceImpl) ProcessPending(ctx context.Context) (int, error) {
+	loanImports, err := svc.repo.FindPendingImports(ctx, 10)
+	if err != nil {
+		return 0, err
+	}
+	for i := range loanImports {
+		if err := svc.processImport(ctx, &loanImports[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(loanImports), nil
+}
+
+func (svc *loanImportServiceImpl) RetryImport(ctx context.Context, id uint) (*model.LoanImport, error) {
+	loanImport, err := svc.repo.FindImportByID(ctx, id, model.LoanImportRowStatusFailed)
+	if err != nil {
+		return nil, err
+	}
+	if loanImport.Status != model.LoanImportStatusCompleted {
+		return nil, fmt.Errorf("Loan import %d is still %s.", loanImport.ID, loanImport.Status)
+	}
+	reset, err := svc.repo.ResetFailedRows(ctx, loanImport.ID)
+	if err != nil {
+		return nil, err
+	}
+	if reset == 0 {
+		return nil, fmt.Errorf("Loan import %d has no failed rows.", loanImport.ID)
+	}
+	loanImport.Rows = nil
+	loanImport.Status = model.LoanImportStatusPending
+	loanImport.FinishedAt = nil
+	if err := svc.updateCounts(ctx, loanImport); err != nil {
+		return nil, err
+	}
+	return loanImport, nil
+}
+
+func (svc *loanImportServiceImpl) GetImport(ctx context.Context, id uint, status string) (*model.LoanImport, error) {
+	return svc.repo.FindImportByID(ctx, id, status)
+}
+
+func (svc *loanImportServiceImpl) processImport(ctx context.Context, loanImport *model.LoanImport) error {
+	now := time.Now()
+	loanImport.Status = model.LoanImportStatusRunning
+	if loanImport.StartedAt == nil {
+		loanImport.StartedAt = &now
+	}
+	if err := svc.repo.UpdateImport(ctx, loanImport); err != nil {
+		return err
+	}
+	for {
+		rows, err := svc.repo.FindPendingRows(ctx, loanImport.ID, loanImportChunkSize)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			break
+		}
+		for i := range rows {
+			row := &rows[i]
+			err := svc.repo.WithDB().Transaction(func(trx *gorm.DB) error {
+				if err := svc.importRow(ctx, trx, row); err != nil {
+					return err
+				}
+				return svc.repo.WithTransaction(trx).UpdateRow(ctx, row)
+			})
+			if err != nil {
+				row.Status = model.LoanImportRowStatusFailed
+				row.Reason = err.Error()
+				row.BillingID = nil
+				if err := svc.repo.UpdateRow(ctx, row); err != nil {
+					return err
+				}
+			}
+		}
+		if err := svc.updateCounts(ctx, loanImport); err != nil {
+			return err
+		}
+	}
+	finishedAt := time.Now()
+	loanImport.Status = model.LoanImportStatusCompleted
+	loanImport.FinishedAt = &finishedAt
+	return svc.updateCounts(ctx, loanImport)
+}
+
+func (svc *loanImportServiceImpl) importRow(ctx context.Context, trx *gorm.DB, row *model.LoanImportRow) error {
+	existing, err := svc.billingRepo.WithTransaction(trx).FindByLoanID(ctx, row.LoanID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil {
+		row.Status = model.LoanImportRowStatusSkipped
+		row.Reason = fmt.Sprintf("Loan %d is already billing %d.", row.LoanID, existing.ID)
+		row.BillingID = &existing.ID
+		return nil
+	}
+	var req dto.ImportBillingRequest
+	if err := json.Unmarshal(row.Payload, &req); err != nil {
+		row.Status = model.LoanImportRowStatusFailed
+		row.Reason = "Invalid loan row."
+		return nil
+	}
+	billing, err := svc.billingSvc.WithTransaction(trx).ImportBilling(ctx, req)
+	if err != nil {
+		row.Status = model.LoanImportRowStatusFailed
+		row.Reason = err.Error()
+		return nil
+	}
+	row.Status = model.LoanImportRowStatusImported
+	row.Reason = ""
+	row.BillingID = &billing.ID
+	return nil
+}
+
+func (svc *loanImportServiceImpl) updateCounts(ctx context.Context, loanImport *model.LoanImport) error {
+	counts, err := svc.repo.CountRowsByStatus(ctx, loanImport.ID)
+	if err != nil {
+		return err
+	}
+	loanImport.ImportedRows = counts[model.LoanImportRowStatusImported]
+	loanImport.SkippedRows = counts[model.LoanImportRowStatusSkipped]
+	loanImport.FailedRows = counts[model.LoanImportRowStatusFailed]
+	loanImport.PendingRows = counts[model.LoanImportRowStatusPending]
+	return svc.repo.UpdateImport(ctx, loanImport)
+}
+
+func parseLoanImportRows(data []byte) ([]model.LoanImportRow, error) {
+	var payloads []json.RawMessage
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &payloads); err != nil {
+			return nil, fmt.Errorf("Invalid loan import JSON array.")
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+		for scanner.Scan() {
+			if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+				payloads = append(payloads, append(json.RawMessage(nil), line...))
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("Invalid loan import JSON lines: %s.", err.Error())
+		}
+	}
+
+	rows := make([]model.LoanImportRow, len(payloads))
+	for i, payload := range payloads {
+		rows[i] = model.LoanImportRow{RowNo: i + 1, Status: model.LoanImportRowStatusPending, Payload: payload}
+		var key struct {
+			LoanID uint `json:"loanId"`
+		}
+		if err := json.Unmarshal(payload, &key); err != nil || key.LoanID == 0 {
+			rows[i].Status = model.LoanImportRowStatusFailed
+			rows[i].Reason = "Row is not a JSON object with a loanId."
+			rows[i].Payload, _ = json.Marshal(string(payload))
+			continue
+		}
+		rows[i].LoanID = key.LoanID
+	}
+	return rows, nil
+}
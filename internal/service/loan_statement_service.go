@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/loanstatement"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/notification"
+	"github.com/doddeeph/billing-engine/internal/repository"
+)
+
+type LoanStatementService interface {
+	RenderStatement(ctx context.Context, billingID uint, query dto.LoanStatementQuery) (*dto.LoanStatementFile, error)
+}
+
+type loanStatementServiceImpl struct {
+	billingRepo  repository.BillingRepository
+	recoveryRepo repository.RecoveryRepository
+	renderer     *loanstatement.Renderer
+	customerCfg  *config.CustomerConfig
+}
+
+func NewLoanStatementService(billingRepo repository.BillingRepository, recoveryRepo repository.RecoveryRepository, renderer *loanstatement.Renderer, customerCfg *config.CustomerConfig) LoanStatementService {
+	return &loanStatementServiceImpl{billingRepo: billingRepo, recoveryRepo: recoveryRepo, renderer: renderer, customerCfg: customerCfg}
+}
+
+func (svc *loanStatementServiceImpl) RenderStatement(ctx context.Context, billingID uint, query dto.LoanStatementQuery) (*dto.LoanStatementFile, error) {
+	format, err := loanstatement.ParseFormat(query.Format)
+	if err != nil {
+		return nil, err
+	}
+	billing, err := svc.billingRepo.FindByID(ctx, billingID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	loc := customerLocation(billing.Customer, svc.customerCfg)
+	from, to, err := loanStatementPeriod(query, loanBookedAt(billing).In(loc), now.In(loc))
+	if err != nil {
+		return nil, err
+	}
+	recoveries, err := svc.recoveryRepo.FindByBillingID(ctx, billing.ID)
+	if err != nil {
+		return nil, err
+	}
+	statement := buildLoanStatement(billing, recoveries, from, to, now)
+	content, err := svc.renderer.Render(format, statement)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.LoanStatementFile{
+		FileName:    statement.FileName(format),
+		ContentType: loanstatement.ContentTypes[format],
+		Content:     content,
+	}, nil
+}
+
+func loanStatementPeriod(query dto.LoanStatementQuery, bookedAt, now time.Time) (time.Time, time.Time, error) {
+	loc := now.Location()
+	from := time.Date(bookedAt.Year(), bookedAt.Month(), bookedAt.Day(), 0, 0, 0, 0, loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	var err error
+	if query.From != "" {
+		if from, err = time.ParseInLocation(time.DateOnly, query.From, loc); err != nil {
+			return from, to, fmt.Errorf("Invalid from date %s.", query.From)
+		}
+	}
+	if query.To != "" {
+		if to, err = time.ParseInLocation(time.DateOnly, query.To, loc); err != nil {
+			return from, to, fmt.Errorf("Invalid to date %s.", query.To)
+		}
+	}
+	if from.After(to) {
+		return from, to, fmt.Errorf("Statement from date must not be after to date.")
+	}
+	return from, to, nil
+}
+
+func loanBookedAt(billing *model.Billing) time.Time {
+	bookedAt := billing.CreatedAt
+	for _, p := range billing.Payments {
+		if p.StartDate.Before(bookedAt) {
+			bookedAt = p.StartDate
+		}
+	}
+	return bookedAt
+}
+
+func loanStatementEntries(billing *model.Billing, recoveries []model.Recovery) []loanstatement.Entry {
+	repayable := 0
+	for _, p := range billing.Payments {
+		repayable += p.Amount
+	}
+	entries := []loanstatement.Entry{{
+		Date:        loanBookedAt(billing),
+		Type:        loanstatement.EntryLoan,
+		Description: fmt.Sprintf("Loan %d, %d weekly installments", billing.LoanID, billing.LoanWeeks),
+		Debit:       repayable,
+	}}
+	for _, p := range billing.Payments {
+		if p.LateFee > 0 {
+			entries = append(entries, loanstatement.Entry{
+				Date:        p.DueDate,
+				Type:        loanstatement.EntryLateFee,
+				Week:        p.Week,
+				Description: fmt.Sprintf("Late fee week %d", p.Week),
+				Debit:       p.LateFee,
+			})
+		}
+		if p.Paid {
+			paidAt := p.DueDate
+			if p.PaidDate != nil {
+				paidAt = *p.PaidDate
+			}
+			entries = append(entries, loanstatement.Entry{
+				Date:        paidAt,
+				Type:        loanstatement.EntryPayment,
+				Week:        p.Week,
+				Description: fmt.Sprintf("Payment week %d", p.Week),
+				Credit:      p.Amount + p.LateFee,
+			})
+		}
+	}
+	if billing.WrittenOffAt != nil && billing.WrittenOffAmount > 0 {
+		description := fmt.Sprintf("Write-off of %s", notification.FormatRupiah(billing.WrittenOffAmount))
+		if billing.WriteOffReason != "" {
+			description += ": " + billing.WriteOffReason
+		}
+		entries = append(entries, loanstatement.Entry{
+			Date:        *billing.WrittenOffAt,
+			Type:        loanstatement.EntryWriteOff,
+			Description: description,
+		})
+	}
+	for _, r := range recoveries {
+		entries = append(entries, loanstatement.Entry{
+			Date:        r.RecoveredAt,
+			Type:        loanstatement.EntryRecovery,
+			Description: "Recovery payment",
+			Credit:      r.Amount,
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Date.Before(entries[j].Date) })
+	return entries
+}
+
+func buildLoanStatement(billing *model.Billing, recoveries []model.Recovery, from, to, now time.Time) *loanstatement.Statement {
+	loc := from.Location()
+	end := to.AddDate(0, 0, 1)
+	statement := &loanstatement.Statement{
+		BillingID:   billing.ID,
+		CustomerID:  billing.CustomerID,
+		LoanID:      billing.LoanID,
+		ProductCode: billing.ProductCode,
+		Status:      billing.Status,
+		From:        from,
+		To:          to,
+		GeneratedAt: now.In(loc),
+	}
+	if billing.Customer != nil {
+		statement.CustomerName = billing.Customer.Name
+	}
+	balance := 0
+	for _, e := range loanStatementEntries(billing, recoveries) {
+		if !e.Date.Before(end) {
+			break
+		}
+		balance += e.Debit - e.Credit
+		if e.Date.Before(from) {
+			statement.OpeningBalance = balance
+			continue
+		}
+		e.Date = e.Date.In(loc)
+		e.Balance = balance
+		statement.TotalDebit += e.Debit
+		statement.TotalCredit += e.Credit
+		statement.Entries = append(statement.Entries, e)
+	}
+	statement.ClosingBalance = balance
+	asOf := end
+	if now.Before(asOf) {
+		asOf = now
+	}
+	for _, p := range billing.Payments {
+		if p.DueDate.Before(from) || !p.DueDate.Before(end) {
+			continue
+		}
+		installment := loanstatement.Installment{Week: p.Week, DueDate: p.DueDate.In(loc), Amount: p.Amount, LateFee: p.LateFee, Status: loanstatement.InstallmentDue}
+		switch {
+		case p.Paid && (p.PaidDate == nil || p.PaidDate.Before(end)):
+			installment.Status = loanstatement.InstallmentPaid
+			if p.PaidDate != nil {
+				paidDate := p.PaidDate.In(loc)
+				installment.PaidDate = &paidDate
+			}
+		case p.DueDate.Before(asOf):
+			installment.Status = loanstatement.InstallmentOverdue
+		}
+		statement.Installments = append(statement.Installments, installment)
+	}
+	return statement
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/loanstatement"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func testStatementBilling() *model.Billing {
+	day := func(d, h int) time.Time { return time.Date(2025, 8, d, h, 0, 0, 0, time.UTC) }
+	paid := func(t time.Time) *time.Time { return &t }
+	billing := &model.Billing{
+		ID:         1,
+		CustomerID: 7,
+		LoanID:     1001,
+		LoanWeeks:  4,
+		Status:     model.BillingStatusActive,
+		Customer:   &model.Customer{Name: "Budi"},
+		Payments: []model.Payment{
+			{Week: 1, Amount: 100000, StartDate: day(1, 17), DueDate: day(8, 16), Paid: true, PaidDate: paid(day(7, 9))},
+			{Week: 2, Amount: 100000, StartDate: day(8, 17), DueDate: day(15, 16), Paid: true, PaidDate: paid(day(18, 9)), Overdue: true, LateFee: 5000},
+			{Week: 3, Amount: 100000, StartDate: day(15, 17), DueDate: day(22, 16), Overdue: true, LateFee: 5000},
+			{Week: 4, Amount: 100000, StartDate: day(22, 17), DueDate: day(29, 16)},
+		},
+	}
+	billing.CreatedAt = day(1, 10)
+	return billing
+}
+
+func TestBuildLoanStatement(t *testing.T) {
+	billing := testStatementBilling()
+	from := time.Date(2025, 8, 10, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 8, 25, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 8, 26, 12, 0, 0, 0, time.UTC)
+
+	s := buildLoanStatement(billing, nil, from, to, now)
+	assert.Equal(t, "Budi", s.CustomerName)
+	assert.Equal(t, 300000, s.OpeningBalance)
+	assert.Equal(t, 10000, s.TotalDebit)
+	assert.Equal(t, 105000, s.TotalCredit)
+	assert.Equal(t, 205000, s.ClosingBalance)
+	assert.Len(t, s.Entries, 3)
+	assert.Equal(t, loanstatement.EntryLateFee, s.Entries[0].Type)
+	assert.Equal(t, 305000, s.Entries[0].Balance)
+	assert.Equal(t, loanstatement.EntryPayment, s.Entries[1].Type)
+	assert.Equal(t, 200000, s.Entries[1].Balance)
+
+	assert.Len(t, s.Installments, 2)
+	assert.Equal(t, loanstatement.InstallmentPaid, s.Installments[0].Status)
+	assert.NotNil(t, s.Installments[0].PaidDate)
+	assert.Equal(t, loanstatement.InstallmentOverdue, s.Installments[1].Status)
+
+	all := buildLoanStatement(billing, nil, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC), now)
+	assert.Equal(t, 0, all.OpeningBalance)
+	assert.Equal(t, loanstatement.EntryLoan, all.Entries[0].Type)
+	assert.Equal(t, 400000, all.Entries[0].Debit)
+	assert.Equal(t, 205000, all.ClosingBalance)
+	assert.Equal(t, loanstatement.InstallmentDue, all.Installments[3].Status)
+}
+
+func TestBuildLoanStatement_WriteOff(t *testing.T) {
+	billing := testStatementBilling()
+	writtenOffAt := time.Date(2025, 8, 27, 8, 0, 0, 0, time.UTC)
+	billing.Status = model.BillingStatusWrittenOff
+	billing.WrittenOffAt = &writtenOffAt
+	billing.WrittenOffAmount = 205000
+	billing.WriteOffReason = "Uncollectible"
+
+	recoveries := []model.Recovery{
+		{Amount: 50000, RecoveredAt: time.Date(2025, 8, 29, 10, 0, 0, 0, time.UTC)},
+		{Amount: 30000, RecoveredAt: time.Date(2025, 9, 2, 10, 0, 0, 0, time.UTC)},
+	}
+
+	s := buildLoanStatement(billing, recoveries, time.Date(2025, 8, 26, 0, 0, 0, 0, time.UTC), time.Date(2025, 8, 31, 0, 0, 0, 0, time.UTC), writtenOffAt)
+	assert.Equal(t, 205000, s.OpeningBalance)
+	assert.Len(t, s.Entries, 2)
+	assert.Equal(t, "Write-off of Rp205.000: Uncollectible", s.Entries[0].Description)
+	assert.Equal(t, 205000, s.Entries[0].Balance)
+	assert.Equal(t, loanstatement.EntryRecovery, s.Entries[1].Type)
+	assert.Equal(t, 50000, s.Entries[1].Credit)
+	assert.Equal(t, 50000, s.TotalCredit)
+	assert.Equal(t, 155000, s.ClosingBalance)
+
+	s = buildLoanStatement(billing, recoveries, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC), writtenOffAt)
+	assert.Equal(t, 155000, s.OpeningBalance)
+	assert.Equal(t, 125000, s.ClosingBalance)
+}
+
+func TestLoanStatementPeriod(t *testing.T) {
+	bookedAt := time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 9, 3, 12, 0, 0, 0, time.UTC)
+
+	from, to, err := loanStatementPeriod(dto.LoanStatementQuery{}, bookedAt, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 9, 3, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = loanStatementPeriod(dto.LoanStatementQuery{From: "2025-08-10", To: "2025-08-20"}, bookedAt, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 8, 10, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 8, 20, 0, 0, 0, 0, time.UTC), to)
+
+	_, _, err = loanStatementPeriod(dto.LoanStatementQuery{From: "10/08/2025"}, bookedAt, now)
+	assert.EqualError(t, err, "Invalid from date 10/08/2025.")
+	_, _, err = loanStatementPeriod(dto.LoanStatementQuery{From: "2025-08-20", To: "2025-08-10"}, bookedAt, now)
+	assert.EqualError(t, err, "Statement from date must not be after to date.")
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/doddeeph/billing-engine/pkg/events"
+	"gorm.io/gorm"
+)
+
+type OutboxService interface {
+	WithTransaction(tx *gorm.DB) OutboxService
+	Record(ctx context.Context, billingID uint, eventType string, payload any) error
+}
+
+type outboxServiceImpl struct {
+	repo repository.OutboxRepository
+}
+
+func NewOutboxService(repo repository.OutboxRepository) OutboxService {
+	return &outboxServiceImpl{repo: repo}
+}
+
+func (svc *outboxServiceImpl) WithTransaction(tx *gorm.DB) OutboxService {
+	return &outboxServiceImpl{repo: svc.repo.WithTransaction(tx)}
+}
+
+func (svc *outboxServiceImpl) Record(ctx context.Context, billingID uint, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	if err := events.ValidateData(eventType, data); err != nil {
+		return err
+	}
+	return svc.repo.Create(ctx, &model.OutboxEvent{
+		BillingID:  billingID,
+		EventType:  eventType,
+		Payload:    data,
+		Status:     model.OutboxStatusPending,
+		OccurredAt: time.Now(),
+	})
+}
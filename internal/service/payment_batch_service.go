@@ -0,0 +1,332 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"gorm.io/gorm"
+)
+
+var errDryRunRollback = errors.New("dry run rollback")
+
+type PaymentBatchService interface {
+	SubmitBatch(ctx context.Context, format string, data []byte, dryRun, async bool) (*model.PaymentBatch, error)
+	ProcessPending(ctx context.Context) (int, error)
+	GetBatch(ctx context.Context, id uint, status string) (*model.PaymentBatch, error)
+}
+
+type paymentBatchServiceImpl struct {
+	repo       repository.PaymentBatchRepository
+	paymentSvc PaymentService
+	cfg        *config.PaymentBatchConfig
+}
+
+func NewPaymentBatchService(repo repository.PaymentBatchRepository, paymentSvc PaymentService, cfg *config.PaymentBatchConfig) PaymentBatchService {
+	return &paymentBatchServiceImpl{repo: repo, paymentSvc: paymentSvc, cfg: cfg}
+}
+
+func (svc *paymentBatchServiceImpl) SubmitBatch(ctx context.Context, format string, data []byte, dryRun, async bool) (*model.PaymentBatch, error) {
+	var rows []model.PaymentBatchRow
+	var err error
+	switch strings.ToUpper(format) {
+	case model.PaymentBatchFormatCSV:
+		rows, err = parsePaymentBatchCSV(data)
+	case model.PaymentBatchFormatJSONL:
+		rows, err = parsePaymentBatchJSONL(data)
+	default:
+		return nil, fmt.Errorf("Unsupported payment batch format %q.", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("Payment batch has no rows.")
+	}
+
+	batch := &model.PaymentBatch{
+		Format:    strings.ToUpper(format),
+		DryRun:    dryRun,
+		Status:    model.PaymentBatchStatusPending,
+		TotalRows: len(rows),
+	}
+	if err := svc.repo.CreateBatch(ctx, batch, rows); err != nil {
+		return nil, err
+	}
+	if async || len(rows) > svc.cfg.SyncLimit {
+		return batch, nil
+	}
+	if _, err := svc.processBatch(ctx, batch); err != nil {
+		return nil, err
+	}
+	return svc.repo.FindBatchByID(ctx, batch.ID, "")
+}
+
+func (svc *paymentBatchServiceImpl) ProcessPending(ctx context.Context) (int, error) {
+	batches, err := svc.repo.FindPendingBatches(ctx, time.Now(), 10)
+	if err != nil {
+		return 0, err
+	}
+	processed := 0
+	for i := range batches {
+		claimed, err := svc.processBatch(ctx, &batches[i])
+		if err != nil {
+			return processed, err
+		}
+		if claimed {
+			processed++
+		}
+	}
+	return processed, nil
+}
+
+func (svc *paymentBatchServiceImpl) GetBatch(ctx context.Context, id uint, status string) (*model.PaymentBatch, error) {
+	return svc.repo.FindBatchByID(ctx, id, status)
+}
+
+func (svc *paymentBatchServiceImpl) processBatch(ctx context.Context, batch *model.PaymentBatch) (bool, error) {
+	now := time.Now()
+	claimed, err := svc.repo.ClaimBatch(ctx, batch, now, now.Add(svc.cfg.LeaseDuration))
+	if err != nil || !claimed {
+		return false, err
+	}
+	return true, svc.runBatch(ctx, batch)
+}
+
+func (svc *paymentBatchServiceImpl) renewLease(ctx context.Context, batch *model.PaymentBatch) error {
+	now := time.Now()
+	if batch.LeaseExpiresAt != nil && batch.LeaseExpiresAt.Sub(now) > svc.cfg.LeaseDuration/2 {
+		return nil
+	}
+	leaseExpiresAt := now.Add(svc.cfg.LeaseDuration)
+	batch.LeaseExpiresAt = &leaseExpiresAt
+	return svc.repo.RenewLease(ctx, batch.ID, leaseExpiresAt)
+}
+
+func (svc *paymentBatchServiceImpl) runBatch(ctx context.Context, batch *model.PaymentBatch) error {
+	rows, err := svc.repo.FindPendingRows(ctx, batch.ID)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	if batch.DryRun {
+		err := svc.repo.WithDB().Transaction(func(trx *gorm.DB) error {
+			for i := range rows {
+				if err := svc.renewLease(ctx, batch); err != nil {
+					return err
+				}
+				if err := svc.applyRow(ctx, trx, &rows[i], seen, true); err != nil {
+					return err
+				}
+			}
+			return errDryRunRollback
+		})
+		if !errors.Is(err, errDryRunRollback) {
+			return err
+		}
+		for i := range rows {
+			if err := svc.repo.UpdateRow(ctx, &rows[i]); err != nil {
+				return err
+			}
+		}
+	} else {
+		for i := range rows {
+			if err := svc.renewLease(ctx, batch); err != nil {
+				return err
+			}
+			row := &rows[i]
+			err := svc.repo.WithDB().Transaction(func(trx *gorm.DB) error {
+				if err := svc.applyRow(ctx, trx, row, seen, false); err != nil {
+					return err
+				}
+				return svc.repo.WithTransaction(trx).UpdateRow(ctx, row)
+			})
+			if err != nil {
+				row.Status = model.PaymentBatchRowStatusRejected
+				row.Reason = err.Error()
+				row.PaymentID = nil
+				row.RecoveryID = nil
+				if err := svc.repo.UpdateRow(ctx, row); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	counts, err := svc.repo.CountRowsByStatus(ctx, batch.ID)
+	if err != nil {
+		return err
+	}
+	finishedAt := time.Now()
+	batch.Status = model.PaymentBatchStatusCompleted
+	batch.AppliedRows = counts[model.PaymentBatchRowStatusApplied]
+	batch.ValidRows = counts[model.PaymentBatchRowStatusValid]
+	batch.DuplicateRows = counts[model.PaymentBatchRowStatusDuplicate]
+	batch.RejectedRows = counts[model.PaymentBatchRowStatusRejected]
+	batch.FinishedAt = &finishedAt
+	batch.LeaseExpiresAt = nil
+	return svc.repo.UpdateBatch(ctx, batch)
+}
+
+func (svc *paymentBatchServiceImpl) applyRow(ctx context.Context, trx *gorm.DB, row *model.PaymentBatchRow, seen map[string]bool, dryRun bool) error {
+	if seen[row.Reference] {
+		row.Status = model.PaymentBatchRowStatusDuplicate
+		row.Reason = "Reference appears earlier in the batch."
+		return nil
+	}
+	applied, err := svc.repo.WithTransaction(trx).ExistsAppliedReference(ctx, row.Reference)
+	if err != nil {
+		return err
+	}
+	if applied {
+		row.Status = model.PaymentBatchRowStatusDuplicate
+		row.Reason = "Reference has already been applied."
+		return nil
+	}
+	seen[row.Reference] = true
+
+	trxPaymentSvc := svc.paymentSvc.WithTransaction(trx)
+	var paymentResp *dto.PaymentResponse
+	if row.Week == 0 {
+		paymentResp, err = trxPaymentSvc.ApplyNextInstallment(ctx, row.BillingID, row.Amount, time.Now())
+	} else {
+		paymentResp, err = trxPaymentSvc.MakePayment(ctx, row.BillingID, dto.PaymentRequest{Week: row.Week, Amount: row.Amount})
+	}
+	if err != nil {
+		row.Status = model.PaymentBatchRowStatusRejected
+		row.Reason = err.Error()
+		return nil
+	}
+	row.Status = model.PaymentBatchRowStatusApplied
+	if dryRun {
+		row.Status = model.PaymentBatchRowStatusValid
+	}
+	if paymentResp.Payment != nil {
+		row.Week = paymentResp.Payment.Week
+		row.PaymentID = &paymentResp.Payment.ID
+	}
+	if paymentResp.Recovery != nil {
+		row.RecoveryID = &paymentResp.Recovery.ID
+	}
+	if dryRun {
+		row.PaymentID = nil
+		row.RecoveryID = nil
+	}
+	return nil
+}
+
+func newPaymentBatchRow(rowNo int, req dto.PaymentBatchRowRequest, parseErr error) model.PaymentBatchRow {
+	row := model.PaymentBatchRow{
+		RowNo:     rowNo,
+		BillingID: req.BillingID,
+		Week:      req.Week,
+		Amount:    req.Amount,
+		Reference: strings.TrimSpace(req.Reference),
+		Status:    model.PaymentBatchRowStatusPending,
+	}
+	switch {
+	case parseErr != nil:
+		row.Reason = parseErr.Error()
+	case row.BillingID == 0:
+		row.Reason = "billingId is required."
+	case row.Amount <= 0:
+		row.Reason = "amount must be positive."
+	case row.Week < 0:
+		row.Reason = "week must not be negative."
+	case row.Reference == "":
+		row.Reason = "reference is required."
+	}
+	if row.Reason != "" {
+		row.Status = model.PaymentBatchRowStatusRejected
+	}
+	return row
+}
+
+func parsePaymentBatchCSV(data []byte) ([]model.PaymentBatchRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid CSV payment batch: %s.", err.Error())
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	index := make(map[string]int)
+	for i, header := range records[0] {
+		index[strings.ReplaceAll(strings.ToLower(strings.TrimSpace(header)), "_", "")] = i
+	}
+	for _, column := range []string{"billingid", "amount", "reference"} {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("CSV payment batch requires billing_id, amount and reference columns.")
+		}
+	}
+
+	var rows []model.PaymentBatchRow
+	for n, record := range records[1:] {
+		if strings.Join(record, "") == "" {
+			continue
+		}
+		field := func(column string) string {
+			if i, ok := index[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		var req dto.PaymentBatchRowRequest
+		var parseErr error
+		req.Reference = field("reference")
+		if billingID, err := strconv.ParseUint(field("billingid"), 10, 64); err == nil {
+			req.BillingID = uint(billingID)
+		} else {
+			parseErr = fmt.Errorf("Invalid billing_id %q.", field("billingid"))
+		}
+		if req.Amount, err = strconv.Atoi(field("amount")); err != nil && parseErr == nil {
+			parseErr = fmt.Errorf("Invalid amount %q.", field("amount"))
+		}
+		if week := field("week"); week != "" {
+			if req.Week, err = strconv.Atoi(week); err != nil && parseErr == nil {
+				parseErr = fmt.Errorf("Invalid week %q.", week)
+			}
+		}
+		rows = append(rows, newPaymentBatchRow(n+1, req, parseErr))
+	}
+	return rows, nil
+}
+
+func parsePaymentBatchJSONL(data []byte) ([]model.PaymentBatchRow, error) {
+	var rows []model.PaymentBatchRow
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	rowNo := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		rowNo++
+		var req dto.PaymentBatchRowRequest
+		var parseErr error
+		if err := json.Unmarshal(line, &req); err != nil {
+			parseErr = fmt.Errorf("Invalid JSON row.")
+		}
+		rows = append(rows, newPaymentBatchRow(rowNo, req, parseErr))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Invalid JSON lines payment batch: %s.", err.Error())
+	}
+	return rows, nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePaymentBatchCSV(t *testing.T) {
+	rows, err := parsePaymentBatchCSV([]byte("Billing_ID,Week,Amount,Reference\n" +
+		"1,1,110000,agent-1\n" +
+		"1,,110000,agent-2\n" +
+		",,,\n" +
+		"x,1,110000,agent-3\n" +
+		"1,1,0,agent-4\n" +
+		"1,1,110000,\n"))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 5)
+	assert.Equal(t, model.PaymentBatchRowStatusPending, rows[0].Status)
+	assert.Equal(t, uint(1), rows[0].BillingID)
+	assert.Equal(t, 1, rows[0].Week)
+	assert.Equal(t, 0, rows[1].Week)
+	assert.Equal(t, model.PaymentBatchRowStatusRejected, rows[2].Status)
+	assert.Equal(t, `Invalid billing_id "x".`, rows[2].Reason)
+	assert.Equal(t, "amount must be positive.", rows[3].Reason)
+	assert.Equal(t, "reference is required.", rows[4].Reason)
+
+	_, err = parsePaymentBatchCSV([]byte("billing_id,amount\n1,110000\n"))
+	assert.Error(t, err)
+}
+
+func TestParsePaymentBatchJSONL(t *testing.T) {
+	rows, err := parsePaymentBatchJSONL([]byte(`{"billingId":1,"week":2,"amount":110000,"reference":"agent-1"}` + "\n\n" +
+		`{"billingId":1,"amount":` + "\n"))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, model.PaymentBatchRowStatusPending, rows[0].Status)
+	assert.Equal(t, 2, rows[0].Week)
+	assert.Equal(t, 2, rows[1].RowNo)
+	assert.Equal(t, model.PaymentBatchRowStatusRejected, rows[1].Status)
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"gorm.io/gorm"
+)
+
+type PaymentPromiseService interface {
+	WithTransaction(tx *gorm.DB) PaymentPromiseService
+	CreatePromise(ctx context.Context, billingID uint, req dto.PaymentPromiseRequest) (*model.PaymentPromise, error)
+	GetPromises(ctx context.Context, billingID uint) ([]model.PaymentPromise, error)
+	ApplyPayment(ctx context.Context, billingID uint, amount int, paidAt time.Time) (*model.PaymentPromise, error)
+	BreakExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+type paymentPromiseServiceImpl struct {
+	repo        repository.PaymentPromiseRepository
+	billingRepo repository.BillingRepository
+	cfg         *config.PaymentPromiseConfig
+}
+
+func NewPaymentPromiseService(repo repository.PaymentPromiseRepository, billingRepo repository.BillingRepository, cfg *config.PaymentPromiseConfig) PaymentPromiseService {
+	return &paymentPromiseServiceImpl{repo: repo, billingRepo: billingRepo, cfg: cfg}
+}
+
+func (svc *paymentPromiseServiceImpl) WithTransaction(tx *gorm.DB) PaymentPromiseService {
+	return &paymentPromiseServiceImpl{
+		repo:        svc.repo.WithTransaction(tx),
+		billingRepo: svc.billingRepo.WithTransaction(tx),
+		cfg:         svc.cfg,
+	}
+}
+
+func validatePaymentPromiseRequest(req dto.PaymentPromiseRequest, now time.Time, maxDays int) (time.Time, error) {
+	if req.Amount <= 0 {
+		return time.Time{}, fmt.Errorf("Promise amount must be positive.")
+	}
+	if req.PromiseDate == "" {
+		return time.Time{}, fmt.Errorf("Promise date is required.")
+	}
+	promiseDate, err := time.ParseInLocation(time.DateOnly, req.PromiseDate, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid promise date %s.", req.PromiseDate)
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if promiseDate.Before(today) {
+		return time.Time{}, fmt.Errorf("Promise date cannot be in the past.")
+	}
+	if promiseDate.After(today.AddDate(0, 0, maxDays)) {
+		return time.Time{}, fmt.Errorf("Promise date must be within %d days.", maxDays)
+	}
+	return promiseDate, nil
+}
+
+func (svc *paymentPromiseServiceImpl) CreatePromise(ctx context.Context, billingID uint, req dto.PaymentPromiseRequest) (*model.PaymentPromise, error) {
+	now := time.Now()
+	promiseDate, err := validatePaymentPromiseRequest(req, now, svc.cfg.MaxDays)
+	if err != nil {
+		return nil, err
+	}
+	var promise *model.PaymentPromise
+	err = svc.repo.WithDB().Transaction(func(trx *gorm.DB) error {
+		trxRepo := svc.repo.WithTransaction(trx)
+		billing, err := svc.billingRepo.WithTransaction(trx).FindByID(ctx, billingID)
+		if err != nil {
+			return err
+		}
+		if billing.Status != model.BillingStatusActive {
+			return fmt.Errorf("Billing %d is not active.", billing.ID)
+		}
+		if req.Amount > billing.Outstanding {
+			return fmt.Errorf("Promise amount exceeds outstanding %d.", billing.Outstanding)
+		}
+		pending, err := trxRepo.FindPendingByBillingIDForUpdate(ctx, billing.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if pending != nil {
+			pending.Status = model.PaymentPromiseStatusCancelled
+			if !now.Before(pending.ExpiresAt) {
+				pending.Status = model.PaymentPromiseStatusBroken
+			}
+			pending.ResolvedAt = &now
+			if err := trxRepo.Update(ctx, pending); err != nil {
+				return err
+			}
+		}
+		promise = &model.PaymentPromise{
+			BillingID:   billing.ID,
+			Amount:      req.Amount,
+			PromiseDate: promiseDate,
+			ExpiresAt:   promiseDate.AddDate(0, 0, 1),
+			Status:      model.PaymentPromiseStatusPending,
+			Notes:       req.Notes,
+			PromisedAt:  now,
+		}
+		return trxRepo.Create(ctx, promise)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return promise, nil
+}
+
+func (svc *paymentPromiseServiceImpl) GetPromises(ctx context.Context, billingID uint) ([]model.PaymentPromise, error) {
+	if _, err := svc.billingRepo.FindByID(ctx, billingID); err != nil {
+		return nil, err
+	}
+	return svc.repo.FindByBillingID(ctx, billingID)
+}
+
+func (svc *paymentPromiseServiceImpl) ApplyPayment(ctx context.Context, billingID uint, amount int, paidAt time.Time) (*model.PaymentPromise, error) {
+	promise, err := svc.repo.FindPendingByBillingIDForUpdate(ctx, billingID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	applyPromisePayment(promise, amount, paidAt)
+	if err := svc.repo.Update(ctx, promise); err != nil {
+		return nil, err
+	}
+	return promise, nil
+}
+
+func applyPromisePayment(promise *model.PaymentPromise, amount int, paidAt time.Time) {
+	if !paidAt.Before(promise.ExpiresAt) {
+		promise.Status = model.PaymentPromiseStatusBroken
+		promise.ResolvedAt = &paidAt
+		return
+	}
+	promise.PaidAmount += amount
+	if promise.PaidAmount >= promise.Amount {
+		promise.Status = model.PaymentPromiseStatusFulfilled
+		promise.ResolvedAt = &paidAt
+	}
+}
+
+func (svc *paymentPromiseServiceImpl) BreakExpired(ctx context.Context, now time.Time) (int, error) {
+	broken, err := svc.repo.MarkExpiredBroken(ctx, now)
+	return int(broken), err
+}
+
+func hasPendingPromise(promises []model.PaymentPromise, at time.Time) bool {
+	for _, p := range promises {
+		if p.Status == model.PaymentPromiseStatusPending && !at.Before(p.PromisedAt) && at.Before(p.ExpiresAt) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePaymentPromiseRequest(t *testing.T) {
+	now := time.Date(2025, 9, 10, 15, 0, 0, 0, time.UTC)
+	promiseDate, err := validatePaymentPromiseRequest(dto.PaymentPromiseRequest{Amount: 110000, PromiseDate: "2025-09-10"}, now, 14)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC), promiseDate)
+
+	tests := []struct {
+		req dto.PaymentPromiseRequest
+		err string
+	}{
+		{dto.PaymentPromiseRequest{PromiseDate: "2025-09-12"}, "Promise amount must be positive."},
+		{dto.PaymentPromiseRequest{Amount: 110000}, "Promise date is required."},
+		{dto.PaymentPromiseRequest{Amount: 110000, PromiseDate: "12/09/2025"}, "Invalid promise date 12/09/2025."},
+		{dto.PaymentPromiseRequest{Amount: 110000, PromiseDate: "2025-09-09"}, "Promise date cannot be in the past."},
+		{dto.PaymentPromiseRequest{Amount: 110000, PromiseDate: "2025-09-25"}, "Promise date must be within 14 days."},
+	}
+	for _, tt := range tests {
+		_, err := validatePaymentPromiseRequest(tt.req, now, 14)
+		assert.EqualError(t, err, tt.err)
+	}
+}
+
+func TestApplyPromisePayment(t *testing.T) {
+	expiresAt := time.Date(2025, 9, 13, 0, 0, 0, 0, time.UTC)
+	promise := &model.PaymentPromise{Amount: 220000, ExpiresAt: expiresAt, Status: model.PaymentPromiseStatusPending}
+
+	applyPromisePayment(promise, 110000, expiresAt.Add(-48*time.Hour))
+	assert.Equal(t, model.PaymentPromiseStatusPending, promise.Status)
+	assert.Equal(t, 110000, promise.PaidAmount)
+	assert.Nil(t, promise.ResolvedAt)
+
+	applyPromisePayment(promise, 110000, expiresAt.Add(-time.Hour))
+	assert.Equal(t, model.PaymentPromiseStatusFulfilled, promise.Status)
+	assert.NotNil(t, promise.ResolvedAt)
+
+	late := &model.PaymentPromise{Amount: 110000, ExpiresAt: expiresAt, Status: model.PaymentPromiseStatusPending}
+	applyPromisePayment(late, 110000, expiresAt)
+	assert.Equal(t, model.PaymentPromiseStatusBroken, late.Status)
+	assert.Equal(t, 0, late.PaidAmount)
+}
+
+func TestHasPendingPromise(t *testing.T) {
+	promisedAt := time.Date(2025, 9, 10, 15, 0, 0, 0, time.UTC)
+	promises := []model.PaymentPromise{
+		{Status: model.PaymentPromiseStatusBroken, PromisedAt: promisedAt.AddDate(0, 0, -7), ExpiresAt: promisedAt.AddDate(0, 0, -1)},
+		{Status: model.PaymentPromiseStatusPending, PromisedAt: promisedAt, ExpiresAt: promisedAt.AddDate(0, 0, 3)},
+	}
+	assert.True(t, hasPendingPromise(promises, promisedAt.Add(time.Hour)))
+	assert.False(t, hasPendingPromise(promises, promisedAt.Add(-time.Hour)))
+	assert.False(t, hasPendingPromise(promises, promisedAt.AddDate(0, 0, 3)))
+	assert.False(t, hasPendingPromise(promises[:1], promisedAt.AddDate(0, 0, -3)))
+}
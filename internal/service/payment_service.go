@@ -2,72 +2,169 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
 	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/doddeeph/billing-engine/pkg/events"
 	"gorm.io/gorm"
 )
 
 type PaymentService interface {
+	WithTransaction(tx *gorm.DB) PaymentService
 	MakePayment(ctx context.Context, billingId uint, req dto.PaymentRequest) (*dto.PaymentResponse, error)
+	ApplyNextInstallment(ctx context.Context, billingId uint, amount int, paidAt time.Time) (*dto.PaymentResponse, error)
 }
 
 type paymentServiceImpl struct {
-	repo       repository.PaymentRepository
-	billingSvc BillingService
+	repo           repository.PaymentRepository
+	billingSvc     BillingService
+	writeOffSvc    WriteOffService
+	delinquencySvc DelinquencyService
+	promiseSvc     PaymentPromiseService
+	outboxSvc      OutboxService
 }
 
-func NewPaymentService(repo repository.PaymentRepository, billingSvc BillingService) PaymentService {
-	return &paymentServiceImpl{repo: repo, billingSvc: billingSvc}
+func NewPaymentService(repo repository.PaymentRepository, billingSvc BillingService, writeOffSvc WriteOffService, delinquencySvc DelinquencyService, promiseSvc PaymentPromiseService, outboxSvc OutboxService) PaymentService {
+	return &paymentServiceImpl{repo: repo, billingSvc: billingSvc, writeOffSvc: writeOffSvc, delinquencySvc: delinquencySvc, promiseSvc: promiseSvc, outboxSvc: outboxSvc}
+}
+
+func (svc *paymentServiceImpl) WithTransaction(tx *gorm.DB) PaymentService {
+	return &paymentServiceImpl{
+		repo:           svc.repo.WithTransaction(tx),
+		billingSvc:     svc.billingSvc.WithTransaction(tx),
+		writeOffSvc:    svc.writeOffSvc.WithTransaction(tx),
+		delinquencySvc: svc.delinquencySvc.WithTransaction(tx),
+		promiseSvc:     svc.promiseSvc.WithTransaction(tx),
+		outboxSvc:      svc.outboxSvc.WithTransaction(tx),
+	}
 }
 
 func (svc *paymentServiceImpl) MakePayment(ctx context.Context, billingId uint, req dto.PaymentRequest) (*dto.PaymentResponse, error) {
+	return svc.applyPayment(ctx, billingId, req.Amount, time.Now(), func(trxPaymentRepo repository.PaymentRepository, billing *model.Billing) (*model.Payment, error) {
+		if req.Week < 0 || req.Week > billing.LoanWeeks {
+			return nil, fmt.Errorf("Payment is outside %d loan week.", billing.LoanWeeks)
+		}
+		return trxPaymentRepo.FindByBillingIdAndWeek(ctx, billing.ID, req.Week)
+	})
+}
+
+func (svc *paymentServiceImpl) ApplyNextInstallment(ctx context.Context, billingId uint, amount int, paidAt time.Time) (*dto.PaymentResponse, error) {
+	return svc.applyPayment(ctx, billingId, amount, paidAt, func(trxPaymentRepo repository.PaymentRepository, billing *model.Billing) (*model.Payment, error) {
+		payment, err := trxPaymentRepo.FindFirstUnpaid(ctx, billing.ID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("Billing %d has no unpaid installment.", billing.ID)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if due := payment.Amount + payment.LateFee; amount > due {
+			return nil, fmt.Errorf("Payment of %d exceeds the %d due for week %d.", amount, due, payment.Week)
+		}
+		return payment, nil
+	})
+}
+
+func (svc *paymentServiceImpl) applyPayment(ctx context.Context, billingId uint, amount int, paidAt time.Time, findPayment func(repository.PaymentRepository, *model.Billing) (*model.Payment, error)) (*dto.PaymentResponse, error) {
 	var paymentResp *dto.PaymentResponse
 	err := svc.repo.WithDB().Transaction(func(trx *gorm.DB) error {
 		trxBillingSvc := svc.billingSvc.WithTransaction(trx)
 		trxPaymentRepo := svc.repo.WithTransaction(trx)
+		trxOutboxSvc := svc.outboxSvc.WithTransaction(trx)
 
-		billing, err := trxBillingSvc.GetBilling(ctx, billingId)
+		billing, err := trxBillingSvc.GetBillingForUpdate(ctx, billingId)
 		if err != nil {
 			return err
 		}
 
-		if req.Week < 0 || req.Week > billing.LoanWeeks {
-			return fmt.Errorf("Payment is outside %d loan week.", billing.LoanWeeks)
+		if billing.Status == model.BillingStatusWrittenOff {
+			recovery, err := svc.writeOffSvc.WithTransaction(trx).RecordRecovery(ctx, billing, amount)
+			if err != nil {
+				return err
+			}
+			paymentResp = &dto.PaymentResponse{
+				CustomerID:  billing.CustomerID,
+				LoanID:      billing.LoanID,
+				Outstanding: billing.Outstanding,
+				Status:      billing.Status,
+				Recovery:    recovery,
+			}
+			return nil
+		}
+		if billing.Status == model.BillingStatusClosed {
+			return fmt.Errorf("Billing %d has been closed.", billing.ID)
 		}
 
-		payment, err := trxPaymentRepo.FindByBillingIdAndWeek(ctx, billing.ID, req.Week)
+		payment, err := findPayment(trxPaymentRepo, billing)
 		if err != nil {
 			return err
 		}
 		if payment.Paid {
-			return fmt.Errorf("Week %d has been paid.", req.Week)
+			return fmt.Errorf("Week %d has been paid.", payment.Week)
 		}
-		if req.Amount < payment.Amount {
-			return fmt.Errorf("Insufficient loan amount paid for week %d", req.Week)
+		if amount < payment.Amount+payment.LateFee {
+			return fmt.Errorf("Insufficient loan amount paid for week %d", payment.Week)
 		}
 
 		payment.Paid = true
-		now := time.Now()
-		payment.PaidDate = &now
+		payment.PaidDate = &paidAt
 		updatedPayment, err := trxPaymentRepo.UpdatePaid(ctx, payment)
 		if err != nil {
 			return err
 		}
 
-		updatedOutstanding := billing.Outstanding - payment.Amount
+		updatedOutstanding := billing.Outstanding - payment.Amount - payment.LateFee
 		err = trxBillingSvc.UpdateOutstanding(ctx, billing.ID, updatedOutstanding)
 		if err != nil {
 			return err
 		}
+		err = trxOutboxSvc.Record(ctx, billing.ID, events.TypePaymentApplied, events.PaymentApplied{
+			BillingID:   billing.ID,
+			CustomerID:  billing.CustomerID,
+			LoanID:      billing.LoanID,
+			Week:        payment.Week,
+			Amount:      payment.Amount,
+			LateFee:     payment.LateFee,
+			Outstanding: updatedOutstanding,
+			PaidAt:      paidAt,
+		})
+		if err != nil {
+			return err
+		}
+		status := billing.Status
+		if updatedOutstanding <= 0 {
+			status = model.BillingStatusClosed
+			if err := trxBillingSvc.UpdateStatus(ctx, billing.ID, status); err != nil {
+				return err
+			}
+			err = trxOutboxSvc.Record(ctx, billing.ID, events.TypeBillingClosed, events.BillingClosed{
+				BillingID:  billing.ID,
+				CustomerID: billing.CustomerID,
+				LoanID:     billing.LoanID,
+				ClosedAt:   paidAt,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		promise, err := svc.promiseSvc.WithTransaction(trx).ApplyPayment(ctx, billing.ID, payment.Amount+payment.LateFee, paidAt)
+		if err != nil {
+			return err
+		}
+		if _, err := svc.delinquencySvc.WithTransaction(trx).EvaluateBilling(ctx, billing.ID, model.DelinquencySourcePayment); err != nil {
+			return err
+		}
 
 		paymentResp = &dto.PaymentResponse{
 			CustomerID:  billing.CustomerID,
 			LoanID:      billing.LoanID,
 			Outstanding: updatedOutstanding,
-			Payment:     *updatedPayment,
+			Status:      status,
+			Payment:     updatedPayment,
+			Promise:     promise,
 		}
 		return nil
 	})
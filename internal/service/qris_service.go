@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/qris"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/skip2/go-qrcode"
+)
+
+const qrisImageSize = 256
+
+type QRISService interface {
+	GeneratePayment(ctx context.Context, billingID uint, req dto.QRISRequest) (*dto.QRISResponse, error)
+	GetImage(ctx context.Context, reference string) ([]byte, error)
+	ResolveBillingID(ctx context.Context, reference string) (uint, error)
+}
+
+type qrisServiceImpl struct {
+	repo        repository.QRISPaymentRepository
+	billingRepo repository.BillingRepository
+	merchant    qris.Merchant
+}
+
+func NewQRISService(repo repository.QRISPaymentRepository, billingRepo repository.BillingRepository, cfg *config.QRISConfig) QRISService {
+	return &qrisServiceImpl{
+		repo:        repo,
+		billingRepo: billingRepo,
+		merchant: qris.Merchant{
+			AcquirerDomain: cfg.AcquirerDomain,
+			MerchantPAN:    cfg.MerchantPAN,
+			MerchantID:     cfg.MerchantID,
+			NMID:           cfg.NMID,
+			Criteria:       cfg.Criteria,
+			MCC:            cfg.MCC,
+			Name:           cfg.MerchantName,
+			City:           cfg.MerchantCity,
+			PostalCode:     cfg.PostalCode,
+		},
+	}
+}
+
+func (svc *qrisServiceImpl) GeneratePayment(ctx context.Context, billingID uint, req dto.QRISRequest) (*dto.QRISResponse, error) {
+	billing, err := svc.billingRepo.FindByID(ctx, billingID)
+	if err != nil {
+		return nil, err
+	}
+	if billing.Status == model.BillingStatusClosed {
+		return nil, fmt.Errorf("Billing %d has been closed.", billing.ID)
+	}
+	payment := &model.QRISPayment{
+		BillingID:  billing.ID,
+		CustomerID: billing.CustomerID,
+		Amount:     req.Amount,
+	}
+	if req.Amount < 0 {
+		return nil, fmt.Errorf("Amount must be positive.")
+	}
+	if req.Amount == 0 {
+		installment := nextInstallment(billing)
+		if installment == nil {
+			return nil, fmt.Errorf("Billing %d has no installment due, an amount is required.", billing.ID)
+		}
+		payment.Amount = installment.AmountDue
+		payment.Week = installment.Week
+	}
+
+	payment.Reference, err = generateQRISReference(billing.ID)
+	if err != nil {
+		return nil, err
+	}
+	payment.Payload, err = qris.GenerateDynamic(svc.merchant, qris.Payment{
+		Amount:     payment.Amount,
+		BillNumber: strconv.FormatUint(uint64(billing.ID), 10),
+		Reference:  payment.Reference,
+	})
+	if err != nil {
+		return nil, err
+	}
+	png, err := qrcode.Encode(payment.Payload, qrcode.Medium, qrisImageSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := svc.repo.Create(ctx, payment); err != nil {
+		return nil, err
+	}
+	return &dto.QRISResponse{
+		Reference: payment.Reference,
+		BillingID: payment.BillingID,
+		Amount:    payment.Amount,
+		Week:      payment.Week,
+		Payload:   payment.Payload,
+		Image:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+func (svc *qrisServiceImpl) GetImage(ctx context.Context, reference string) ([]byte, error) {
+	payment, err := svc.repo.FindByReference(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+	return qrcode.Encode(payment.Payload, qrcode.Medium, qrisImageSize)
+}
+
+func (svc *qrisServiceImpl) ResolveBillingID(ctx context.Context, reference string) (uint, error) {
+	payment, err := svc.repo.FindByReference(ctx, reference)
+	if err != nil {
+		return 0, err
+	}
+	return payment.BillingID, nil
+}
+
+func generateQRISReference(billingID uint) (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("QR%d%s", billingID, strings.ToUpper(hex.EncodeToString(b))), nil
+}
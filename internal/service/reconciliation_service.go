@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/doddeeph/billing-engine/internal/statement"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"gorm.io/gorm"
+)
+
+var errStatementLineReconciled = errors.New("statement line already reconciled")
+
+var (
+	virtualAccountPattern = regexp.MustCompile(`\d{10,}`)
+	qrisReferencePattern  = regexp.MustCompile(`QR\d+[0-9A-F]{8}`)
+)
+
+type ReconciliationService interface {
+	ImportStatement(ctx context.Context, fileName, format string, data []byte) (*model.StatementImport, bool, error)
+	GetImport(ctx context.Context, id uint, status string) (*model.StatementImport, error)
+	GetImports(ctx context.Context, limit int) ([]model.StatementImport, error)
+}
+
+type reconciliationServiceImpl struct {
+	repo        repository.StatementRepository
+	gatewayRepo repository.GatewayCallbackRepository
+	paymentSvc  PaymentService
+	vaSvc       VirtualAccountService
+	qrisSvc     QRISService
+	cfg         *config.ReconciliationConfig
+}
+
+func NewReconciliationService(repo repository.StatementRepository, gatewayRepo repository.GatewayCallbackRepository, paymentSvc PaymentService, vaSvc VirtualAccountService, qrisSvc QRISService, cfg *config.ReconciliationConfig) ReconciliationService {
+	return &reconciliationServiceImpl{repo: repo, gatewayRepo: gatewayRepo, paymentSvc: paymentSvc, vaSvc: vaSvc, qrisSvc: qrisSvc, cfg: cfg}
+}
+
+func (svc *reconciliationServiceImpl) ImportStatement(ctx context.Context, fileName, format string, data []byte) (*model.StatementImport, bool, error) {
+	if format == "" {
+		format = statement.DetectFormat(fileName, data)
+	}
+	stmt, err := statement.Parse(format, data)
+	if err != nil {
+		return nil, false, err
+	}
+	checksum := sha256.Sum256(data)
+	statementImport := &model.StatementImport{
+		Format:             stmt.Format,
+		FileName:           fileName,
+		Checksum:           hex.EncodeToString(checksum[:]),
+		AccountNumber:      stmt.AccountNumber,
+		StatementReference: stmt.Reference,
+		TotalLines:         len(stmt.Lines),
+	}
+	for _, line := range stmt.Lines {
+		statementImport.Lines = append(statementImport.Lines, model.StatementLine{
+			LineNo:      line.LineNo,
+			ValueDate:   line.ValueDate,
+			Direction:   line.Direction,
+			Amount:      line.Amount,
+			Reference:   line.Reference,
+			Description: line.Description,
+			Status:      model.StatementLineStatusPending,
+		})
+	}
+	var created bool
+	err = svc.repo.WithDB().Transaction(func(trx *gorm.DB) error {
+		trxRepo := svc.repo.WithTransaction(trx)
+		created, err = trxRepo.CreateImportIfAbsent(ctx, statementImport)
+		if err != nil || !created {
+			return err
+		}
+		for i := range statementImport.Lines {
+			statementImport.Lines[i].ImportID = statementImport.ID
+		}
+		return trxRepo.CreateLines(ctx, statementImport.Lines)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if !created {
+		statementImport, err = svc.repo.FindImportByChecksum(ctx, statementImport.Checksum)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	pending := 0
+	for i := range statementImport.Lines {
+		if statementImport.Lines[i].Status != model.StatementLineStatusPending {
+			continue
+		}
+		pending++
+		if err := svc.reconcileLine(ctx, &statementImport.Lines[i]); err != nil {
+			return nil, false, err
+		}
+	}
+	if pending == 0 {
+		return statementImport, true, nil
+	}
+	statementImport, err = svc.repo.FindImportByID(ctx, statementImport.ID, "")
+	if err != nil {
+		return nil, false, err
+	}
+	countStatementLines(statementImport)
+	if err := svc.repo.UpdateImport(ctx, statementImport); err != nil {
+		return nil, false, err
+	}
+	return statementImport, !created, nil
+}
+
+func countStatementLines(statementImport *model.StatementImport) {
+	statementImport.MatchedLines = 0
+	statementImport.AppliedLines = 0
+	statementImport.MismatchedLines = 0
+	statementImport.UnmatchedLines = 0
+	statementImport.IgnoredLines = 0
+	for _, line := range statementImport.Lines {
+		switch line.Status {
+		case model.StatementLineStatusMatched:
+			statementImport.MatchedLines++
+		case model.StatementLineStatusApplied:
+			statementImport.AppliedLines++
+		case model.StatementLineStatusMismatched:
+			statementImport.MismatchedLines++
+		case model.StatementLineStatusUnmatched:
+			statementImport.UnmatchedLines++
+		case model.StatementLineStatusIgnored:
+			statementImport.IgnoredLines++
+		}
+	}
+}
+
+func (svc *reconciliationServiceImpl) reconcileLine(ctx context.Context, reconciled *model.StatementLine) error {
+	if reconciled.Direction == statement.DirectionDebit {
+		reconciled.Status = model.StatementLineStatusIgnored
+		reconciled.Note = "Debit line."
+		return svc.saveLine(ctx, reconciled)
+	}
+
+	matched, err := svc.matchByReference(ctx, reconciled)
+	if err != nil || matched {
+		return err
+	}
+
+	billingID, matchedBy := svc.identifyBilling(ctx, reconciled)
+	if billingID != 0 {
+		reconciled.BillingID = &billingID
+		reconciled.MatchedBy = matchedBy
+		payments, err := svc.findRecordedPayments(ctx, billingID, reconciled)
+		if err != nil {
+			return err
+		}
+		if len(payments) > 0 {
+			reconciled.Status = model.StatementLineStatusMatched
+			reconciled.PaymentID = &payments[0].ID
+			return svc.saveLine(ctx, reconciled)
+		}
+		return svc.applyLine(ctx, reconciled)
+	}
+
+	reconciled.MatchedBy = model.StatementMatchAmount
+	payments, err := svc.findRecordedPayments(ctx, 0, reconciled)
+	if err != nil {
+		return err
+	}
+	if len(payments) == 1 {
+		reconciled.Status = model.StatementLineStatusMatched
+		reconciled.BillingID = &payments[0].BillingID
+		reconciled.PaymentID = &payments[0].ID
+		return svc.saveLine(ctx, reconciled)
+	}
+	if len(payments) > 1 {
+		return svc.unmatched(ctx, reconciled, fmt.Sprintf("%d recorded payments match the amount.", len(payments)))
+	}
+	candidates, err := svc.repo.FindUnpaidByAmountDue(ctx, reconciled.Amount, 2)
+	if err != nil {
+		return err
+	}
+	switch len(candidates) {
+	case 0:
+		return svc.unmatched(ctx, reconciled, "No billing matches the line.")
+	case 1:
+		return svc.unmatched(ctx, reconciled, fmt.Sprintf("Only the amount matches the installment due on billing %d; apply it manually.", candidates[0].BillingID))
+	}
+	return svc.unmatched(ctx, reconciled, "More than one billing has an installment due for the amount.")
+}
+
+func (svc *reconciliationServiceImpl) matchByReference(ctx context.Context, reconciled *model.StatementLine) (bool, error) {
+	if reconciled.Reference == "" {
+		return false, nil
+	}
+	callback, err := svc.gatewayRepo.FindProcessedByExternalID(ctx, reconciled.Reference)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	reconciled.MatchedBy = model.StatementMatchReference
+	reconciled.BillingID = &callback.BillingID
+	reconciled.PaymentID = callback.PaymentID
+	reconciled.RecoveryID = callback.RecoveryID
+	reconciled.Status = model.StatementLineStatusMatched
+	if callback.Amount != reconciled.Amount {
+		reconciled.Status = model.StatementLineStatusMismatched
+		reconciled.Note = fmt.Sprintf("Gateway callback %s was for %d.", callback.ExternalID, callback.Amount)
+	} else if callback.PaymentID != nil {
+		existing, err := svc.repo.FindLineByPaymentID(ctx, *callback.PaymentID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, err
+		}
+		if existing != nil {
+			reconciled.Status = model.StatementLineStatusMismatched
+			reconciled.PaymentID = nil
+			reconciled.Note = fmt.Sprintf("Payment %d is already reconciled by statement line %d.", *callback.PaymentID, existing.ID)
+		}
+	}
+	return true, svc.saveLine(ctx, reconciled)
+}
+
+func (svc *reconciliationServiceImpl) identifyBilling(ctx context.Context, line *model.StatementLine) (uint, string) {
+	text := line.Reference + " " + line.Description
+	for _, number := range virtualAccountPattern.FindAllString(text, -1) {
+		if !utils.IsValidLuhn(number) {
+			continue
+		}
+		if billingID, err := svc.vaSvc.ResolveBillingID(ctx, number); err == nil {
+			return billingID, model.StatementMatchVirtualAccount
+		}
+	}
+	for _, reference := range qrisReferencePattern.FindAllString(text, -1) {
+		if billingID, err := svc.qrisSvc.ResolveBillingID(ctx, reference); err == nil {
+			return billingID, model.StatementMatchQRIS
+		}
+	}
+	return 0, ""
+}
+
+func (svc *reconciliationServiceImpl) findRecordedPayments(ctx context.Context, billingID uint, line *model.StatementLine) ([]model.Payment, error) {
+	window := time.Duration(svc.cfg.MatchWindowDays) * 24 * time.Hour
+	return svc.repo.FindUnreconciledPayments(ctx, billingID, line.Amount, line.ValueDate.Add(-window), line.ValueDate.Add(window+24*time.Hour))
+}
+
+func (svc *reconciliationServiceImpl) applyLine(ctx context.Context, reconciled *model.StatementLine) error {
+	err := svc.repo.WithDB().Transaction(func(trx *gorm.DB) error {
+		paymentResp, err := svc.paymentSvc.WithTransaction(trx).ApplyNextInstallment(ctx, *reconciled.BillingID, reconciled.Amount, reconciled.ValueDate)
+		if err != nil {
+			return err
+		}
+		reconciled.Status = model.StatementLineStatusApplied
+		if paymentResp.Payment != nil {
+			reconciled.PaymentID = &paymentResp.Payment.ID
+		}
+		if paymentResp.Recovery != nil {
+			reconciled.RecoveryID = &paymentResp.Recovery.ID
+		}
+		updated, err := svc.repo.WithTransaction(trx).UpdatePendingLine(ctx, reconciled)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return errStatementLineReconciled
+		}
+		return nil
+	})
+	if errors.Is(err, errStatementLineReconciled) {
+		return nil
+	}
+	if err != nil {
+		reconciled.Status = model.StatementLineStatusMismatched
+		reconciled.PaymentID = nil
+		reconciled.RecoveryID = nil
+		reconciled.Note = err.Error()
+		return svc.saveLine(ctx, reconciled)
+	}
+	return nil
+}
+
+func (svc *reconciliationServiceImpl) unmatched(ctx context.Context, reconciled *model.StatementLine, note string) error {
+	reconciled.Status = model.StatementLineStatusUnmatched
+	reconciled.MatchedBy = ""
+	reconciled.BillingID = nil
+	reconciled.Note = note
+	return svc.saveLine(ctx, reconciled)
+}
+
+func (svc *reconciliationServiceImpl) saveLine(ctx context.Context, reconciled *model.StatementLine) error {
+	_, err := svc.repo.UpdatePendingLine(ctx, reconciled)
+	return err
+}
+
+func (svc *reconciliationServiceImpl) GetImport(ctx context.Context, id uint, status string) (*model.StatementImport, error) {
+	return svc.repo.FindImportByID(ctx, id, status)
+}
+
+func (svc *reconciliationServiceImpl) GetImports(ctx context.Context, limit int) ([]model.StatementImport, error) {
+	return svc.repo.FindImports(ctx, limit)
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/notification"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"gorm.io/gorm"
+)
+
+const reminderLookahead = 24 * time.Hour
+
+type ReminderService interface {
+	RunReminders(ctx context.Context) (int, int, error)
+	ScheduleReminders(ctx context.Context, now time.Time) (int, error)
+	DispatchDue(ctx context.Context, now time.Time) (int, int, error)
+	GetBillingReminders(ctx context.Context, billingID uint) ([]model.Reminder, error)
+	GetReminders(ctx context.Context, status string, limit int) ([]model.Reminder, error)
+}
+
+type reminderServiceImpl struct {
+	repo         repository.ReminderRepository
+	customerRepo repository.CustomerRepository
+	vaRepo       repository.VirtualAccountRepository
+	channels     map[string]notification.Channel
+	renderer     *notification.Renderer
+	cfg          *config.ReminderConfig
+	customerCfg  *config.CustomerConfig
+}
+
+func NewReminderService(repo repository.ReminderRepository, customerRepo repository.CustomerRepository, vaRepo repository.VirtualAccountRepository, channels map[string]notification.Channel, renderer *notification.Renderer, cfg *config.ReminderConfig, customerCfg *config.CustomerConfig) ReminderService {
+	return &reminderServiceImpl{
+		repo:         repo,
+		customerRepo: customerRepo,
+		vaRepo:       vaRepo,
+		channels:     channels,
+		renderer:     renderer,
+		cfg:          cfg,
+		customerCfg:  customerCfg,
+	}
+}
+
+func (svc *reminderServiceImpl) RunReminders(ctx context.Context) (int, int, error) {
+	now := time.Now()
+	if _, err := svc.ScheduleReminders(ctx, now); err != nil {
+		return 0, 0, err
+	}
+	return svc.DispatchDue(ctx, now)
+}
+
+func (svc *reminderServiceImpl) ScheduleReminders(ctx context.Context, now time.Time) (int, error) {
+	if len(svc.cfg.Offsets) == 0 {
+		return 0, nil
+	}
+	from := now.Add(-svc.cfg.GracePeriod)
+	to := now.Add(reminderLookahead)
+	dueFrom := from.AddDate(0, 0, -slices.Max(svc.cfg.Offsets)-1)
+	dueTo := to.AddDate(0, 0, -slices.Min(svc.cfg.Offsets)+1)
+	customers := make(map[uint]*model.Customer)
+	created := 0
+	var afterID uint
+	for {
+		targets, err := svc.repo.FindTargets(ctx, dueFrom, dueTo, afterID, svc.cfg.BatchSize)
+		if err != nil {
+			return created, err
+		}
+		var reminders []model.Reminder
+		for _, target := range targets {
+			customer, ok := customers[target.CustomerID]
+			if !ok {
+				if customer, err = svc.customerRepo.FindByID(ctx, target.CustomerID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					return created, err
+				}
+				customers[target.CustomerID] = customer
+			}
+			reminders = append(reminders, planReminders(target, customer, svc.cfg, svc.customerCfg, from, to)...)
+		}
+		n, err := svc.repo.CreateIfAbsent(ctx, reminders)
+		created += n
+		if err != nil {
+			return created, err
+		}
+		if len(targets) < svc.cfg.BatchSize {
+			return created, nil
+		}
+		afterID = targets[len(targets)-1].PaymentID
+	}
+}
+
+func (svc *reminderServiceImpl) DispatchDue(ctx context.Context, now time.Time) (int, int, error) {
+	sent, failed := 0, 0
+	for {
+		reminders, err := svc.repo.FindDue(ctx, now, svc.cfg.BatchSize)
+		if err != nil {
+			return sent, failed, err
+		}
+		for i := range reminders {
+			reminder := &reminders[i]
+			if err := svc.dispatch(ctx, reminder, now); err != nil {
+				return sent, failed, err
+			}
+			switch {
+			case reminder.Status == model.ReminderStatusSent:
+				sent++
+			case reminder.Status != model.ReminderStatusCancelled:
+				failed++
+			}
+		}
+		if len(reminders) < svc.cfg.BatchSize {
+			return sent, failed, nil
+		}
+	}
+}
+
+func (svc *reminderServiceImpl) dispatch(ctx context.Context, reminder *model.Reminder, now time.Time) error {
+	target, err := svc.repo.FindTarget(ctx, reminder.PaymentID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	customer, err := svc.customerRepo.FindByID(ctx, reminder.CustomerID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if reason := reminderCancelReason(reminder, target, customer, svc.cfg, now); reason != "" {
+		reminder.Status = model.ReminderStatusCancelled
+		reminder.LastError = reason
+		return svc.repo.Update(ctx, reminder)
+	}
+	virtualAccounts, err := svc.vaRepo.FindByBillingID(ctx, reminder.BillingID)
+	if err != nil {
+		return err
+	}
+	reminder.Recipient = reminderRecipient(customer, reminder.Channel)
+	ref, sendErr := svc.send(ctx, reminder, reminderTemplateData(target, customer, virtualAccounts, reminder.DayOffset, reminder.Language, customerLocation(customer, svc.customerCfg)))
+	reminder.Attempts++
+	if sendErr == nil {
+		reminder.Status = model.ReminderStatusSent
+		reminder.SentAt = &now
+		reminder.ProviderRef = ref
+		reminder.LastError = ""
+	} else {
+		reminder.LastError = sendErr.Error()
+		if reminder.Attempts >= svc.cfg.MaxAttempts {
+			reminder.Status = model.ReminderStatusFailed
+		} else {
+			reminder.NextAttemptAt = now.Add(svc.cfg.RetryInterval)
+		}
+	}
+	return svc.repo.Update(ctx, reminder)
+}
+
+func (svc *reminderServiceImpl) send(ctx context.Context, reminder *model.Reminder, data notification.TemplateData) (string, error) {
+	subject, body, err := svc.renderer.Render(reminder.Language, notification.TemplateForOffset(reminder.DayOffset), data)
+	if err != nil {
+		return "", err
+	}
+	reminder.Subject = subject
+	reminder.Body = body
+	channel, ok := svc.channels[reminder.Channel]
+	if !ok {
+		return "", fmt.Errorf("no adapter for channel %s", reminder.Channel)
+	}
+	return channel.Send(ctx, notification.Message{
+		Reference: fmt.Sprintf("reminder-%d", reminder.ID),
+		Channel:   reminder.Channel,
+		To:        reminder.Recipient,
+		Subject:   subject,
+		Body:      body,
+	})
+}
+
+func (svc *reminderServiceImpl) GetBillingReminders(ctx context.Context, billingID uint) ([]model.Reminder, error) {
+	return svc.repo.FindByBillingID(ctx, billingID)
+}
+
+func (svc *reminderServiceImpl) GetReminders(ctx context.Context, status string, limit int) ([]model.Reminder, error) {
+	return svc.repo.FindRecent(ctx, status, limit)
+}
+
+func planReminders(target repository.ReminderTarget, customer *model.Customer, cfg *config.ReminderConfig, customerCfg *config.CustomerConfig, from, to time.Time) []model.Reminder {
+	var channels []string
+	for _, channel := range cfg.Channels {
+		if reminderRecipient(customer, strings.ToUpper(channel)) != "" {
+			channels = append(channels, strings.ToUpper(channel))
+		}
+	}
+	if len(channels) == 0 {
+		return nil
+	}
+	loc := customerLocation(customer, customerCfg)
+	language := customer.PreferredLanguage
+	if language == "" {
+		language = customerCfg.DefaultLanguage
+	}
+	var reminders []model.Reminder
+	for _, offset := range cfg.Offsets {
+		scheduledAt := reminderTime(target.DueDate, offset, cfg.SendHour, loc)
+		if scheduledAt.Before(from) || scheduledAt.After(to) {
+			continue
+		}
+		for _, channel := range channels {
+			reminders = append(reminders, model.Reminder{
+				BillingID:     target.BillingID,
+				PaymentID:     target.PaymentID,
+				CustomerID:    target.CustomerID,
+				Week:          target.Week,
+				DueDate:       target.DueDate,
+				DayOffset:     offset,
+				Channel:       channel,
+				Language:      language,
+				Status:        model.ReminderStatusScheduled,
+				ScheduledAt:   scheduledAt,
+				NextAttemptAt: scheduledAt,
+			})
+		}
+	}
+	return reminders
+}
+
+func reminderTime(dueDate time.Time, offset, sendHour int, loc *time.Location) time.Time {
+	year, month, day := dueDate.In(loc).Date()
+	return time.Date(year, month, day+offset, sendHour, 0, 0, 0, loc)
+}
+
+func reminderRecipient(customer *model.Customer, channel string) string {
+	if customer == nil {
+		return ""
+	}
+	switch channel {
+	case notification.ChannelSMS:
+		if customer.SMSOptIn {
+			return customer.Phone
+		}
+	case notification.ChannelWhatsapp:
+		if customer.WhatsappOptIn {
+			return customer.Phone
+		}
+	case notification.ChannelEmail:
+		if customer.EmailOptIn {
+			return customer.Email
+		}
+	}
+	return ""
+}
+
+func customerLocation(customer *model.Customer, customerCfg *config.CustomerConfig) *time.Location {
+	if customer != nil && customer.Timezone != "" {
+		if loc, err := time.LoadLocation(customer.Timezone); err == nil {
+			return loc
+		}
+	}
+	if loc, err := time.LoadLocation(customerCfg.DefaultTimezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+func reminderCancelReason(reminder *model.Reminder, target *repository.ReminderTarget, customer *model.Customer, cfg *config.ReminderConfig, now time.Time) string {
+	switch {
+	case target == nil:
+		return "Installment no longer exists."
+	case target.Paid:
+		return "Installment is paid."
+	case target.BillingStatus != model.BillingStatusActive:
+		return fmt.Sprintf("Billing is %s.", target.BillingStatus)
+	case !target.DueDate.Equal(reminder.DueDate):
+		return "Installment was rescheduled."
+	case reminderRecipient(customer, reminder.Channel) == "":
+		return fmt.Sprintf("Customer opted out of %s.", reminder.Channel)
+	case now.After(reminder.ScheduledAt.Add(cfg.GracePeriod)):
+		return "Reminder is past its send window."
+	}
+	return ""
+}
+
+func reminderTemplateData(target *repository.ReminderTarget, customer *model.Customer, virtualAccounts []model.VirtualAccount, offset int, language string, loc *time.Location) notification.TemplateData {
+	data := notification.TemplateData{
+		CustomerName: customer.Name,
+		LoanID:       target.LoanID,
+		Week:         target.Week,
+		Amount:       notification.FormatRupiah(target.Amount + target.LateFee),
+		DueDate:      notification.FormatDate(target.DueDate.In(loc), language),
+		Days:         max(offset, -offset),
+	}
+	for _, virtualAccount := range virtualAccounts {
+		data.VirtualAccounts = append(data.VirtualAccounts, notification.VirtualAccount{BankCode: virtualAccount.BankCode, Number: virtualAccount.Number})
+	}
+	return data
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var testReminderConfig = &config.ReminderConfig{
+	Offsets:     []int{-2, 0, 1},
+	Channels:    []string{"SMS", "EMAIL", "WHATSAPP"},
+	SendHour:    9,
+	GracePeriod: 12 * time.Hour,
+	MaxAttempts: 3,
+}
+
+func testReminderTarget() repository.ReminderTarget {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	return repository.ReminderTarget{
+		PaymentID:     10,
+		BillingID:     1,
+		CustomerID:    7,
+		Week:          3,
+		Amount:        110000,
+		DueDate:       time.Date(2025, 8, 17, 23, 59, 59, 0, jakarta),
+		BillingStatus: model.BillingStatusActive,
+	}
+}
+
+func TestReminderTime(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	makassar, _ := time.LoadLocation("Asia/Makassar")
+	dueDate := testReminderTarget().DueDate
+
+	assert.Equal(t, time.Date(2025, 8, 15, 9, 0, 0, 0, jakarta), reminderTime(dueDate, -2, 9, jakarta))
+	assert.Equal(t, time.Date(2025, 8, 18, 9, 0, 0, 0, jakarta), reminderTime(dueDate, 1, 9, jakarta))
+	assert.Equal(t, time.Date(2025, 8, 19, 9, 0, 0, 0, makassar), reminderTime(dueDate, 1, 9, makassar))
+}
+
+func TestPlanReminders(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	customer := &model.Customer{ID: 7, Phone: "+6281234567890", SMSOptIn: true, Email: "budi@example.com", PreferredLanguage: "en"}
+	target := testReminderTarget()
+	now := time.Date(2025, 8, 15, 8, 0, 0, 0, jakarta)
+
+	reminders := planReminders(target, customer, testReminderConfig, testCustomerConfig, now.Add(-12*time.Hour), now.Add(24*time.Hour))
+	assert.Len(t, reminders, 1)
+	assert.Equal(t, -2, reminders[0].DayOffset)
+	assert.Equal(t, "SMS", reminders[0].Channel)
+	assert.Equal(t, "en", reminders[0].Language)
+	assert.Equal(t, time.Date(2025, 8, 15, 9, 0, 0, 0, jakarta), reminders[0].ScheduledAt)
+	assert.Equal(t, reminders[0].ScheduledAt, reminders[0].NextAttemptAt)
+
+	customer.EmailOptIn = true
+	customer.PreferredLanguage = ""
+	now = time.Date(2025, 8, 17, 8, 0, 0, 0, jakarta)
+	reminders = planReminders(target, customer, testReminderConfig, testCustomerConfig, now.Add(-12*time.Hour), now.Add(24*time.Hour))
+	assert.Len(t, reminders, 2)
+	assert.Equal(t, 0, reminders[0].DayOffset)
+	assert.Equal(t, "EMAIL", reminders[1].Channel)
+	assert.Equal(t, "id", reminders[1].Language)
+
+	assert.Empty(t, planReminders(target, &model.Customer{ID: 7}, testReminderConfig, testCustomerConfig, now.Add(-12*time.Hour), now.Add(24*time.Hour)))
+	assert.Empty(t, planReminders(target, nil, testReminderConfig, testCustomerConfig, now.Add(-12*time.Hour), now.Add(24*time.Hour)))
+}
+
+func TestReminderCancelReason(t *testing.T) {
+	target := testReminderTarget()
+	customer := &model.Customer{ID: 7, Phone: "+6281234567890", SMSOptIn: true}
+	reminder := &model.Reminder{PaymentID: 10, DueDate: target.DueDate, Channel: "SMS", ScheduledAt: target.DueDate.Add(-15 * time.Hour)}
+	now := reminder.ScheduledAt.Add(time.Hour)
+
+	assert.Empty(t, reminderCancelReason(reminder, &target, customer, testReminderConfig, now))
+	assert.Equal(t, "Installment no longer exists.", reminderCancelReason(reminder, nil, customer, testReminderConfig, now))
+	assert.Equal(t, "Reminder is past its send window.", reminderCancelReason(reminder, &target, customer, testReminderConfig, now.Add(12*time.Hour)))
+
+	paid := target
+	paid.Paid = true
+	assert.Equal(t, "Installment is paid.", reminderCancelReason(reminder, &paid, customer, testReminderConfig, now))
+
+	closed := target
+	closed.BillingStatus = model.BillingStatusWrittenOff
+	assert.Equal(t, "Billing is WRITTEN_OFF.", reminderCancelReason(reminder, &closed, customer, testReminderConfig, now))
+
+	shifted := target
+	shifted.DueDate = target.DueDate.AddDate(0, 0, 7)
+	assert.Equal(t, "Installment was rescheduled.", reminderCancelReason(reminder, &shifted, customer, testReminderConfig, now))
+
+	customer.SMSOptIn = false
+	assert.Equal(t, "Customer opted out of SMS.", reminderCancelReason(reminder, &target, customer, testReminderConfig, now))
+}
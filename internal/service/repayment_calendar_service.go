@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/ical"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/notification"
+	"github.com/doddeeph/billing-engine/internal/repository"
+)
+
+const repaymentCalendarProdID = "-//Billing Engine//Repayment Schedule//EN"
+
+type RepaymentCalendarService interface {
+	GetBillingCalendar(ctx context.Context, billingID uint) (*dto.CalendarFile, error)
+	GetCustomerCalendar(ctx context.Context, customerID uint) (*dto.CalendarFile, error)
+}
+
+type repaymentCalendarServiceImpl struct {
+	billingRepo  repository.BillingRepository
+	customerRepo repository.CustomerRepository
+	customerCfg  *config.CustomerConfig
+	cfg          *config.CalendarConfig
+}
+
+func NewRepaymentCalendarService(billingRepo repository.BillingRepository, customerRepo repository.CustomerRepository, customerCfg *config.CustomerConfig, cfg *config.CalendarConfig) RepaymentCalendarService {
+	return &repaymentCalendarServiceImpl{billingRepo: billingRepo, customerRepo: customerRepo, customerCfg: customerCfg, cfg: cfg}
+}
+
+func (svc *repaymentCalendarServiceImpl) GetBillingCalendar(ctx context.Context, billingID uint) (*dto.CalendarFile, error) {
+	billing, err := svc.billingRepo.FindByID(ctx, billingID)
+	if err != nil {
+		return nil, err
+	}
+	loc := customerLocation(billing.Customer, svc.customerCfg)
+	cal := svc.newCalendar(fmt.Sprintf("%s - Loan %d", svc.cfg.Name, billing.LoanID))
+	cal.Events = repaymentEvents(billing, loc, svc.cfg.UIDDomain)
+	return &dto.CalendarFile{
+		FileName: fmt.Sprintf("schedule-loan-%d.ics", billing.LoanID),
+		Content:  cal.Render(time.Now()),
+	}, nil
+}
+
+func (svc *repaymentCalendarServiceImpl) GetCustomerCalendar(ctx context.Context, customerID uint) (*dto.CalendarFile, error) {
+	customer, err := svc.customerRepo.FindByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	billings, err := svc.billingRepo.FindByCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	loc := customerLocation(customer, svc.customerCfg)
+	cal := svc.newCalendar(svc.cfg.Name)
+	for i := range billings {
+		cal.Events = append(cal.Events, repaymentEvents(&billings[i], loc, svc.cfg.UIDDomain)...)
+	}
+	return &dto.CalendarFile{
+		FileName: fmt.Sprintf("schedule-customer-%d.ics", customerID),
+		Content:  cal.Render(time.Now()),
+	}, nil
+}
+
+func (svc *repaymentCalendarServiceImpl) newCalendar(name string) *ical.Calendar {
+	return &ical.Calendar{
+		ProdID:  repaymentCalendarProdID,
+		Name:    name,
+		Refresh: time.Duration(svc.cfg.RefreshHours) * time.Hour,
+	}
+}
+
+func repaymentEvents(billing *model.Billing, loc *time.Location, uidDomain string) []ical.Event {
+	events := make([]ical.Event, 0, len(billing.Payments))
+	for _, p := range billing.Payments {
+		event := ical.Event{
+			UID:          fmt.Sprintf("payment-%d@%s", p.ID, uidDomain),
+			Sequence:     p.Revision,
+			Date:         p.DueDate.In(loc),
+			LastModified: p.UpdatedAt,
+			Summary:      fmt.Sprintf("Loan %d installment %d of %d due", billing.LoanID, p.Week, billing.LoanWeeks),
+			Status:       ical.StatusConfirmed,
+		}
+		lines := []string{fmt.Sprintf("Amount due: %s", notification.FormatRupiah(p.Amount+p.LateFee))}
+		if p.LateFee > 0 {
+			lines = append(lines, fmt.Sprintf("Includes late fee: %s", notification.FormatRupiah(p.LateFee)))
+		}
+		switch {
+		case p.Paid:
+			event.Status = ical.StatusCancelled
+			if p.PaidDate != nil {
+				lines = append(lines, fmt.Sprintf("Paid on %s.", p.PaidDate.In(loc).Format(time.DateOnly)))
+			} else {
+				lines = append(lines, "Paid.")
+			}
+		case billing.Status != model.BillingStatusActive:
+			event.Status = ical.StatusCancelled
+			event.Sequence++
+			lines = append(lines, fmt.Sprintf("Billing is %s.", strings.ToLower(strings.ReplaceAll(billing.Status, "_", " "))))
+		}
+		lines = append(lines, fmt.Sprintf("Billing ID: %d", billing.ID))
+		event.Description = strings.Join(lines, "\n")
+		events = append(events, event)
+	}
+	return events
+}
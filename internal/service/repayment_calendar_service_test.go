@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/ical"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepaymentEvents(t *testing.T) {
+	billing := testStatementBilling()
+	for i := range billing.Payments {
+		billing.Payments[i].ID = uint(10 + i)
+		billing.Payments[i].CreatedAt = billing.CreatedAt
+		billing.Payments[i].UpdatedAt = billing.CreatedAt
+	}
+	billing.Payments[1].UpdatedAt = billing.CreatedAt.Add(90 * time.Second)
+	billing.Payments[1].Revision = 2
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+
+	events := repaymentEvents(billing, loc, "billing.test")
+
+	assert.Len(t, events, 4)
+	assert.Equal(t, "payment-10@billing.test", events[0].UID)
+	assert.Equal(t, ical.StatusCancelled, events[0].Status)
+	assert.Contains(t, events[0].Description, "Paid on 2025-08-07.")
+	assert.Equal(t, 2, events[1].Sequence)
+	assert.Equal(t, ical.StatusConfirmed, events[2].Status)
+	assert.Equal(t, "Loan 1001 installment 3 of 4 due", events[2].Summary)
+	assert.Equal(t, "2025-08-22", events[2].Date.Format(time.DateOnly))
+	assert.Equal(t, "Amount due: Rp105.000\nIncludes late fee: Rp5.000\nBilling ID: 1", events[2].Description)
+	assert.Equal(t, 0, events[3].Sequence)
+	assert.Equal(t, "Amount due: Rp100.000\nBilling ID: 1", events[3].Description)
+}
+
+func TestRepaymentEvents_InactiveBillingCancelsUnpaid(t *testing.T) {
+	billing := testStatementBilling()
+	billing.Status = model.BillingStatusWrittenOff
+	billing.Payments[3].Revision = 1
+
+	events := repaymentEvents(billing, time.UTC, "billing.test")
+
+	for _, e := range events {
+		assert.Equal(t, ical.StatusCancelled, e.Status)
+	}
+	assert.Contains(t, events[3].Description, "Billing is written off.")
+	assert.Equal(t, 0, events[0].Sequence)
+	assert.Equal(t, 2, events[3].Sequence)
+}
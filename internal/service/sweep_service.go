@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"gorm.io/gorm"
+)
+
+type SweepService interface {
+	RunEndOfDaySweep(ctx context.Context) (int, int, error)
+}
+
+type sweepServiceImpl struct {
+	billingRepo    repository.BillingRepository
+	paymentRepo    repository.PaymentRepository
+	delinquencySvc DelinquencyService
+	promiseSvc     PaymentPromiseService
+	cfg            *config.BillingConfig
+}
+
+func NewSweepService(billingRepo repository.BillingRepository, paymentRepo repository.PaymentRepository, delinquencySvc DelinquencyService, promiseSvc PaymentPromiseService, cfg *config.BillingConfig) SweepService {
+	return &sweepServiceImpl{billingRepo: billingRepo, paymentRepo: paymentRepo, delinquencySvc: delinquencySvc, promiseSvc: promiseSvc, cfg: cfg}
+}
+
+func (svc *sweepServiceImpl) RunEndOfDaySweep(ctx context.Context) (int, int, error) {
+	var errs []error
+	if _, err := svc.promiseSvc.BreakExpired(ctx, time.Now()); err != nil {
+		errs = append(errs, fmt.Errorf("payment promises: %w", err))
+	}
+	result, err := svc.delinquencySvc.EvaluateActiveBillings(ctx, svc.markOverdue)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return result.Evaluated, result.Failed, errors.Join(errs...)
+}
+
+func (svc *sweepServiceImpl) markOverdue(ctx context.Context, trx *gorm.DB, billing *model.Billing, now time.Time) error {
+	if isFrozen(billing.Freezes, now) {
+		return nil
+	}
+	marked, err := svc.paymentRepo.WithTransaction(trx).MarkOverdue(ctx, billing.ID, now, svc.cfg.LateFeeAmount)
+	if err != nil {
+		return err
+	}
+	if marked > 0 && svc.cfg.LateFeeAmount > 0 {
+		updatedOutstanding := billing.Outstanding + int(marked)*svc.cfg.LateFeeAmount
+		return svc.billingRepo.WithTransaction(trx).UpdateOutstanding(ctx, billing.ID, updatedOutstanding)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"gorm.io/gorm"
+)
+
+var ErrVirtualAccountNotFound = errors.New("Unknown virtual account")
+
+type VirtualAccountService interface {
+	WithTransaction(tx *gorm.DB) VirtualAccountService
+	IssueForBilling(ctx context.Context, billing *model.Billing) ([]model.VirtualAccount, error)
+	IssueVirtualAccounts(ctx context.Context, billingID uint) ([]model.VirtualAccount, error)
+	GetVirtualAccount(ctx context.Context, number string) (*dto.VirtualAccountResponse, error)
+	ResolveBillingID(ctx context.Context, number string) (uint, error)
+}
+
+type virtualAccountServiceImpl struct {
+	repo        repository.VirtualAccountRepository
+	billingRepo repository.BillingRepository
+	cfg         *config.VirtualAccountConfig
+}
+
+func NewVirtualAccountService(repo repository.VirtualAccountRepository, billingRepo repository.BillingRepository, cfg *config.VirtualAccountConfig) VirtualAccountService {
+	return &virtualAccountServiceImpl{repo: repo, billingRepo: billingRepo, cfg: cfg}
+}
+
+func (svc *virtualAccountServiceImpl) WithTransaction(tx *gorm.DB) VirtualAccountService {
+	return &virtualAccountServiceImpl{
+		repo:        svc.repo.WithTransaction(tx),
+		billingRepo: svc.billingRepo.WithTransaction(tx),
+		cfg:         svc.cfg,
+	}
+}
+
+func (svc *virtualAccountServiceImpl) IssueForBilling(ctx context.Context, billing *model.Billing) ([]model.VirtualAccount, error) {
+	var errs []error
+	for _, bank := range svc.cfg.Banks {
+		number, err := utils.GenerateVirtualAccountNumber(bank.Prefix, billing.ID, svc.cfg.Length)
+		if err == nil {
+			err = svc.repo.CreateIfAbsent(ctx, &model.VirtualAccount{
+				Number:     number,
+				BankCode:   bank.Code,
+				BillingID:  billing.ID,
+				CustomerID: billing.CustomerID,
+			})
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("bank %s: %w", bank.Code, err))
+		}
+	}
+	virtualAccounts, err := svc.repo.FindByBillingID(ctx, billing.ID)
+	return virtualAccounts, errors.Join(append(errs, err)...)
+}
+
+func (svc *virtualAccountServiceImpl) IssueVirtualAccounts(ctx context.Context, billingID uint) ([]model.VirtualAccount, error) {
+	billing, err := svc.billingRepo.FindByID(ctx, billingID)
+	if err != nil {
+		return nil, err
+	}
+	return svc.IssueForBilling(ctx, billing)
+}
+
+func (svc *virtualAccountServiceImpl) GetVirtualAccount(ctx context.Context, number string) (*dto.VirtualAccountResponse, error) {
+	virtualAccount, err := svc.repo.FindByNumber(ctx, number)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w %s.", ErrVirtualAccountNotFound, number)
+	}
+	if err != nil {
+		return nil, err
+	}
+	billing, err := svc.billingRepo.FindByID(ctx, virtualAccount.BillingID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.VirtualAccountResponse{
+		Number:          virtualAccount.Number,
+		BankCode:        virtualAccount.BankCode,
+		BillingID:       billing.ID,
+		CustomerID:      billing.CustomerID,
+		LoanID:          billing.LoanID,
+		BillingStatus:   billing.Status,
+		Outstanding:     billing.Outstanding,
+		NextInstallment: nextInstallment(billing),
+	}, nil
+}
+
+func (svc *virtualAccountServiceImpl) ResolveBillingID(ctx context.Context, number string) (uint, error) {
+	virtualAccount, err := svc.repo.FindByNumber(ctx, number)
+	if err != nil {
+		return 0, err
+	}
+	return virtualAccount.BillingID, nil
+}
+
+func nextInstallment(billing *model.Billing) *dto.NextInstallment {
+	if billing.Status != model.BillingStatusActive {
+		return nil
+	}
+	for _, p := range billing.Payments {
+		if !p.Paid {
+			return &dto.NextInstallment{
+				Week:      p.Week,
+				Amount:    p.Amount,
+				LateFee:   p.LateFee,
+				AmountDue: p.Amount + p.LateFee,
+				DueDate:   p.DueDate,
+			}
+		}
+	}
+	return nil
+}
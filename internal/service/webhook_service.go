@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/outbox"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/doddeeph/billing-engine/pkg/events"
+)
+
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, req dto.WebhookSubscriptionRequest) (*dto.WebhookSubscriptionResponse, error)
+	UpdateSubscription(ctx context.Context, id uint, req dto.WebhookSubscriptionRequest) (*dto.WebhookSubscriptionResponse, error)
+	DeleteSubscription(ctx context.Context, id uint) error
+	GetSubscriptions(ctx context.Context) ([]dto.WebhookSubscriptionResponse, error)
+	GetDeliveries(ctx context.Context, subscriptionID uint, status string, limit int) ([]model.WebhookDelivery, error)
+	ReplayDelivery(ctx context.Context, id uint) (*model.WebhookDelivery, error)
+	Enqueue(ctx context.Context, event model.OutboxEvent) error
+}
+
+type webhookServiceImpl struct {
+	repo repository.WebhookRepository
+}
+
+func NewWebhookService(repo repository.WebhookRepository) WebhookService {
+	return &webhookServiceImpl{repo: repo}
+}
+
+func validateWebhookSubscriptionRequest(req dto.WebhookSubscriptionRequest) error {
+	u, err := url.ParseRequestURI(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("Webhook url must be an absolute http or https URL.")
+	}
+	for _, eventType := range req.EventTypes {
+		if !slices.Contains(events.Types(), eventType) {
+			return fmt.Errorf("Unknown event type %q.", eventType)
+		}
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func toWebhookSubscriptionResponse(subscription *model.WebhookSubscription) dto.WebhookSubscriptionResponse {
+	eventTypes := []string{}
+	if subscription.EventTypes != "" {
+		eventTypes = strings.Split(subscription.EventTypes, ",")
+	}
+	return dto.WebhookSubscriptionResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: eventTypes,
+		Active:     subscription.Active,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+func subscribedTo(subscription model.WebhookSubscription, eventType string) bool {
+	return subscription.EventTypes == "" || slices.Contains(strings.Split(subscription.EventTypes, ","), eventType)
+}
+
+func (svc *webhookServiceImpl) CreateSubscription(ctx context.Context, req dto.WebhookSubscriptionRequest) (*dto.WebhookSubscriptionResponse, error) {
+	if err := validateWebhookSubscriptionRequest(req); err != nil {
+		return nil, err
+	}
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+	subscription := &model.WebhookSubscription{
+		URL:        req.URL,
+		EventTypes: strings.Join(req.EventTypes, ","),
+		Secret:     secret,
+		Active:     req.Active == nil || *req.Active,
+	}
+	if err := svc.repo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+	resp := toWebhookSubscriptionResponse(subscription)
+	resp.Secret = secret
+	return &resp, nil
+}
+
+func (svc *webhookServiceImpl) UpdateSubscription(ctx context.Context, id uint, req dto.WebhookSubscriptionRequest) (*dto.WebhookSubscriptionResponse, error) {
+	if err := validateWebhookSubscriptionRequest(req); err != nil {
+		return nil, err
+	}
+	subscription, err := svc.repo.FindSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	subscription.URL = req.URL
+	subscription.EventTypes = strings.Join(req.EventTypes, ",")
+	if req.Active != nil {
+		subscription.Active = *req.Active
+	}
+	if req.Secret != "" {
+		subscription.Secret = req.Secret
+	}
+	if err := svc.repo.UpdateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+	resp := toWebhookSubscriptionResponse(subscription)
+	return &resp, nil
+}
+
+func (svc *webhookServiceImpl) DeleteSubscription(ctx context.Context, id uint) error {
+	if _, err := svc.repo.FindSubscriptionByID(ctx, id); err != nil {
+		return err
+	}
+	return svc.repo.DeleteSubscription(ctx, id)
+}
+
+func (svc *webhookServiceImpl) GetSubscriptions(ctx context.Context) ([]dto.WebhookSubscriptionResponse, error) {
+	subscriptions, err := svc.repo.FindSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]dto.WebhookSubscriptionResponse, len(subscriptions))
+	for i := range subscriptions {
+		resp[i] = toWebhookSubscriptionResponse(&subscriptions[i])
+	}
+	return resp, nil
+}
+
+func (svc *webhookServiceImpl) GetDeliveries(ctx context.Context, subscriptionID uint, status string, limit int) ([]model.WebhookDelivery, error) {
+	return svc.repo.FindDeliveries(ctx, subscriptionID, status, limit)
+}
+
+func (svc *webhookServiceImpl) ReplayDelivery(ctx context.Context, id uint) (*model.WebhookDelivery, error) {
+	delivery, err := svc.repo.FindDeliveryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status != model.WebhookDeliveryStatusDead {
+		return nil, fmt.Errorf("Only %s deliveries can be replayed, delivery %d is %s.", model.WebhookDeliveryStatusDead, delivery.ID, delivery.Status)
+	}
+	delivery.Status = model.WebhookDeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	delivery.LastStatusCode = 0
+	delivery.LastError = ""
+	if err := svc.repo.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (svc *webhookServiceImpl) Enqueue(ctx context.Context, event model.OutboxEvent) error {
+	subscriptions, err := svc.repo.FindActiveSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(outbox.ToCloudEvent(event))
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, subscription := range subscriptions {
+		if !subscribedTo(subscription, event.EventType) {
+			continue
+		}
+		err := svc.repo.CreateDelivery(ctx, &model.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			OutboxEventID:  event.ID,
+			EventType:      event.EventType,
+			Payload:        payload,
+			Status:         model.WebhookDeliveryStatusPending,
+			NextAttemptAt:  now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/doddeeph/billing-engine/internal/utils"
+	"gorm.io/gorm"
+)
+
+type WriteOffService interface {
+	WithTransaction(tx *gorm.DB) WriteOffService
+	WriteOff(ctx context.Context, billingID uint, req dto.WriteOffRequest) (*dto.WriteOffResponse, error)
+	RecordRecovery(ctx context.Context, billing *model.Billing, amount int) (*model.Recovery, error)
+	GetRecoveries(ctx context.Context, billingID uint) (*dto.RecoveriesResponse, error)
+	GetReport(ctx context.Context) (*dto.WriteOffReportResponse, error)
+}
+
+type writeOffServiceImpl struct {
+	billingRepo   repository.BillingRepository
+	recoveryRepo  repository.RecoveryRepository
+	collectionSvc CollectionService
+	cfg           *config.BillingConfig
+}
+
+func NewWriteOffService(billingRepo repository.BillingRepository, recoveryRepo repository.RecoveryRepository, collectionSvc CollectionService, cfg *config.BillingConfig) WriteOffService {
+	return &writeOffServiceImpl{billingRepo: billingRepo, recoveryRepo: recoveryRepo, collectionSvc: collectionSvc, cfg: cfg}
+}
+
+func (svc *writeOffServiceImpl) WithTransaction(tx *gorm.DB) WriteOffService {
+	return &writeOffServiceImpl{
+		billingRepo:   svc.billingRepo.WithTransaction(tx),
+		recoveryRepo:  svc.recoveryRepo.WithTransaction(tx),
+		collectionSvc: svc.collectionSvc.WithTransaction(tx),
+		cfg:           svc.cfg,
+	}
+}
+
+func (svc *writeOffServiceImpl) WriteOff(ctx context.Context, billingID uint, req dto.WriteOffRequest) (*dto.WriteOffResponse, error) {
+	if req.Reason == "" {
+		return nil, fmt.Errorf("Write-off reason is required.")
+	}
+	var resp *dto.WriteOffResponse
+	err := svc.billingRepo.WithDB().Transaction(func(trx *gorm.DB) error {
+		trxBillingRepo := svc.billingRepo.WithTransaction(trx)
+		billing, err := trxBillingRepo.FindByIDForUpdate(ctx, billingID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := validateWriteOff(billing, svc.cfg.WriteOffMinDaysPastDue, now); err != nil {
+			return err
+		}
+		if err := trxBillingRepo.WriteOff(ctx, billing.ID, billing.Outstanding, req.Reason, now); err != nil {
+			return err
+		}
+		if err := svc.collectionSvc.WithTransaction(trx).CloseCase(ctx, billing.ID, "Billing is written off.", now); err != nil {
+			return err
+		}
+		resp = &dto.WriteOffResponse{
+			BaseResponse: dto.BaseResponse{
+				BillingID:  billing.ID,
+				CustomerID: billing.CustomerID,
+				LoanID:     billing.LoanID,
+			},
+			Status:           model.BillingStatusWrittenOff,
+			WrittenOffAmount: billing.Outstanding,
+			WriteOffReason:   req.Reason,
+			WrittenOffAt:     now,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func validateWriteOff(billing *model.Billing, minDaysPastDue int, now time.Time) error {
+	if billing.Status != model.BillingStatusActive {
+		return fmt.Errorf("Billing %d is %s and cannot be written off.", billing.ID, billing.Status)
+	}
+	if billing.Outstanding <= 0 {
+		return fmt.Errorf("Billing %d has no outstanding to write off.", billing.ID)
+	}
+	dpd := 0
+	if oldest := oldestUnpaidDueDate(billing.Payments); oldest != nil {
+		dpd = utils.DaysPastDue(*oldest, now)
+	}
+	if dpd < minDaysPastDue {
+		return fmt.Errorf("Billing %d is %d days past due and cannot be written off before %d days.", billing.ID, dpd, minDaysPastDue)
+	}
+	return nil
+}
+
+func (svc *writeOffServiceImpl) RecordRecovery(ctx context.Context, billing *model.Billing, amount int) (*model.Recovery, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("Recovery amount must be positive.")
+	}
+	var recovery *model.Recovery
+	err := svc.billingRepo.WithDB().Transaction(func(trx *gorm.DB) error {
+		trxBillingRepo := svc.billingRepo.WithTransaction(trx)
+		locked, err := trxBillingRepo.FindByIDForUpdate(ctx, billing.ID)
+		if err != nil {
+			return err
+		}
+		if locked.Status != model.BillingStatusWrittenOff {
+			return fmt.Errorf("Billing %d has not been written off.", locked.ID)
+		}
+		remaining := locked.WrittenOffAmount - locked.RecoveredAmount
+		if amount > remaining {
+			return fmt.Errorf("Recovery amount exceeds the remaining written-off balance of %d.", remaining)
+		}
+		recovery = &model.Recovery{
+			BillingID:   locked.ID,
+			Amount:      amount,
+			RecoveredAt: time.Now(),
+		}
+		if err := svc.recoveryRepo.WithTransaction(trx).Create(ctx, recovery); err != nil {
+			return err
+		}
+		return trxBillingRepo.AddRecoveredAmount(ctx, locked.ID, amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recovery, nil
+}
+
+func (svc *writeOffServiceImpl) GetRecoveries(ctx context.Context, billingID uint) (*dto.RecoveriesResponse, error) {
+	billing, err := svc.billingRepo.FindByID(ctx, billingID)
+	if err != nil {
+		return nil, err
+	}
+	recoveries, err := svc.recoveryRepo.FindByBillingID(ctx, billing.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RecoveriesResponse{
+		BaseResponse: dto.BaseResponse{
+			BillingID:  billing.ID,
+			CustomerID: billing.CustomerID,
+			LoanID:     billing.LoanID,
+		},
+		WrittenOffAmount: billing.WrittenOffAmount,
+		RecoveredAmount:  billing.RecoveredAmount,
+		Recoveries:       recoveries,
+	}, nil
+}
+
+func (svc *writeOffServiceImpl) GetReport(ctx context.Context) (*dto.WriteOffReportResponse, error) {
+	summary, err := svc.billingRepo.SummarizeWriteOffs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.WriteOffReportResponse{
+		WrittenOffCount:  summary.WrittenOffCount,
+		WrittenOffAmount: summary.WrittenOffAmount,
+		RecoveredAmount:  summary.RecoveredAmount,
+		NetLoss:          summary.WrittenOffAmount - summary.RecoveredAmount,
+	}, nil
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWriteOff(t *testing.T) {
+	now := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	billing := &model.Billing{
+		ID:          1,
+		Outstanding: 220000,
+		Status:      model.BillingStatusActive,
+		Payments: []model.Payment{
+			{Week: 1, Paid: true, DueDate: now.AddDate(0, 0, -100)},
+			{Week: 2, DueDate: now.AddDate(0, 0, -93)},
+			{Week: 3, DueDate: now.AddDate(0, 0, -86)},
+		},
+	}
+
+	assert.NoError(t, validateWriteOff(billing, 90, now))
+	assert.EqualError(t, validateWriteOff(billing, 120, now), "Billing 1 is 94 days past due and cannot be written off before 120 days.")
+
+	billing.Payments[1].Paid = true
+	assert.EqualError(t, validateWriteOff(billing, 90, now), "Billing 1 is 87 days past due and cannot be written off before 90 days.")
+
+	billing.Status = model.BillingStatusClosed
+	assert.EqualError(t, validateWriteOff(billing, 0, now), "Billing 1 is CLOSED and cannot be written off.")
+
+	billing.Status = model.BillingStatusActive
+	billing.Outstanding = 0
+	assert.EqualError(t, validateWriteOff(billing, 0, now), "Billing 1 has no outstanding to write off.")
+}
@@ -0,0 +1,108 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var csvDateLayouts = []string{"2006-01-02", "02/01/2006", "02-01-2006", "20060102"}
+
+var csvColumns = map[string][]string{
+	"date":        {"date", "value_date", "transaction_date"},
+	"amount":      {"amount"},
+	"direction":   {"type", "direction", "dc"},
+	"reference":   {"reference", "ref"},
+	"description": {"description", "remark", "remarks"},
+}
+
+func ParseCSV(data []byte) (*Statement, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid CSV statement: %s.", err.Error())
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("CSV statement has no lines.")
+	}
+
+	index := make(map[string]int)
+	for i, header := range records[0] {
+		header = strings.ToLower(strings.TrimSpace(header))
+		for column, names := range csvColumns {
+			for _, name := range names {
+				if header == name {
+					index[column] = i
+				}
+			}
+		}
+	}
+	if _, ok := index["date"]; !ok {
+		return nil, fmt.Errorf("CSV statement requires a date column.")
+	}
+	if _, ok := index["amount"]; !ok {
+		return nil, fmt.Errorf("CSV statement requires an amount column.")
+	}
+
+	stmt := &Statement{Format: FormatCSV}
+	for n, record := range records[1:] {
+		field := func(column string) string {
+			if i, ok := index[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.Join(record, "") == "" {
+			continue
+		}
+		line, err := parseCSVLine(field)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid CSV statement row %d: %s.", n+2, err.Error())
+		}
+		line.LineNo = len(stmt.Lines) + 1
+		stmt.Lines = append(stmt.Lines, *line)
+	}
+	return stmt, nil
+}
+
+func parseCSVLine(field func(string) string) (*Line, error) {
+	var valueDate time.Time
+	var err error
+	for _, layout := range csvDateLayouts {
+		if valueDate, err = time.ParseInLocation(layout, field("date"), jakarta()); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q", field("date"))
+	}
+
+	rawAmount := field("amount")
+	direction := DirectionCredit
+	if strings.HasPrefix(rawAmount, "-") {
+		direction = DirectionDebit
+		rawAmount = rawAmount[1:]
+	}
+	switch strings.ToUpper(field("direction")) {
+	case "", "C", "CR", "CREDIT":
+	case "D", "DB", "DR", "DEBIT":
+		direction = DirectionDebit
+	default:
+		return nil, fmt.Errorf("invalid type %q", field("direction"))
+	}
+	amount, err := parseAmount(rawAmount, '.')
+	if err != nil {
+		return nil, err
+	}
+	return &Line{
+		ValueDate:   valueDate,
+		Direction:   direction,
+		Amount:      amount,
+		Reference:   field("reference"),
+		Description: field("description"),
+	}, nil
+}
@@ -0,0 +1,108 @@
+package statement
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var mt940LinePattern = regexp.MustCompile(`^(\d{6})(\d{4})?(R?[CD])([A-Z])?(\d+,\d*)([A-Z][A-Z0-9]{3})([^/]*)(?://(.*))?$`)
+
+func ParseMT940(data []byte) (*Statement, error) {
+	stmt := &Statement{Format: FormatMT940}
+	var tag, value string
+	flush := func() error {
+		if tag == "" {
+			return nil
+		}
+		defer func() { tag, value = "", "" }()
+		switch tag {
+		case "20":
+			stmt.Reference = strings.TrimSpace(value)
+		case "25":
+			stmt.AccountNumber = strings.TrimSpace(value)
+		case "61":
+			line, err := parseMT940Line(value)
+			if err != nil {
+				return err
+			}
+			line.LineNo = len(stmt.Lines) + 1
+			stmt.Lines = append(stmt.Lines, *line)
+		case "86":
+			if n := len(stmt.Lines); n > 0 {
+				last := &stmt.Lines[n-1]
+				last.Description = strings.TrimSpace(last.Description + " " + strings.Join(strings.Fields(value), " "))
+			}
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(text, ":") {
+			if end := strings.Index(text[1:], ":"); end > 0 {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+				tag, value = text[1:end+1], text[end+2:]
+				continue
+			}
+		}
+		if tag != "" && text != "-" && text != "-}" {
+			value += "\n" + text
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if len(stmt.Lines) == 0 {
+		return nil, fmt.Errorf("MT940 statement has no :61: lines.")
+	}
+	return stmt, nil
+}
+
+func parseMT940Line(value string) (*Line, error) {
+	first, supplementary, _ := strings.Cut(value, "\n")
+	m := mt940LinePattern.FindStringSubmatch(strings.TrimSpace(first))
+	if m == nil {
+		return nil, fmt.Errorf("Invalid MT940 :61: line %q.", first)
+	}
+	valueDate, err := time.ParseInLocation("060102", m[1], jakarta())
+	if err != nil {
+		return nil, fmt.Errorf("Invalid MT940 value date %q.", m[1])
+	}
+	amount, err := parseAmount(m[5], ',')
+	if err != nil {
+		return nil, fmt.Errorf("Invalid MT940 :61: line %q: %s.", first, err.Error())
+	}
+	direction := DirectionCredit
+	if strings.HasSuffix(m[3], "D") {
+		direction = DirectionDebit
+	}
+	reference := strings.TrimSpace(m[7])
+	if reference == "" || reference == "NONREF" {
+		reference = strings.TrimSpace(m[8])
+	}
+	return &Line{
+		ValueDate:   valueDate,
+		Direction:   direction,
+		Amount:      amount,
+		Reference:   reference,
+		Description: strings.TrimSpace(supplementary),
+	}, nil
+}
+
+func jakarta() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
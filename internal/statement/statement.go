@@ -0,0 +1,72 @@
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatMT940 = "MT940"
+	FormatCSV   = "CSV"
+
+	DirectionCredit = "CREDIT"
+	DirectionDebit  = "DEBIT"
+)
+
+type Line struct {
+	LineNo      int
+	ValueDate   time.Time
+	Direction   string
+	Amount      int
+	Reference   string
+	Description string
+}
+
+type Statement struct {
+	Format        string
+	AccountNumber string
+	Reference     string
+	Lines         []Line
+}
+
+func Parse(format string, data []byte) (*Statement, error) {
+	switch strings.ToUpper(format) {
+	case FormatMT940:
+		return ParseMT940(data)
+	case FormatCSV:
+		return ParseCSV(data)
+	}
+	return nil, fmt.Errorf("Unsupported statement format %q.", format)
+}
+
+func DetectFormat(fileName string, data []byte) string {
+	name := strings.ToLower(fileName)
+	switch {
+	case strings.HasSuffix(name, ".csv"):
+		return FormatCSV
+	case strings.HasSuffix(name, ".sta"), strings.HasSuffix(name, ".mt940"), strings.HasSuffix(name, ".940"):
+		return FormatMT940
+	case bytes.Contains(data, []byte(":61:")):
+		return FormatMT940
+	}
+	return FormatCSV
+}
+
+func parseAmount(s string, decimalSep byte) (int, error) {
+	s = strings.TrimSpace(s)
+	whole, frac, _ := strings.Cut(s, string(decimalSep))
+	if strings.Trim(frac, "0") != "" {
+		return 0, fmt.Errorf("amount %q has a fractional part", s)
+	}
+	if decimalSep == '.' {
+		whole = strings.ReplaceAll(whole, ",", "")
+	}
+	amount, err := strconv.Atoi(whole)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return amount, nil
+}
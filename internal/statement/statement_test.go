@@ -0,0 +1,84 @@
+package statement
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testMT940 = `{1:F01BANKIDJAXXXX0000000000}{2:I940BANKIDJAXXXXN}{4:
+:20:STMT20251201
+:25:1234567890
+:28C:00001/001
+:60F:C251130IDR1000000,00
+:61:2512011201C110000,00NTRFVA3935800000000015//BNK001
+:86:VA PAYMENT 3935800000000015
+ CUSTOMER 1
+:61:251201D25000,00NCHGNONREF//BNK002
+:86:ADMIN FEE
+:61:251201C50000,NTRFTRX-9
+:62F:C251201IDR1135000,00
+-}`
+
+func TestParseMT940(t *testing.T) {
+	stmt, err := ParseMT940([]byte(testMT940))
+	assert.NoError(t, err)
+	assert.Equal(t, "STMT20251201", stmt.Reference)
+	assert.Equal(t, "1234567890", stmt.AccountNumber)
+	assert.Len(t, stmt.Lines, 3)
+
+	line := stmt.Lines[0]
+	assert.Equal(t, 1, line.LineNo)
+	assert.Equal(t, DirectionCredit, line.Direction)
+	assert.Equal(t, 110000, line.Amount)
+	assert.Equal(t, "VA3935800000000015", line.Reference)
+	assert.Equal(t, "VA PAYMENT 3935800000000015 CUSTOMER 1", line.Description)
+	assert.Equal(t, "2025-12-01", line.ValueDate.Format(time.DateOnly))
+
+	assert.Equal(t, DirectionDebit, stmt.Lines[1].Direction)
+	assert.Equal(t, "BNK002", stmt.Lines[1].Reference)
+	assert.Equal(t, 50000, stmt.Lines[2].Amount)
+	assert.Equal(t, "TRX-9", stmt.Lines[2].Reference)
+}
+
+func TestParseMT940Invalid(t *testing.T) {
+	_, err := ParseMT940([]byte(":20:STMT\n:25:123\n"))
+	assert.Error(t, err)
+
+	_, err = ParseMT940([]byte(":20:STMT\n:61:251201C110000,50NTRFREF\n"))
+	assert.Error(t, err)
+}
+
+func TestParseCSV(t *testing.T) {
+	data := "Date,Description,Reference,Amount,Type\n" +
+		"2025-12-01,VA PAYMENT 3935800000000015,BNK001,\"110,000.00\",CR\n" +
+		"01/12/2025,ADMIN FEE,BNK002,25000,DR\n" +
+		",,,,\n" +
+		"2025-12-01,REFUND,BNK003,-5000,\n"
+	stmt, err := ParseCSV([]byte(data))
+	assert.NoError(t, err)
+	assert.Len(t, stmt.Lines, 3)
+	assert.Equal(t, 110000, stmt.Lines[0].Amount)
+	assert.Equal(t, DirectionCredit, stmt.Lines[0].Direction)
+	assert.Equal(t, "BNK001", stmt.Lines[0].Reference)
+	assert.Equal(t, DirectionDebit, stmt.Lines[1].Direction)
+	assert.Equal(t, "2025-12-01", stmt.Lines[1].ValueDate.Format(time.DateOnly))
+	assert.Equal(t, DirectionDebit, stmt.Lines[2].Direction)
+	assert.Equal(t, 5000, stmt.Lines[2].Amount)
+
+	_, err = ParseCSV([]byte("Reference,Amount\nBNK001,100\n"))
+	assert.Error(t, err)
+
+	_, err = ParseCSV([]byte("Date,Amount\nyesterday,100\n"))
+	assert.Error(t, err)
+}
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, FormatCSV, DetectFormat("statement.csv", nil))
+	assert.Equal(t, FormatMT940, DetectFormat("statement.sta", nil))
+	assert.Equal(t, FormatMT940, DetectFormat("upload", []byte(testMT940)))
+
+	_, err := Parse("pdf", nil)
+	assert.Error(t, err)
+}
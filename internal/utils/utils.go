@@ -48,3 +48,72 @@ func GenerateWeeklyDateRanges(start time.Time, n int) []WeeklyDateRange {
 	}
 	return ranges
 }
+
+func DaysPastDue(dueDate time.Time, now time.Time) int {
+	if !now.After(dueDate) {
+		return 0
+	}
+	return int(now.Sub(dueDate).Hours()/24) + 1
+}
+
+func AgingBucketLabels(boundaries []int) []string {
+	labels := []string{AgingBucketCurrent}
+	lower := 1
+	for _, upper := range boundaries {
+		labels = append(labels, fmt.Sprintf("%d-%d", lower, upper))
+		lower = upper + 1
+	}
+	return append(labels, fmt.Sprintf("%d+", lower))
+}
+
+func GetAgingBucket(dpd int, boundaries []int) string {
+	labels := AgingBucketLabels(boundaries)
+	if dpd <= 0 {
+		return labels[0]
+	}
+	for i, upper := range boundaries {
+		if dpd <= upper {
+			return labels[i+1]
+		}
+	}
+	return labels[len(labels)-1]
+}
+
+func LuhnCheckDigit(digits string) int {
+	sum := 0
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
+
+func IsValidLuhn(number string) bool {
+	if len(number) < 2 {
+		return false
+	}
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return LuhnCheckDigit(number[:len(number)-1]) == int(number[len(number)-1]-'0')
+}
+
+func GenerateVirtualAccountNumber(prefix string, id uint, length int) (string, error) {
+	width := length - len(prefix) - 1
+	body := strconv.FormatUint(uint64(id), 10)
+	if width <= 0 || len(body) > width {
+		return "", fmt.Errorf("id %d does not fit a %d-digit virtual account with prefix %s", id, length, prefix)
+	}
+	digits := prefix + fmt.Sprintf("%0*s", width, body)
+	return digits + strconv.Itoa(LuhnCheckDigit(digits)), nil
+}
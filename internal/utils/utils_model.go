@@ -2,6 +2,8 @@ package utils
 
 import "time"
 
+const AgingBucketCurrent = "current"
+
 type WeeklyDateRange struct {
 	StartOfWeek time.Time
 	EndOfWeek   time.Time
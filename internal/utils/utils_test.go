@@ -48,3 +48,72 @@ func TestGenerateWeeklyDateRanges(t *testing.T) {
 	expectedEndOfWeek = time.Date(2025, 9, 7, 23, 59, 59, 0, loc)
 	assert.Equal(t, expectedEndOfWeek, actual[4].EndOfWeek)
 }
+
+func TestDaysPastDue(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	dueDate := time.Date(2025, 8, 10, 23, 59, 59, 0, loc)
+
+	assert.Equal(t, 0, DaysPastDue(dueDate, time.Date(2025, 8, 10, 8, 0, 0, 0, loc)))
+	assert.Equal(t, 0, DaysPastDue(dueDate, dueDate))
+	assert.Equal(t, 1, DaysPastDue(dueDate, time.Date(2025, 8, 11, 8, 0, 0, 0, loc)))
+	assert.Equal(t, 2, DaysPastDue(dueDate, time.Date(2025, 8, 12, 0, 0, 0, 0, loc)))
+	assert.Equal(t, 31, DaysPastDue(dueDate, time.Date(2025, 9, 10, 8, 0, 0, 0, loc)))
+}
+
+func TestAgingBucketLabels(t *testing.T) {
+	tests := []struct {
+		name       string
+		boundaries []int
+		expected   []string
+	}{
+		{"default buckets", []int{30, 60, 90}, []string{"current", "1-30", "31-60", "61-90", "91+"}},
+		{"single boundary", []int{7}, []string{"current", "1-7", "8+"}},
+		{"no boundaries", nil, []string{"current", "1+"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, AgingBucketLabels(tt.boundaries))
+		})
+	}
+}
+
+func TestGetAgingBucket(t *testing.T) {
+	tests := []struct {
+		name       string
+		dpd        int
+		boundaries []int
+		expected   string
+	}{
+		{"not past due", 0, []int{30, 60, 90}, "current"},
+		{"first day past due", 1, []int{30, 60, 90}, "1-30"},
+		{"on a boundary", 30, []int{30, 60, 90}, "1-30"},
+		{"after a boundary", 31, []int{30, 60, 90}, "31-60"},
+		{"on the last boundary", 90, []int{30, 60, 90}, "61-90"},
+		{"after the last boundary", 91, []int{30, 60, 90}, "91+"},
+		{"no boundaries and not past due", 0, nil, "current"},
+		{"no boundaries and past due", 10, nil, "1+"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, GetAgingBucket(tt.dpd, tt.boundaries))
+		})
+	}
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	assert.Equal(t, 3, LuhnCheckDigit("7992739871"))
+	assert.True(t, IsValidLuhn("79927398713"))
+	assert.False(t, IsValidLuhn("79927398710"))
+	assert.False(t, IsValidLuhn("7992739871a"))
+}
+
+func TestGenerateVirtualAccountNumber(t *testing.T) {
+	number, err := GenerateVirtualAccountNumber("39358", 42, 16)
+	assert.NoError(t, err)
+	assert.Len(t, number, 16)
+	assert.Equal(t, "393580000000042", number[:15])
+	assert.True(t, IsValidLuhn(number))
+
+	_, err = GenerateVirtualAccountNumber("39358", 12345678901, 16)
+	assert.Error(t, err)
+}
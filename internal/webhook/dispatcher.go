@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/lock"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+)
+
+const (
+	HeaderDeliveryID = "X-Webhook-Id"
+	HeaderEvent      = "X-Webhook-Event"
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	HeaderSignature  = "X-Webhook-Signature"
+
+	dispatcherLockName = "webhook-dispatcher"
+)
+
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	locker lock.Locker
+	client *http.Client
+	cfg    *config.WebhookConfig
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewDispatcher(repo repository.WebhookRepository, locker lock.Locker, cfg *config.WebhookConfig) *Dispatcher {
+	return &Dispatcher{
+		repo:   repo,
+		locker: locker,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+	}
+}
+
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	return min(delay, max)
+}
+
+func (d *Dispatcher) Start() {
+	if !d.cfg.DispatcherEnabled {
+		log.Println("Webhook dispatcher is disabled.")
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.cfg.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.poll(ctx)
+			}
+		}
+	}()
+	log.Printf("Webhook dispatcher started, polling every %s", d.cfg.PollInterval)
+}
+
+func (d *Dispatcher) Stop() {
+	if d.cancel == nil {
+		return
+	}
+	d.cancel()
+	d.wg.Wait()
+	log.Println("Webhook dispatcher stopped.")
+}
+
+func (d *Dispatcher) poll(ctx context.Context) {
+	dispatcherLock, acquired, err := d.locker.TryLock(ctx, dispatcherLockName)
+	if err != nil {
+		log.Printf("Failed to acquire webhook dispatcher lock: %v", err)
+		return
+	}
+	if !acquired {
+		return
+	}
+	defer func() {
+		if err := dispatcherLock.Release(context.Background()); err != nil {
+			log.Printf("Failed to release webhook dispatcher lock: %v", err)
+		}
+	}()
+	if _, _, err := d.DispatchBatch(ctx); err != nil {
+		log.Printf("Failed to dispatch webhooks: %v", err)
+	}
+}
+
+func (d *Dispatcher) DispatchBatch(ctx context.Context) (delivered, failed int, err error) {
+	deliveries, err := d.repo.FindDueDeliveries(ctx, time.Now(), d.cfg.BatchSize)
+	if err != nil {
+		return 0, 0, err
+	}
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		statusCode, sendErr := d.send(ctx, delivery)
+		now := time.Now()
+		delivery.Attempts++
+		delivery.LastStatusCode = statusCode
+		if sendErr == nil {
+			delivery.Status = model.WebhookDeliveryStatusDelivered
+			delivery.DeliveredAt = &now
+			delivery.LastError = ""
+			delivered++
+		} else {
+			delivery.LastError = sendErr.Error()
+			if delivery.Attempts >= d.cfg.MaxAttempts || delivery.Subscription.ID == 0 {
+				delivery.Status = model.WebhookDeliveryStatusDead
+				log.Printf("Webhook delivery %d moved to dead letters after %d attempts: %v", delivery.ID, delivery.Attempts, sendErr)
+			} else {
+				delivery.NextAttemptAt = now.Add(Backoff(delivery.Attempts, d.cfg.BackoffBase, d.cfg.BackoffMax))
+			}
+			failed++
+		}
+		if err := d.repo.UpdateDelivery(ctx, delivery); err != nil {
+			return delivered, failed, err
+		}
+	}
+	return delivered, failed, nil
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *model.WebhookDelivery) (int, error) {
+	subscription := delivery.Subscription
+	if subscription.ID == 0 {
+		return 0, fmt.Errorf("subscription %d no longer exists", delivery.SubscriptionID)
+	}
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+	req.Header.Set(HeaderDeliveryID, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, delivery.Payload))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
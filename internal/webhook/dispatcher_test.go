@@ -0,0 +1,161 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/doddeeph/billing-engine/internal/config"
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/repository"
+	"github.com/doddeeph/billing-engine/pkg/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeWebhookRepository struct {
+	repository.WebhookRepository
+	deliveries []model.WebhookDelivery
+}
+
+func (r *fakeWebhookRepository) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	var due []model.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == model.WebhookDeliveryStatusPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeWebhookRepository) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	r.deliveries[delivery.ID-1] = *delivery
+	return nil
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func newReceiver(t *testing.T, statusCode *int) (*httptest.Server, *[]receivedWebhook) {
+	var received []receivedWebhook
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		received = append(received, receivedWebhook{header: r.Header.Clone(), body: body})
+		w.WriteHeader(*statusCode)
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func newDelivery(url string) model.WebhookDelivery {
+	return model.WebhookDelivery{
+		ID:             1,
+		SubscriptionID: 1,
+		OutboxEventID:  1,
+		EventType:      events.TypePaymentApplied,
+		Payload:        []byte(`{"id":1,"type":"payment.applied.v1"}`),
+		Status:         model.WebhookDeliveryStatusPending,
+		NextAttemptAt:  time.Now().Add(-time.Second),
+		Subscription:   model.WebhookSubscription{ID: 1, URL: url, Secret: "s3cret", Active: true},
+	}
+}
+
+func testWebhookConfig() *config.WebhookConfig {
+	return &config.WebhookConfig{
+		BatchSize:   10,
+		MaxAttempts: 3,
+		BackoffBase: time.Minute,
+		BackoffMax:  time.Hour,
+		Timeout:     time.Second,
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	signature := Sign("s3cret", 1700000000, body)
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.True(t, Verify("s3cret", 1700000000, body, signature))
+	assert.False(t, Verify("other", 1700000000, body, signature))
+	assert.False(t, Verify("s3cret", 1700000001, body, signature))
+	assert.False(t, Verify("s3cret", 1700000000, []byte(`{"id":2}`), signature))
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Minute, Backoff(1, time.Minute, time.Hour))
+	assert.Equal(t, 2*time.Minute, Backoff(2, time.Minute, time.Hour))
+	assert.Equal(t, 8*time.Minute, Backoff(4, time.Minute, time.Hour))
+	assert.Equal(t, time.Hour, Backoff(10, time.Minute, time.Hour))
+}
+
+func TestDispatchBatch_DeliversSignedPayload(t *testing.T) {
+	statusCode := http.StatusOK
+	server, received := newReceiver(t, &statusCode)
+	repo := &fakeWebhookRepository{deliveries: []model.WebhookDelivery{newDelivery(server.URL)}}
+	dispatcher := NewDispatcher(repo, nil, testWebhookConfig())
+
+	delivered, failed, err := dispatcher.DispatchBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, 0, failed)
+
+	assert.Len(t, *received, 1)
+	webhook := (*received)[0]
+	assert.Equal(t, `{"id":1,"type":"payment.applied.v1"}`, string(webhook.body))
+	assert.Equal(t, events.TypePaymentApplied, webhook.header.Get(HeaderEvent))
+	assert.Equal(t, "1", webhook.header.Get(HeaderDeliveryID))
+	timestamp, err := strconv.ParseInt(webhook.header.Get(HeaderTimestamp), 10, 64)
+	assert.NoError(t, err)
+	assert.True(t, Verify("s3cret", timestamp, webhook.body, webhook.header.Get(HeaderSignature)))
+
+	assert.Equal(t, model.WebhookDeliveryStatusDelivered, repo.deliveries[0].Status)
+	assert.Equal(t, 1, repo.deliveries[0].Attempts)
+	assert.Equal(t, http.StatusOK, repo.deliveries[0].LastStatusCode)
+	assert.NotNil(t, repo.deliveries[0].DeliveredAt)
+}
+
+func TestDispatchBatch_RetriesWithBackoffThenDeadLetters(t *testing.T) {
+	statusCode := http.StatusInternalServerError
+	server, received := newReceiver(t, &statusCode)
+	repo := &fakeWebhookRepository{deliveries: []model.WebhookDelivery{newDelivery(server.URL)}}
+	dispatcher := NewDispatcher(repo, nil, testWebhookConfig())
+
+	delivered, failed, err := dispatcher.DispatchBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, model.WebhookDeliveryStatusPending, repo.deliveries[0].Status)
+	assert.Equal(t, http.StatusInternalServerError, repo.deliveries[0].LastStatusCode)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), repo.deliveries[0].NextAttemptAt, 5*time.Second)
+
+	delivered, failed, err = dispatcher.DispatchBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, delivered+failed)
+
+	for attempt := 2; attempt <= 3; attempt++ {
+		repo.deliveries[0].NextAttemptAt = time.Now().Add(-time.Second)
+		_, failed, err = dispatcher.DispatchBatch(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, failed)
+	}
+	assert.Equal(t, model.WebhookDeliveryStatusDead, repo.deliveries[0].Status)
+	assert.Equal(t, 3, repo.deliveries[0].Attempts)
+	assert.Len(t, *received, 3)
+}
+
+func TestDispatchBatch_DeletedSubscriptionIsDeadLettered(t *testing.T) {
+	delivery := newDelivery("")
+	delivery.Subscription = model.WebhookSubscription{}
+	repo := &fakeWebhookRepository{deliveries: []model.WebhookDelivery{delivery}}
+	dispatcher := NewDispatcher(repo, nil, testWebhookConfig())
+
+	_, failed, err := dispatcher.DispatchBatch(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, model.WebhookDeliveryStatusDead, repo.deliveries[0].Status)
+}
@@ -0,0 +1,23 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/outbox"
+	"github.com/doddeeph/billing-engine/internal/service"
+)
+
+const PublisherName = "webhook"
+
+type publisher struct {
+	svc service.WebhookService
+}
+
+func NewPublisher(svc service.WebhookService) outbox.Publisher {
+	return &publisher{svc: svc}
+}
+
+func (p *publisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	return p.svc.Enqueue(ctx, event)
+}
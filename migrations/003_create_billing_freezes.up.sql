@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS billing_freezes (
+    id SERIAL PRIMARY KEY,
+    billing_id INTEGER NOT NULL REFERENCES billings(id) ON DELETE CASCADE,
+    start_date TIMESTAMPTZ NOT NULL,
+    end_date TIMESTAMPTZ NOT NULL,
+    reason TEXT NOT NULL,
+    shifted_weeks INTEGER NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_billing_freezes_billing_id ON billing_freezes (billing_id);
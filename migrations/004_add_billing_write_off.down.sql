@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS recoveries;
+ALTER TABLE billings
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS written_off_amount,
+    DROP COLUMN IF EXISTS recovered_amount,
+    DROP COLUMN IF EXISTS write_off_reason,
+    DROP COLUMN IF EXISTS written_off_at;
@@ -0,0 +1,18 @@
+ALTER TABLE billings
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE',
+    ADD COLUMN IF NOT EXISTS written_off_amount INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS recovered_amount INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS write_off_reason TEXT,
+    ADD COLUMN IF NOT EXISTS written_off_at TIMESTAMPTZ;
+UPDATE billings SET status = 'CLOSED' WHERE outstanding = 0;
+
+CREATE TABLE IF NOT EXISTS recoveries (
+    id SERIAL PRIMARY KEY,
+    billing_id INTEGER NOT NULL REFERENCES billings(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL,
+    recovered_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_recoveries_billing_id ON recoveries (billing_id);
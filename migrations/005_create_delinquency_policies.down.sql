@@ -0,0 +1,4 @@
+ALTER TABLE billings
+    DROP COLUMN IF EXISTS delinquency_policy_id,
+    DROP COLUMN IF EXISTS product_code;
+DROP TABLE IF EXISTS delinquency_policies;
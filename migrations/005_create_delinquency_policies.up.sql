@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS delinquency_policies (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    product_code VARCHAR(50),
+    rule VARCHAR(30) NOT NULL,
+    threshold INTEGER NOT NULL,
+    window_weeks INTEGER NOT NULL DEFAULT 0,
+    cure_rule VARCHAR(30) NOT NULL DEFAULT 'ARREARS_CLEARED',
+    cure_payments INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT uq_delinquency_policies_code UNIQUE (code),
+    CONSTRAINT uq_delinquency_policies_product_code UNIQUE (product_code)
+);
+
+ALTER TABLE billings
+    ADD COLUMN IF NOT EXISTS product_code VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS delinquency_policy_id INTEGER REFERENCES delinquency_policies(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_billings_product_code ON billings (product_code);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS delinquency_histories;
+ALTER TABLE billings
+    DROP COLUMN IF EXISTS delinquency_status;
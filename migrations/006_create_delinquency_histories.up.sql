@@ -0,0 +1,18 @@
+ALTER TABLE billings
+    ADD COLUMN IF NOT EXISTS delinquency_status VARCHAR(20) NOT NULL DEFAULT 'CURRENT';
+
+CREATE TABLE IF NOT EXISTS delinquency_histories (
+    id SERIAL PRIMARY KEY,
+    billing_id INTEGER NOT NULL REFERENCES billings(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    policy_code VARCHAR(50) NOT NULL,
+    rule VARCHAR(30),
+    reason TEXT,
+    source VARCHAR(20) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_delinquency_histories_billing_id ON delinquency_histories (billing_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS job_runs;
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS late_fee,
+    DROP COLUMN IF EXISTS overdue;
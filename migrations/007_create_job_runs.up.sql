@@ -0,0 +1,18 @@
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS overdue BOOLEAN DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS late_fee INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS job_runs (
+    id SERIAL PRIMARY KEY,
+    job_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ,
+    processed INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_name ON job_runs (job_name);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS gateway_callbacks (
+    id SERIAL PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    external_id VARCHAR(255) NOT NULL,
+    billing_id INTEGER,
+    amount INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    error TEXT,
+    payment_id INTEGER,
+    recovery_id INTEGER,
+    payload JSONB NOT NULL,
+    paid_at TIMESTAMPTZ,
+    received_at TIMESTAMPTZ NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_gateway_callbacks_provider_external_id ON gateway_callbacks (provider, external_id);
+CREATE INDEX IF NOT EXISTS idx_gateway_callbacks_billing_id ON gateway_callbacks (billing_id);
+CREATE INDEX IF NOT EXISTS idx_gateway_callbacks_status ON gateway_callbacks (status);
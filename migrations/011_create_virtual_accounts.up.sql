@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS virtual_accounts (
+    id SERIAL PRIMARY KEY,
+    number VARCHAR(32) NOT NULL,
+    bank_code VARCHAR(20) NOT NULL,
+    billing_id INTEGER NOT NULL REFERENCES billings(id),
+    customer_id INTEGER NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_virtual_accounts_number ON virtual_accounts (number);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_virtual_accounts_billing_bank ON virtual_accounts (billing_id, bank_code);
+CREATE INDEX IF NOT EXISTS idx_virtual_accounts_customer_id ON virtual_accounts (customer_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS qris_payments;
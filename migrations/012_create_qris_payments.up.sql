@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS qris_payments (
+    id SERIAL PRIMARY KEY,
+    reference VARCHAR(25) NOT NULL,
+    billing_id INTEGER NOT NULL REFERENCES billings(id),
+    customer_id INTEGER NOT NULL,
+    amount INTEGER NOT NULL,
+    week INTEGER,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_qris_payments_reference ON qris_payments (reference);
+CREATE INDEX IF NOT EXISTS idx_qris_payments_billing_id ON qris_payments (billing_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS statement_lines;
+DROP TABLE IF EXISTS statement_imports;
@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS statement_imports (
+    id SERIAL PRIMARY KEY,
+    format VARCHAR(10) NOT NULL,
+    file_name VARCHAR(255),
+    checksum VARCHAR(64) NOT NULL,
+    account_number VARCHAR(50),
+    statement_reference VARCHAR(50),
+    total_lines INTEGER NOT NULL DEFAULT 0,
+    matched_lines INTEGER NOT NULL DEFAULT 0,
+    applied_lines INTEGER NOT NULL DEFAULT 0,
+    mismatched_lines INTEGER NOT NULL DEFAULT 0,
+    unmatched_lines INTEGER NOT NULL DEFAULT 0,
+    ignored_lines INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_statement_imports_checksum ON statement_imports (checksum);
+
+CREATE TABLE IF NOT EXISTS statement_lines (
+    id SERIAL PRIMARY KEY,
+    import_id INTEGER NOT NULL REFERENCES statement_imports(id),
+    line_no INTEGER NOT NULL,
+    value_date TIMESTAMPTZ NOT NULL,
+    direction VARCHAR(10) NOT NULL,
+    amount INTEGER NOT NULL,
+    reference VARCHAR(255),
+    description TEXT,
+    status VARCHAR(20) NOT NULL,
+    matched_by VARCHAR(20),
+    billing_id INTEGER,
+    payment_id INTEGER,
+    recovery_id INTEGER,
+    note TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_statement_lines_import_id ON statement_lines (import_id);
+CREATE INDEX IF NOT EXISTS idx_statement_lines_status ON statement_lines (status);
+CREATE INDEX IF NOT EXISTS idx_statement_lines_billing_id ON statement_lines (billing_id);
+CREATE INDEX IF NOT EXISTS idx_statement_lines_payment_id ON statement_lines (payment_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS payment_batch_rows;
+DROP TABLE IF EXISTS payment_batches;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS payment_batches (
+    id SERIAL PRIMARY KEY,
+    format VARCHAR(10) NOT NULL,
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL,
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    applied_rows INTEGER NOT NULL DEFAULT 0,
+    valid_rows INTEGER NOT NULL DEFAULT 0,
+    duplicate_rows INTEGER NOT NULL DEFAULT 0,
+    rejected_rows INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_payment_batches_status ON payment_batches (status);
+
+CREATE TABLE IF NOT EXISTS payment_batch_rows (
+    id SERIAL PRIMARY KEY,
+    batch_id INTEGER NOT NULL REFERENCES payment_batches(id),
+    row_no INTEGER NOT NULL,
+    billing_id INTEGER,
+    week INTEGER,
+    amount INTEGER,
+    reference VARCHAR(255),
+    status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    payment_id INTEGER,
+    recovery_id INTEGER,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_payment_batch_rows_batch_id ON payment_batch_rows (batch_id);
+CREATE INDEX IF NOT EXISTS idx_payment_batch_rows_reference ON payment_batch_rows (reference);
+CREATE INDEX IF NOT EXISTS idx_payment_batch_rows_status ON payment_batch_rows (status);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_batch_rows_applied_reference ON payment_batch_rows (reference) WHERE status = 'APPLIED' AND deleted_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS loan_import_rows;
+DROP TABLE IF EXISTS loan_imports;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS loan_imports (
+    id SERIAL PRIMARY KEY,
+    status VARCHAR(20) NOT NULL,
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    imported_rows INTEGER NOT NULL DEFAULT 0,
+    skipped_rows INTEGER NOT NULL DEFAULT 0,
+    failed_rows INTEGER NOT NULL DEFAULT 0,
+    pending_rows INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_loan_imports_status ON loan_imports (status);
+
+CREATE TABLE IF NOT EXISTS loan_import_rows (
+    id SERIAL PRIMARY KEY,
+    import_id INTEGER NOT NULL REFERENCES loan_imports(id),
+    row_no INTEGER NOT NULL,
+    loan_id INTEGER,
+    status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    billing_id INTEGER,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_loan_import_rows_import_id ON loan_import_rows (import_id);
+CREATE INDEX IF NOT EXISTS idx_loan_import_rows_loan_id ON loan_import_rows (loan_id);
+CREATE INDEX IF NOT EXISTS idx_loan_import_rows_status ON loan_import_rows (status);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_billings_customer_id;
+DROP TABLE IF EXISTS customer_exposure_limits;
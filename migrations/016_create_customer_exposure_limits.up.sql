@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS customer_exposure_limits (
+    customer_id INTEGER PRIMARY KEY,
+    exposure_limit INTEGER NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_billings_customer_id ON billings (customer_id);
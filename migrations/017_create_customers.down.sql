@@ -0,0 +1,2 @@
+ALTER TABLE billings DROP CONSTRAINT IF EXISTS fk_billings_customer;
+DROP TABLE IF EXISTS customers;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS customers (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(255),
+    phone VARCHAR(20),
+    email VARCHAR(255),
+    preferred_language VARCHAR(10) NOT NULL DEFAULT '',
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    sms_opt_in BOOLEAN NOT NULL DEFAULT FALSE,
+    email_opt_in BOOLEAN NOT NULL DEFAULT FALSE,
+    whatsapp_opt_in BOOLEAN NOT NULL DEFAULT FALSE,
+    synced_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_customers_phone ON customers (phone);
+CREATE INDEX IF NOT EXISTS idx_customers_email ON customers (email);
+
+INSERT INTO customers (id)
+SELECT DISTINCT customer_id FROM billings
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE billings ADD CONSTRAINT fk_billings_customer FOREIGN KEY (customer_id) REFERENCES customers(id);
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS reminders (
+    id SERIAL PRIMARY KEY,
+    billing_id INTEGER NOT NULL REFERENCES billings(id),
+    payment_id INTEGER NOT NULL REFERENCES payments(id),
+    customer_id INTEGER NOT NULL REFERENCES customers(id),
+    week INTEGER NOT NULL,
+    due_date TIMESTAMPTZ NOT NULL,
+    day_offset INTEGER NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    language VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'SCHEDULED',
+    scheduled_at TIMESTAMPTZ NOT NULL,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    recipient VARCHAR(255),
+    subject TEXT,
+    body TEXT,
+    provider_ref VARCHAR(255),
+    last_error TEXT,
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reminders_payment_schedule ON reminders (payment_id, due_date, day_offset, channel);
+CREATE INDEX IF NOT EXISTS idx_reminders_billing_id ON reminders (billing_id);
+CREATE INDEX IF NOT EXISTS idx_reminders_customer_id ON reminders (customer_id);
+CREATE INDEX IF NOT EXISTS idx_reminders_status ON reminders (status);
+CREATE INDEX IF NOT EXISTS idx_reminders_next_attempt_at ON reminders (next_attempt_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS dunning_logs;
+DROP TABLE IF EXISTS dunning_steps;
+DROP TABLE IF EXISTS dunning_strategies;
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS dunning_strategies (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    product_code VARCHAR(50),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT uq_dunning_strategies_code UNIQUE (code),
+    CONSTRAINT uq_dunning_strategies_product_code UNIQUE (product_code)
+);
+
+CREATE TABLE IF NOT EXISTS dunning_steps (
+    id SERIAL PRIMARY KEY,
+    strategy_id INTEGER NOT NULL REFERENCES dunning_strategies(id) ON DELETE CASCADE,
+    days_past_due INTEGER NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_dunning_steps_strategy_id ON dunning_steps (strategy_id);
+
+CREATE TABLE IF NOT EXISTS dunning_logs (
+    id SERIAL PRIMARY KEY,
+    billing_id INTEGER NOT NULL REFERENCES billings(id),
+    cycle INTEGER NOT NULL,
+    strategy_code VARCHAR(50) NOT NULL,
+    step_days_past_due INTEGER NOT NULL DEFAULT 0,
+    action VARCHAR(20),
+    days_past_due INTEGER NOT NULL,
+    overdue_amount INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL,
+    recipient VARCHAR(255),
+    subject TEXT,
+    body TEXT,
+    provider_ref VARCHAR(255),
+    reason TEXT,
+    triggered_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_dunning_logs_billing_id ON dunning_logs (billing_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS contact_attempts;
+DROP TABLE IF EXISTS collection_cases;
+DROP TABLE IF EXISTS collection_agents;
+ALTER TABLE customers DROP COLUMN IF EXISTS region;
@@ -0,0 +1,52 @@
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS region VARCHAR(50) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS collection_agents (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    region VARCHAR(50) NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_assigned_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_collection_agents_region ON collection_agents (region);
+
+CREATE TABLE IF NOT EXISTS collection_cases (
+    id SERIAL PRIMARY KEY,
+    billing_id INTEGER NOT NULL REFERENCES billings(id),
+    customer_id INTEGER NOT NULL REFERENCES customers(id),
+    agent_id INTEGER REFERENCES collection_agents(id),
+    region VARCHAR(50) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN',
+    opened_at TIMESTAMPTZ NOT NULL,
+    assigned_at TIMESTAMPTZ,
+    closed_at TIMESTAMPTZ,
+    close_reason TEXT,
+    last_contact_at TIMESTAMPTZ,
+    last_outcome VARCHAR(30),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_collection_cases_open_billing ON collection_cases (billing_id) WHERE status = 'OPEN';
+CREATE INDEX IF NOT EXISTS idx_collection_cases_billing_id ON collection_cases (billing_id);
+CREATE INDEX IF NOT EXISTS idx_collection_cases_customer_id ON collection_cases (customer_id);
+CREATE INDEX IF NOT EXISTS idx_collection_cases_agent_id ON collection_cases (agent_id);
+CREATE INDEX IF NOT EXISTS idx_collection_cases_status ON collection_cases (status);
+
+CREATE TABLE IF NOT EXISTS contact_attempts (
+    id SERIAL PRIMARY KEY,
+    case_id INTEGER NOT NULL REFERENCES collection_cases(id),
+    agent_id INTEGER REFERENCES collection_agents(id),
+    channel VARCHAR(20) NOT NULL,
+    outcome VARCHAR(30) NOT NULL,
+    notes TEXT,
+    attempted_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_contact_attempts_case_id ON contact_attempts (case_id);
+CREATE INDEX IF NOT EXISTS idx_contact_attempts_agent_id ON contact_attempts (agent_id);
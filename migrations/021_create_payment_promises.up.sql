@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS payment_promises (
+    id SERIAL PRIMARY KEY,
+    billing_id INTEGER NOT NULL REFERENCES billings(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL,
+    promise_date TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    paid_amount INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    notes TEXT,
+    promised_at TIMESTAMPTZ NOT NULL,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_promises_pending_billing ON payment_promises (billing_id) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_payment_promises_billing_id ON payment_promises (billing_id);
+CREATE INDEX IF NOT EXISTS idx_payment_promises_expires_at ON payment_promises (expires_at);
+CREATE INDEX IF NOT EXISTS idx_payment_promises_status ON payment_promises (status);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_job_runs_completed_slot;
+
+ALTER TABLE job_runs DROP COLUMN IF EXISTS slot;
@@ -0,0 +1,3 @@
+ALTER TABLE job_runs ADD COLUMN IF NOT EXISTS slot TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_runs_completed_slot ON job_runs (job_name, slot) WHERE status = 'SUCCESS';
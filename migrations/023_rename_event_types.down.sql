@@ -0,0 +1,58 @@
+UPDATE outbox_events
+SET event_type = 'billing.created',
+    payload = payload - 'createdAt'
+WHERE status = 'PENDING' AND event_type = 'billing.created.v1';
+
+UPDATE outbox_events
+SET event_type = 'payment.received',
+    payload = (payload - 'paidAt') || jsonb_build_object('paidDate', payload->'paidAt')
+WHERE status = 'PENDING' AND event_type = 'payment.applied.v1';
+
+UPDATE outbox_events
+SET event_type = 'billing.closed'
+WHERE status = 'PENDING' AND event_type = 'billing.closed.v1';
+
+UPDATE outbox_events
+SET event_type = 'billing.delinquency_changed',
+    payload = jsonb_build_object(
+        'billingId', payload->'billingId',
+        'customerId', payload->'customerId',
+        'loanId', payload->'loanId',
+        'fromStatus', 'CURRENT',
+        'toStatus', 'DELINQUENT',
+        'policyCode', payload->'policyCode',
+        'rule', payload->'rule',
+        'reason', payload->'reason',
+        'source', payload->'source',
+        'changedAt', payload->'delinquentAt'
+    )
+WHERE status = 'PENDING' AND event_type = 'billing.delinquent.v1';
+
+UPDATE outbox_events
+SET event_type = 'billing.delinquency_changed',
+    payload = jsonb_build_object(
+        'billingId', payload->'billingId',
+        'customerId', payload->'customerId',
+        'loanId', payload->'loanId',
+        'fromStatus', 'DELINQUENT',
+        'toStatus', 'CURRENT',
+        'policyCode', payload->'policyCode',
+        'source', payload->'source',
+        'changedAt', payload->'curedAt'
+    )
+WHERE status = 'PENDING' AND event_type = 'billing.cured.v1';
+
+UPDATE webhook_subscriptions
+SET event_types = (
+    SELECT string_agg(DISTINCT renamed, ',')
+    FROM unnest(string_to_array(event_types, ',')) AS new_type,
+    unnest(CASE new_type
+        WHEN 'billing.created.v1' THEN ARRAY['billing.created']
+        WHEN 'payment.applied.v1' THEN ARRAY['payment.received']
+        WHEN 'billing.closed.v1' THEN ARRAY['billing.closed']
+        WHEN 'billing.delinquent.v1' THEN ARRAY['billing.delinquency_changed']
+        WHEN 'billing.cured.v1' THEN ARRAY['billing.delinquency_changed']
+        ELSE ARRAY[new_type]
+    END) AS renamed
+)
+WHERE event_types <> '';
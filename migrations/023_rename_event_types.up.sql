@@ -0,0 +1,53 @@
+UPDATE outbox_events
+SET event_type = 'billing.created.v1',
+    payload = payload || jsonb_build_object('createdAt', occurred_at)
+WHERE status = 'PENDING' AND event_type = 'billing.created';
+
+UPDATE outbox_events
+SET event_type = 'payment.applied.v1',
+    payload = (payload - 'paidDate') || jsonb_build_object('paidAt', payload->'paidDate')
+WHERE status = 'PENDING' AND event_type = 'payment.received';
+
+UPDATE outbox_events
+SET event_type = 'billing.closed.v1'
+WHERE status = 'PENDING' AND event_type = 'billing.closed';
+
+UPDATE outbox_events
+SET event_type = 'billing.delinquent.v1',
+    payload = jsonb_build_object(
+        'billingId', payload->'billingId',
+        'customerId', payload->'customerId',
+        'loanId', payload->'loanId',
+        'policyCode', payload->'policyCode',
+        'rule', COALESCE(NULLIF(payload->>'rule', ''), 'UNKNOWN'),
+        'reason', COALESCE(payload->>'reason', ''),
+        'source', payload->'source',
+        'delinquentAt', payload->'changedAt'
+    )
+WHERE status = 'PENDING' AND event_type = 'billing.delinquency_changed' AND payload->>'toStatus' = 'DELINQUENT';
+
+UPDATE outbox_events
+SET event_type = 'billing.cured.v1',
+    payload = jsonb_build_object(
+        'billingId', payload->'billingId',
+        'customerId', payload->'customerId',
+        'loanId', payload->'loanId',
+        'policyCode', payload->'policyCode',
+        'source', payload->'source',
+        'curedAt', payload->'changedAt'
+    )
+WHERE status = 'PENDING' AND event_type = 'billing.delinquency_changed';
+
+UPDATE webhook_subscriptions
+SET event_types = (
+    SELECT string_agg(DISTINCT renamed, ',')
+    FROM unnest(string_to_array(event_types, ',')) AS old_type,
+    unnest(CASE old_type
+        WHEN 'billing.created' THEN ARRAY['billing.created.v1']
+        WHEN 'payment.received' THEN ARRAY['payment.applied.v1']
+        WHEN 'billing.closed' THEN ARRAY['billing.closed.v1']
+        WHEN 'billing.delinquency_changed' THEN ARRAY['billing.delinquent.v1', 'billing.cured.v1']
+        ELSE ARRAY[old_type]
+    END) AS renamed
+)
+WHERE event_types <> '';
@@ -0,0 +1 @@
+ALTER TABLE payment_batches DROP COLUMN IF EXISTS lease_expires_at;
@@ -0,0 +1 @@
+ALTER TABLE payment_batches ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ;
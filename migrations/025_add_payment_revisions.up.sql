@@ -0,0 +1 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 0;
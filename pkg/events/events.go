@@ -0,0 +1,88 @@
+// Package events defines the versioned CloudEvents 1.0 contract for events
+// published by the billing engine, for use by producers and consumers alike.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	SpecVersion     = "1.0"
+	Source          = "/billing-engine"
+	DataContentType = "application/json"
+	SchemaBaseURL   = "https://raw.githubusercontent.com/doddeeph/billing-engine/main/pkg/events/schemas/"
+
+	TypeBillingCreated    = "billing.created.v1"
+	TypePaymentApplied    = "payment.applied.v1"
+	TypePaymentReversed   = "payment.reversed.v1"
+	TypeBillingDelinquent = "billing.delinquent.v1"
+	TypeBillingCured      = "billing.cured.v1"
+	TypeBillingClosed     = "billing.closed.v1"
+)
+
+var types = []string{
+	TypeBillingCreated,
+	TypePaymentApplied,
+	TypePaymentReversed,
+	TypeBillingDelinquent,
+	TypeBillingCured,
+	TypeBillingClosed,
+}
+
+type Event struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	DataSchema      string          `json:"dataschema"`
+	Data            json.RawMessage `json:"data"`
+}
+
+func Types() []string {
+	return append([]string(nil), types...)
+}
+
+func SchemaURL(eventType string) string {
+	return SchemaBaseURL + eventType + ".json"
+}
+
+func BillingSubject(billingID uint) string {
+	return fmt.Sprintf("billings/%d", billingID)
+}
+
+func New(eventType, id, subject string, occurredAt time.Time, data json.RawMessage) Event {
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              id,
+		Source:          Source,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            occurredAt,
+		DataContentType: DataContentType,
+		DataSchema:      SchemaURL(eventType),
+		Data:            data,
+	}
+}
+
+func Parse(b []byte) (*Event, error) {
+	if err := ValidateEnvelope(b); err != nil {
+		return nil, err
+	}
+	var event Event
+	if err := json.Unmarshal(b, &event); err != nil {
+		return nil, err
+	}
+	if err := ValidateData(event.Type, event.Data); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (e *Event) DecodeData(v any) error {
+	return json.Unmarshal(e.Data, v)
+}
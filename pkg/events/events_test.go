@@ -0,0 +1,101 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func samplePayloads() map[string]any {
+	now := time.Date(2025, 12, 1, 9, 30, 0, 0, time.UTC)
+	return map[string]any{
+		TypeBillingCreated: BillingCreated{
+			BillingID: 1, CustomerID: 1, LoanID: 1001, LoanAmount: 5000000, LoanWeeks: 50,
+			LoanInterest: 10, Outstanding: 5500000, ProductCode: "KUR", CreatedAt: now,
+		},
+		TypePaymentApplied: PaymentApplied{
+			BillingID: 1, CustomerID: 1, LoanID: 1001, Week: 1, Amount: 110000, Outstanding: 5390000, PaidAt: now,
+		},
+		TypePaymentReversed: PaymentReversed{
+			BillingID: 1, CustomerID: 1, LoanID: 1001, Week: 1, Amount: 110000, Outstanding: 5500000,
+			Reason: "chargeback", ReversedAt: now,
+		},
+		TypeBillingDelinquent: BillingDelinquent{
+			BillingID: 1, CustomerID: 1, LoanID: 1001, PolicyCode: "DEFAULT", Rule: "CONSECUTIVE_MISSES",
+			Reason: "2 consecutive missed installments, threshold 2", Source: "EVALUATOR", DelinquentAt: now,
+		},
+		TypeBillingCured: BillingCured{
+			BillingID: 1, CustomerID: 1, LoanID: 1001, PolicyCode: "DEFAULT", Source: "PAYMENT", CuredAt: now,
+		},
+		TypeBillingClosed: BillingClosed{
+			BillingID: 1, CustomerID: 1, LoanID: 1001, ClosedAt: now,
+		},
+	}
+}
+
+func TestEveryTypeHasSchemaAndPayload(t *testing.T) {
+	payloads := samplePayloads()
+	assert.Len(t, payloads, len(Types()))
+	for _, eventType := range Types() {
+		_, err := Schema(eventType)
+		assert.NoError(t, err, eventType)
+		assert.Contains(t, payloads, eventType)
+	}
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	for eventType, payload := range samplePayloads() {
+		data, err := json.Marshal(payload)
+		assert.NoError(t, err)
+		assert.NoError(t, ValidateData(eventType, data), eventType)
+
+		b, err := json.Marshal(New(eventType, "42", BillingSubject(1), time.Now(), data))
+		assert.NoError(t, err)
+		event, err := Parse(b)
+		assert.NoError(t, err, eventType)
+		assert.Equal(t, SpecVersion, event.SpecVersion)
+		assert.Equal(t, eventType, event.Type)
+		assert.Equal(t, "billings/1", event.Subject)
+		assert.Equal(t, SchemaBaseURL+eventType+".json", event.DataSchema)
+	}
+}
+
+func TestParse_DecodeData(t *testing.T) {
+	data, _ := json.Marshal(samplePayloads()[TypePaymentApplied])
+	b, _ := json.Marshal(New(TypePaymentApplied, "7", BillingSubject(1), time.Now(), data))
+	event, err := Parse(b)
+	assert.NoError(t, err)
+
+	var applied PaymentApplied
+	assert.NoError(t, event.DecodeData(&applied))
+	assert.Equal(t, 110000, applied.Amount)
+	assert.Equal(t, 5390000, applied.Outstanding)
+}
+
+func TestValidateData_RejectsInvalidPayload(t *testing.T) {
+	assert.Error(t, ValidateData(TypePaymentApplied, []byte(`{"billingId":1,"week":1}`)))
+	assert.Error(t, ValidateData(TypeBillingDelinquent, []byte(`{"billingId":1,"customerId":1,"loanId":1,"policyCode":"DEFAULT","rule":"X","reason":"","source":"MANUAL","delinquentAt":"2025-12-01T09:30:00Z"}`)))
+	assert.Error(t, ValidateData(TypeBillingClosed, []byte(`{"billingId":1,"customerId":1,"loanId":1,"closedAt":"yesterday"}`)))
+	assert.Error(t, ValidateData("billing.created.v9", []byte(`{}`)))
+}
+
+func TestParse_RejectsInvalidEnvelope(t *testing.T) {
+	data, _ := json.Marshal(samplePayloads()[TypeBillingClosed])
+	event := New(TypeBillingClosed, "1", BillingSubject(1), time.Now(), data)
+
+	event.SpecVersion = "0.3"
+	b, _ := json.Marshal(event)
+	_, err := Parse(b)
+	assert.Error(t, err)
+
+	event.SpecVersion = SpecVersion
+	event.Type = "billing.closed"
+	b, _ = json.Marshal(event)
+	_, err = Parse(b)
+	assert.Error(t, err)
+
+	_, err = Parse([]byte(`{"specversion":"1.0"}`))
+	assert.Error(t, err)
+}
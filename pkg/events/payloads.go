@@ -0,0 +1,65 @@
+package events
+
+import "time"
+
+type BillingCreated struct {
+	BillingID    uint      `json:"billingId"`
+	CustomerID   uint      `json:"customerId"`
+	LoanID       uint      `json:"loanId"`
+	LoanAmount   int       `json:"loanAmount"`
+	LoanWeeks    int       `json:"loanWeeks"`
+	LoanInterest int       `json:"loanInterest"`
+	Outstanding  int       `json:"outstanding"`
+	ProductCode  string    `json:"productCode,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type PaymentApplied struct {
+	BillingID   uint      `json:"billingId"`
+	CustomerID  uint      `json:"customerId"`
+	LoanID      uint      `json:"loanId"`
+	Week        int       `json:"week"`
+	Amount      int       `json:"amount"`
+	LateFee     int       `json:"lateFee"`
+	Outstanding int       `json:"outstanding"`
+	PaidAt      time.Time `json:"paidAt"`
+}
+
+type PaymentReversed struct {
+	BillingID   uint      `json:"billingId"`
+	CustomerID  uint      `json:"customerId"`
+	LoanID      uint      `json:"loanId"`
+	Week        int       `json:"week"`
+	Amount      int       `json:"amount"`
+	LateFee     int       `json:"lateFee"`
+	Outstanding int       `json:"outstanding"`
+	Reason      string    `json:"reason"`
+	ReversedAt  time.Time `json:"reversedAt"`
+}
+
+type BillingDelinquent struct {
+	BillingID    uint      `json:"billingId"`
+	CustomerID   uint      `json:"customerId"`
+	LoanID       uint      `json:"loanId"`
+	PolicyCode   string    `json:"policyCode"`
+	Rule         string    `json:"rule"`
+	Reason       string    `json:"reason"`
+	Source       string    `json:"source"`
+	DelinquentAt time.Time `json:"delinquentAt"`
+}
+
+type BillingCured struct {
+	BillingID  uint      `json:"billingId"`
+	CustomerID uint      `json:"customerId"`
+	LoanID     uint      `json:"loanId"`
+	PolicyCode string    `json:"policyCode"`
+	Source     string    `json:"source"`
+	CuredAt    time.Time `json:"curedAt"`
+}
+
+type BillingClosed struct {
+	BillingID  uint      `json:"billingId"`
+	CustomerID uint      `json:"customerId"`
+	LoanID     uint      `json:"loanId"`
+	ClosedAt   time.Time `json:"closedAt"`
+}
@@ -0,0 +1,82 @@
+package events
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+const envelopeSchema = "cloudevent.v1"
+
+//go:embed schemas/*.json
+var schemaFS embed.FS
+
+var (
+	compileOnce sync.Once
+	compiled    map[string]*jsonschema.Schema
+	compileErr  error
+)
+
+func schemas() (map[string]*jsonschema.Schema, error) {
+	compileOnce.Do(func() {
+		compiler := jsonschema.NewCompiler()
+		compiler.AssertFormat = true
+		names := append([]string{envelopeSchema}, types...)
+		for _, name := range names {
+			b, err := schemaFS.ReadFile("schemas/" + name + ".json")
+			if err != nil {
+				compileErr = err
+				return
+			}
+			if err := compiler.AddResource(SchemaURL(name), bytes.NewReader(b)); err != nil {
+				compileErr = err
+				return
+			}
+		}
+		compiled = make(map[string]*jsonschema.Schema, len(names))
+		for _, name := range names {
+			schema, err := compiler.Compile(SchemaURL(name))
+			if err != nil {
+				compileErr = err
+				return
+			}
+			compiled[name] = schema
+		}
+	})
+	return compiled, compileErr
+}
+
+func Schema(name string) ([]byte, error) {
+	return schemaFS.ReadFile("schemas/" + name + ".json")
+}
+
+func ValidateEnvelope(b []byte) error {
+	return validate(envelopeSchema, b)
+}
+
+func ValidateData(eventType string, data []byte) error {
+	return validate(eventType, data)
+}
+
+func validate(name string, b []byte) error {
+	schemas, err := schemas()
+	if err != nil {
+		return err
+	}
+	schema, ok := schemas[name]
+	if !ok {
+		return fmt.Errorf("unknown event type %q", name)
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if err := schema.Validate(v); err != nil {
+		return fmt.Errorf("%s does not match its schema: %w", name, err)
+	}
+	return nil
+}
@@ -3,33 +3,65 @@ package test
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/doddeeph/billing-engine/internal/collection"
 	"github.com/doddeeph/billing-engine/internal/config"
 	"github.com/doddeeph/billing-engine/internal/db"
 	"github.com/doddeeph/billing-engine/internal/dto"
+	"github.com/doddeeph/billing-engine/internal/gateway"
 	"github.com/doddeeph/billing-engine/internal/handler"
+	"github.com/doddeeph/billing-engine/internal/loanstatement"
+	"github.com/doddeeph/billing-engine/internal/lock"
 	"github.com/doddeeph/billing-engine/internal/model"
+	"github.com/doddeeph/billing-engine/internal/notification"
+	"github.com/doddeeph/billing-engine/internal/outbox"
+	"github.com/doddeeph/billing-engine/internal/qris"
 	"github.com/doddeeph/billing-engine/internal/repository"
 	"github.com/doddeeph/billing-engine/internal/service"
 	"github.com/doddeeph/billing-engine/internal/utils"
+	"github.com/doddeeph/billing-engine/internal/webhook"
+	"github.com/doddeeph/billing-engine/pkg/events"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"gorm.io/gorm"
 )
 
 var (
-	billingSvc service.BillingService
-	paymentSvc service.PaymentService
-	router     *gin.Engine
+	testDB          *gorm.DB
+	billingSvc      service.BillingService
+	paymentSvc      service.PaymentService
+	freezeSvc       service.FreezeService
+	writeOffSvc     service.WriteOffService
+	collectionSvc   service.CollectionService
+	delinquencySvc  service.DelinquencyService
+	sweepSvc        service.SweepService
+	outboxRepo      repository.OutboxRepository
+	webhookRepo     repository.WebhookRepository
+	webhookSvc      service.WebhookService
+	paymentBatchSvc service.PaymentBatchService
+	loanImportSvc   service.LoanImportService
+	reminderSvc     service.ReminderService
+	reminderChannel *recordingChannel
+	dunningSvc      service.DunningService
+	promiseSvc      service.PaymentPromiseService
+	router          *gin.Engine
 )
 
+const testGatewaySecret = "gateway-s3cret"
+
 func setupTest(t *testing.T) func() {
 	t.Helper()
 	ctx := context.Background()
@@ -68,27 +100,197 @@ func setupTest(t *testing.T) func() {
 		Password: testConfig.DB.Password,
 	})
 
+	testDB = db
+
+	outboxRepo = repository.NewOutboxRepository(db)
+	outboxSvc := service.NewOutboxService(outboxRepo)
+
 	billingRepo := repository.NewBillingRepository(db)
-	billingSvc = service.NewBillingService(billingRepo)
+	policyRepo := repository.NewDelinquencyPolicyRepository(db)
+	promiseCfg := &config.PaymentPromiseConfig{MaxDays: 14, SuppressDunning: true, SuppressDelinquency: true}
+	policySvc := service.NewDelinquencyPolicyService(policyRepo, billingRepo, &testConfig.Billing, promiseCfg)
+	policyHandler := handler.NewDelinquencyPolicyHandler(policySvc)
+
+	vaRepo := repository.NewVirtualAccountRepository(db)
+	vaSvc := service.NewVirtualAccountService(vaRepo, billingRepo, &config.VirtualAccountConfig{
+		Banks:  []config.VirtualAccountBank{{Code: "BCA", Prefix: "39358"}, {Code: "BNI", Prefix: "8808"}},
+		Length: 16,
+	})
+	vaHandler := handler.NewVirtualAccountHandler(vaSvc)
+
+	qrisRepo := repository.NewQRISPaymentRepository(db)
+	qrisSvc := service.NewQRISService(qrisRepo, billingRepo, &config.QRISConfig{
+		NMID:         "ID1020000000001",
+		Criteria:     "UMI",
+		MCC:          "6012",
+		MerchantName: "Billing Engine",
+		MerchantCity: "Jakarta",
+	})
+	qrisHandler := handler.NewQRISHandler(qrisSvc)
+
+	customerRepo := repository.NewCustomerRepository(db)
+	exposureRepo := repository.NewCustomerExposureRepository(db)
+	billingSvc = service.NewBillingService(billingRepo, customerRepo, exposureRepo, policySvc, outboxSvc, vaSvc, &testConfig.Billing)
 	billingHandler := handler.NewBillingHandler(billingSvc)
+	customerSvc := service.NewCustomerService(customerRepo, billingRepo, exposureRepo, billingSvc, policySvc, &config.CustomerConfig{
+		DefaultLanguage: "id",
+		DefaultTimezone: "Asia/Jakarta",
+		Languages:       []string{"id", "en"},
+	})
+	customerHandler := handler.NewCustomerHandler(customerSvc)
+
+	collectionRepo := repository.NewCollectionRepository(db)
+	collectionSvc = service.NewCollectionService(collectionRepo, billingRepo, customerRepo, &testConfig.Billing, &config.CollectionsConfig{PriorityAmountUnit: 100000})
+	collectionHandler := handler.NewCollectionHandler(collectionSvc)
+
+	recoveryRepo := repository.NewRecoveryRepository(db)
+	writeOffSvc = service.NewWriteOffService(billingRepo, recoveryRepo, collectionSvc, &testConfig.Billing)
+	writeOffHandler := handler.NewWriteOffHandler(writeOffSvc)
+
+	delinquencyHistoryRepo := repository.NewDelinquencyHistoryRepository(db)
+	delinquencySvc = service.NewDelinquencyService(delinquencyHistoryRepo, billingRepo, policySvc, outboxSvc, &testConfig.Billing)
+	delinquencyHandler := handler.NewDelinquencyHandler(delinquencySvc)
+
+	promiseRepo := repository.NewPaymentPromiseRepository(db)
+	promiseSvc = service.NewPaymentPromiseService(promiseRepo, billingRepo, promiseCfg)
+	promiseHandler := handler.NewPaymentPromiseHandler(promiseSvc)
 
 	paymentRepo := repository.NewPaymentRepository(db)
-	paymentSvc = service.NewPaymentService(paymentRepo, billingSvc)
+	paymentSvc = service.NewPaymentService(paymentRepo, billingSvc, writeOffSvc, delinquencySvc, promiseSvc, outboxSvc)
 	paymentHandler := handler.NewPaymentHandler(paymentSvc)
 
+	freezeRepo := repository.NewFreezeRepository(db)
+	freezeSvc = service.NewFreezeService(freezeRepo, paymentRepo, billingRepo)
+	freezeHandler := handler.NewFreezeHandler(freezeSvc)
+
+	sweepSvc = service.NewSweepService(billingRepo, paymentRepo, delinquencySvc, promiseSvc, &testConfig.Billing)
+
+	webhookRepo = repository.NewWebhookRepository(db)
+	webhookSvc = service.NewWebhookService(webhookRepo)
+	webhookHandler := handler.NewWebhookHandler(webhookSvc)
+
+	gatewayCallbackRepo := repository.NewGatewayCallbackRepository(db)
+	gatewaySvc := service.NewGatewayService(gatewayCallbackRepo, paymentSvc, vaSvc, qrisSvc, gateway.NewRegistry(gateway.NewMockProvider(testGatewaySecret)))
+	gatewayHandler := handler.NewGatewayHandler(gatewaySvc)
+
+	statementRepo := repository.NewStatementRepository(db)
+	reconSvc := service.NewReconciliationService(statementRepo, gatewayCallbackRepo, paymentSvc, vaSvc, qrisSvc, &config.ReconciliationConfig{MatchWindowDays: 3})
+	reconHandler := handler.NewReconciliationHandler(reconSvc)
+
+	paymentBatchRepo := repository.NewPaymentBatchRepository(db)
+	paymentBatchSvc = service.NewPaymentBatchService(paymentBatchRepo, paymentSvc, &config.PaymentBatchConfig{SyncLimit: 10, LeaseDuration: time.Minute})
+	paymentBatchHandler := handler.NewPaymentBatchHandler(paymentBatchSvc)
+
+	loanImportRepo := repository.NewLoanImportRepository(db)
+	loanImportSvc = service.NewLoanImportService(loanImportRepo, billingRepo, billingSvc)
+	loanImportHandler := handler.NewLoanImportHandler(loanImportSvc)
+
+	reminderRepo := repository.NewReminderRepository(db)
+	renderer, err := notification.NewRenderer([]string{"id", "en"}, "id", "")
+	assert.NoError(t, err)
+	reminderChannel = &recordingChannel{}
+	reminderSvc = service.NewReminderService(reminderRepo, customerRepo, vaRepo, map[string]notification.Channel{
+		notification.ChannelSMS:      reminderChannel,
+		notification.ChannelEmail:    reminderChannel,
+		notification.ChannelWhatsapp: reminderChannel,
+	}, renderer, &config.ReminderConfig{
+		Offsets:       []int{-2, 0, 1},
+		Channels:      []string{"SMS", "EMAIL", "WHATSAPP"},
+		SendHour:      9,
+		GracePeriod:   12 * time.Hour,
+		MaxAttempts:   3,
+		RetryInterval: 30 * time.Minute,
+		BatchSize:     100,
+	}, &config.CustomerConfig{DefaultLanguage: "id", DefaultTimezone: "Asia/Jakarta", Languages: []string{"id", "en"}})
+	reminderHandler := handler.NewReminderHandler(reminderSvc)
+
+	dunningRepo := repository.NewDunningRepository(db)
+	dunningSvc = service.NewDunningService(dunningRepo, billingRepo, customerRepo, map[string]notification.Channel{
+		notification.ChannelSMS: reminderChannel,
+	}, renderer, &testConfig.Billing, &config.CustomerConfig{DefaultLanguage: "id", DefaultTimezone: "Asia/Jakarta", Languages: []string{"id", "en"}}, promiseCfg, &config.DunningConfig{
+		DefaultSteps: []config.DunningStep{{DaysPastDue: 1, Action: "SMS"}, {DaysPastDue: 7, Action: "CALL"}, {DaysPastDue: 30, Action: "LETTER"}},
+	})
+	dunningHandler := handler.NewDunningHandler(dunningSvc)
+
+	statementRenderer, err := loanstatement.NewRenderer(loanstatement.Brand{Name: "Billing Engine", Color: "#1F4E79"}, "")
+	assert.NoError(t, err)
+	loanStatementSvc := service.NewLoanStatementService(billingRepo, recoveryRepo, statementRenderer, &config.CustomerConfig{DefaultLanguage: "id", DefaultTimezone: "Asia/Jakarta", Languages: []string{"id", "en"}})
+	loanStatementHandler := handler.NewLoanStatementHandler(loanStatementSvc)
+	calendarSvc := service.NewRepaymentCalendarService(billingRepo, customerRepo, &config.CustomerConfig{DefaultLanguage: "id", DefaultTimezone: "Asia/Jakarta", Languages: []string{"id", "en"}}, &config.CalendarConfig{Name: "Loan Repayments", UIDDomain: "billing-engine", RefreshHours: 6})
+	calendarHandler := handler.NewRepaymentCalendarHandler(calendarSvc)
+
 	gin.SetMode(gin.TestMode)
 	router = gin.Default()
 	router.POST("/billings", billingHandler.CreateBilling)
+	router.GET("/billings", billingHandler.ListBillings)
+	router.GET("/loans/:loanId/billing", billingHandler.GetBillingByLoanID)
+	router.GET("/customers/:customerId/billings", billingHandler.ListCustomerBillings)
+	router.PUT("/customers/:customerId", customerHandler.UpsertCustomer)
+	router.GET("/customers/:customerId", customerHandler.GetCustomer)
+	router.GET("/customers/:customerId/summary", customerHandler.GetSummary)
+	router.PUT("/customers/:customerId/exposure-limit", customerHandler.SetExposureLimit)
+	router.DELETE("/customers/:customerId/exposure-limit", customerHandler.RemoveExposureLimit)
 	router.GET("/billings/:id", billingHandler.GetBilling)
 	router.GET("/billings/:id/outstanding", billingHandler.GetOutstanding)
 	router.GET("/billings/:id/delinquent", billingHandler.IsDelinquent)
+	router.GET("/portfolio/aging", billingHandler.GetPortfolioAging)
 	router.POST("/billings/:id/payments", paymentHandler.MakePayment)
+	router.POST("/billings/:id/freezes", freezeHandler.FreezeBilling)
+	router.GET("/billings/:id/freezes", freezeHandler.GetFreezes)
+	router.POST("/freezes", freezeHandler.FreezeBillings)
+	router.POST("/billings/:id/write-off", writeOffHandler.WriteOff)
+	router.GET("/billings/:id/recoveries", writeOffHandler.GetRecoveries)
+	router.GET("/reports/write-offs", writeOffHandler.GetReport)
+	router.POST("/delinquency-policies", policyHandler.CreatePolicy)
+	router.PUT("/billings/:id/delinquency-policy", policyHandler.AssignPolicy)
+	router.GET("/billings/:id/delinquency-history", delinquencyHandler.GetHistory)
+	router.POST("/webhooks", webhookHandler.CreateSubscription)
+	router.GET("/webhook-deliveries", webhookHandler.GetDeliveries)
+	router.POST("/webhook-deliveries/:id/replay", webhookHandler.ReplayDelivery)
+	router.POST("/gateways/:provider/callbacks", gatewayHandler.HandleCallback)
+	router.GET("/gateway-callbacks", gatewayHandler.GetCallbacks)
+	router.GET("/virtual-accounts/:number", vaHandler.GetVirtualAccount)
+	router.POST("/billings/:id/virtual-accounts", vaHandler.IssueVirtualAccounts)
+	router.POST("/billings/:id/qris", qrisHandler.GeneratePayment)
+	router.GET("/qris/:reference/image", qrisHandler.GetImage)
+	router.POST("/statement-imports", reconHandler.ImportStatement)
+	router.GET("/statement-imports/:id", reconHandler.GetImport)
+	router.POST("/payments/batch", paymentBatchHandler.SubmitBatch)
+	router.GET("/payments/batch/:id", paymentBatchHandler.GetBatch)
+	router.POST("/loan-imports", loanImportHandler.SubmitImport)
+	router.GET("/loan-imports/:id", loanImportHandler.GetImport)
+	router.POST("/loan-imports/:id/retry", loanImportHandler.RetryImport)
+	router.GET("/reminders", reminderHandler.GetReminders)
+	router.GET("/billings/:id/reminders", reminderHandler.GetBillingReminders)
+	router.POST("/dunning-strategies", dunningHandler.CreateStrategy)
+	router.GET("/dunning-strategies", dunningHandler.GetStrategies)
+	router.GET("/billings/:id/dunning-log", dunningHandler.GetBillingLog)
+	router.POST("/collection-agents", collectionHandler.CreateAgent)
+	router.GET("/collection-agents/:id/queue", collectionHandler.GetAgentQueue)
+	router.GET("/collection-cases/unassigned", collectionHandler.GetUnassignedQueue)
+	router.GET("/collection-cases/:id", collectionHandler.GetCase)
+	router.PUT("/collection-cases/:id/agent", collectionHandler.AssignCase)
+	router.POST("/collection-cases/:id/contact-attempts", collectionHandler.LogContactAttempt)
+	router.GET("/billings/:id/collection-cases", collectionHandler.GetBillingCases)
+	router.POST("/billings/:id/payment-promises", promiseHandler.CreatePromise)
+	router.GET("/billings/:id/payment-promises", promiseHandler.GetPromises)
+	router.GET("/billings/:id/statement", loanStatementHandler.GetStatement)
+	router.GET("/billings/:id/schedule.ics", calendarHandler.GetBillingCalendar)
+	router.GET("/customers/:customerId/schedule.ics", calendarHandler.GetCustomerCalendar)
 
 	return func() {
 		_ = container.Terminate(ctx)
 	}
 }
 
+func backdateTestBilling(t *testing.T, billingID uint, weeks int) {
+	err := testDB.Model(&model.Payment{}).Where("billing_id = ?", billingID).Updates(map[string]any{
+		"start_date": gorm.Expr("start_date - make_interval(weeks => ?)", weeks),
+		"due_date":   gorm.Expr("due_date - make_interval(weeks => ?)", weeks),
+	}).Error
+	assert.NoError(t, err)
+}
+
 func createTestBilling(t *testing.T) *model.Billing {
 	req := dto.CreateBillingRequest{
 		CreateBillingDTO: dto.CreateBillingDTO{
@@ -182,6 +384,199 @@ func TestIntegration_GetBilling(t *testing.T) {
 	assert.WithinDuration(t, weekDateRange.EndOfWeek, resp.Payments[49].DueDate, 5*time.Second)
 }
 
+func TestIntegration_ListBillings(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	for i := 1; i <= 3; i++ {
+		_, err := billingSvc.CreateBilling(t.Context(), dto.CreateBillingRequest{
+			CreateBillingDTO: dto.CreateBillingDTO{
+				CustomerID:   uint(i%2 + 1),
+				LoanID:       uint(1000 + i),
+				LoanAmount:   1000000,
+				LoanInterest: 10,
+				LoanWeeks:    10,
+			},
+		})
+		assert.NoError(t, err)
+	}
+
+	r, _ := http.NewRequest("GET", "/loans/1002/billing", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var byLoan model.Billing
+	json.Unmarshal(w.Body.Bytes(), &byLoan)
+	assert.Equal(t, uint(1002), byLoan.LoanID)
+	assert.Len(t, byLoan.Payments, 10)
+
+	r, _ = http.NewRequest("GET", "/billings?limit=2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var page dto.BillingListResponse
+	json.Unmarshal(w.Body.Bytes(), &page)
+	assert.Len(t, page.Billings, 2)
+	assert.Equal(t, uint(1003), page.Billings[0].LoanID)
+	assert.NotEmpty(t, page.NextCursor)
+
+	r, _ = http.NewRequest("GET", "/billings?limit=2&cursor="+page.NextCursor, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var next dto.BillingListResponse
+	json.Unmarshal(w.Body.Bytes(), &next)
+	assert.Len(t, next.Billings, 1)
+	assert.Equal(t, uint(1001), next.Billings[0].LoanID)
+	assert.Empty(t, next.NextCursor)
+
+	r, _ = http.NewRequest("GET", "/customers/2/billings?status=active&delinquencyStatus=current", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var byCustomer dto.BillingListResponse
+	json.Unmarshal(w.Body.Bytes(), &byCustomer)
+	assert.Len(t, byCustomer.Billings, 2)
+
+	today := time.Now().Format(time.DateOnly)
+	r, _ = http.NewRequest("GET", "/billings?createdFrom="+today+"&createdTo="+today+"&delinquencyStatus=DELINQUENT", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var delinquent dto.BillingListResponse
+	json.Unmarshal(w.Body.Bytes(), &delinquent)
+	assert.Empty(t, delinquent.Billings)
+
+	r, _ = http.NewRequest("GET", "/billings?status=OPEN", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestIntegration_Customers(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	var placeholder model.Customer
+	assert.NoError(t, testDB.First(&placeholder, billing.CustomerID).Error)
+	assert.Nil(t, placeholder.SyncedAt)
+
+	body, _ := json.Marshal(dto.CustomerRequest{
+		Name:          "Budi Santoso",
+		Phone:         "081234567890",
+		Email:         "budi@example.com",
+		SMSOptIn:      true,
+		WhatsappOptIn: true,
+	})
+	r, _ := http.NewRequest("PUT", fmt.Sprintf("/customers/%d", billing.CustomerID), bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var customer model.Customer
+	json.Unmarshal(w.Body.Bytes(), &customer)
+	assert.Equal(t, "+6281234567890", customer.Phone)
+	assert.Equal(t, "id", customer.PreferredLanguage)
+	assert.Equal(t, "Asia/Jakarta", customer.Timezone)
+	assert.NotNil(t, customer.SyncedAt)
+
+	body, _ = json.Marshal(dto.CustomerRequest{Name: "Budi Santoso", Email: "budi@example.com", PreferredLanguage: "en", EmailOptIn: true})
+	r, _ = http.NewRequest("PUT", fmt.Sprintf("/customers/%d", billing.CustomerID), bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/billings/%d", billing.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var resp model.Billing
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.NotNil(t, resp.Customer)
+	assert.Equal(t, "en", resp.Customer.PreferredLanguage)
+	assert.Empty(t, resp.Customer.Phone)
+	assert.False(t, resp.Customer.SMSOptIn)
+	assert.True(t, resp.Customer.EmailOptIn)
+
+	body, _ = json.Marshal(dto.CustomerRequest{SMSOptIn: true})
+	r, _ = http.NewRequest("PUT", "/customers/2", bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 400, w.Code)
+
+	r, _ = http.NewRequest("GET", "/customers/2", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestIntegration_CustomerSummary(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	body, _ := json.Marshal(dto.ExposureLimitRequest{ExposureLimit: 8000000})
+	r, _ := http.NewRequest("PUT", "/customers/1/exposure-limit", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	billing := createTestBilling(t)
+	backdateTestBilling(t, billing.ID, 3)
+
+	_, err := billingSvc.CreateBilling(t.Context(), dto.CreateBillingRequest{
+		CreateBillingDTO: dto.CreateBillingDTO{
+			CustomerID:   1,
+			LoanID:       2,
+			LoanAmount:   5000000,
+			LoanInterest: 10,
+			LoanWeeks:    50,
+		},
+	})
+	assert.EqualError(t, err, "Customer 1 exposure of 11000000 would exceed the limit of 8000000.")
+
+	second, err := billingSvc.CreateBilling(t.Context(), dto.CreateBillingRequest{
+		CreateBillingDTO: dto.CreateBillingDTO{
+			CustomerID:   1,
+			LoanID:       2,
+			LoanAmount:   2000000,
+			LoanInterest: 10,
+			LoanWeeks:    20,
+		},
+	})
+	assert.NoError(t, err)
+
+	r, _ = http.NewRequest("GET", "/customers/1/summary", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var summary dto.CustomerSummaryResponse
+	json.Unmarshal(w.Body.Bytes(), &summary)
+	assert.Equal(t, 2, summary.ActiveBillingCount)
+	assert.Equal(t, 5500000+second.Outstanding, summary.TotalOutstanding)
+	assert.Equal(t, 2*110000, summary.TotalOverdue)
+	assert.NotNil(t, summary.NextDueDate)
+	assert.GreaterOrEqual(t, summary.WorstDaysPastDue, 7)
+	assert.True(t, summary.IsDelinquent)
+	assert.Equal(t, []uint{billing.ID}, summary.DelinquentBillingIDs)
+	assert.Equal(t, 8000000, *summary.ExposureLimit)
+	assert.Equal(t, 8000000-summary.TotalOutstanding, *summary.AvailableExposure)
+
+	r, _ = http.NewRequest("DELETE", "/customers/1/exposure-limit", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 204, w.Code)
+	_, err = billingSvc.CreateBilling(t.Context(), dto.CreateBillingRequest{
+		CreateBillingDTO: dto.CreateBillingDTO{
+			CustomerID:   1,
+			LoanID:       3,
+			LoanAmount:   5000000,
+			LoanInterest: 10,
+			LoanWeeks:    50,
+		},
+	})
+	assert.NoError(t, err)
+}
+
 func TestIntegration_GetOutstanding(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -199,6 +594,30 @@ func TestIntegration_GetOutstanding(t *testing.T) {
 	var resp dto.OutstandingResponse
 	json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.Equal(t, 5500000, resp.Outstanding)
+	assert.Equal(t, 0, resp.DaysPastDue)
+	assert.Equal(t, "current", resp.AgingBucket)
+}
+
+func TestIntegration_GetPortfolioAging(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	assert.NotZero(t, billing.ID)
+
+	r, _ := http.NewRequest("GET", "/portfolio/aging", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	var resp dto.PortfolioAgingResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, 1, resp.TotalCount)
+	assert.Equal(t, 5500000, resp.TotalOutstanding)
+	assert.Len(t, resp.Buckets, 5)
+	assert.Equal(t, "current", resp.Buckets[0].Bucket)
+	assert.Equal(t, 1, resp.Buckets[0].Count)
+	assert.Equal(t, 5500000, resp.Buckets[0].Outstanding)
 }
 
 func TestIntegration_IsDelinquent(t *testing.T) {
@@ -210,6 +629,8 @@ func TestIntegration_IsDelinquent(t *testing.T) {
 	assert.NotZero(t, billing.CustomerID)
 	assert.NotZero(t, billing.LoanID)
 
+	backdateTestBilling(t, billing.ID, 3)
+
 	paymentReq := dto.PaymentRequest{
 		Week:   3,
 		Amount: 110000,
@@ -227,6 +648,62 @@ func TestIntegration_IsDelinquent(t *testing.T) {
 	assert.True(t, resp.IsDelinquent)
 }
 
+func TestIntegration_IsDelinquent_OnlyDueInstallmentsCount(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	_, err := paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 3, Amount: 110000})
+	assert.NoError(t, err)
+
+	_, delinquency, err := billingSvc.IsDelinquent(t.Context(), billing.ID)
+	assert.NoError(t, err)
+	assert.False(t, delinquency.IsDelinquent)
+	assert.Equal(t, model.DefaultDelinquencyPolicyCode, delinquency.PolicyCode)
+
+	backdateTestBilling(t, billing.ID, 3)
+
+	_, delinquency, err = billingSvc.IsDelinquent(t.Context(), billing.ID)
+	assert.NoError(t, err)
+	assert.True(t, delinquency.IsDelinquent)
+	assert.Equal(t, model.DefaultDelinquencyPolicyCode, delinquency.PolicyCode)
+	assert.Equal(t, model.DelinquencyRuleConsecutiveMisses, delinquency.Rule)
+}
+
+func TestIntegration_IsDelinquentWithPolicy(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	assert.NotZero(t, billing.ID)
+	backdateTestBilling(t, billing.ID, 3)
+
+	payload := dto.DelinquencyPolicyRequest{
+		Code:      "DPD-30",
+		Rule:      model.DelinquencyRuleDPDThreshold,
+		Threshold: 30,
+	}
+	payloadBytes, _ := json.Marshal(payload)
+	r, _ := http.NewRequest("POST", "/delinquency-policies", bytes.NewBuffer(payloadBytes))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 201, w.Code)
+
+	var policy model.DelinquencyPolicy
+	json.Unmarshal(w.Body.Bytes(), &policy)
+
+	payloadBytes, _ = json.Marshal(dto.AssignDelinquencyPolicyRequest{PolicyID: &policy.ID})
+	r, _ = http.NewRequest("PUT", fmt.Sprintf("/billings/%d/delinquency-policy", billing.ID), bytes.NewBuffer(payloadBytes))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	_, delinquency, err := billingSvc.IsDelinquent(t.Context(), billing.ID)
+	assert.NoError(t, err)
+	assert.False(t, delinquency.IsDelinquent)
+	assert.Equal(t, "DPD-30", delinquency.PolicyCode)
+}
+
 func TestIntregration_MakePayment(t *testing.T) {
 	teardown := setupTest(t)
 	defer teardown()
@@ -254,3 +731,1324 @@ func TestIntregration_MakePayment(t *testing.T) {
 	assert.True(t, paymentResp.Payment.Paid)
 	assert.WithinDuration(t, time.Now(), *paymentResp.Payment.PaidDate, 5*time.Second)
 }
+
+func TestIntegration_FreezeBilling(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	assert.NotZero(t, billing.ID)
+
+	startDate := billing.Payments[0].StartDate
+	payload := dto.FreezeRequest{
+		StartDate: startDate,
+		EndDate:   startDate.AddDate(0, 0, 14),
+		Reason:    "Flood relief",
+	}
+	payloadBytes, _ := json.Marshal(payload)
+
+	r, _ := http.NewRequest("POST", fmt.Sprintf("/billings/%d/freezes", billing.ID), bytes.NewBuffer(payloadBytes))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 201, w.Code)
+
+	var freeze model.BillingFreeze
+	json.Unmarshal(w.Body.Bytes(), &freeze)
+	assert.Equal(t, billing.ID, freeze.BillingID)
+	assert.Equal(t, 2, freeze.ShiftedWeeks)
+	assert.Equal(t, "Flood relief", freeze.Reason)
+
+	frozen, err := billingSvc.GetBilling(t.Context(), billing.ID)
+	assert.NoError(t, err)
+	assert.Len(t, frozen.Freezes, 1)
+	assert.WithinDuration(t, billing.Payments[0].DueDate.AddDate(0, 0, 14), frozen.Payments[0].DueDate, time.Second)
+	assert.WithinDuration(t, billing.Payments[49].DueDate.AddDate(0, 0, 14), frozen.Payments[49].DueDate, time.Second)
+}
+
+func TestIntegration_FreezeBilling_RejectsOverlap(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	startDate := billing.Payments[0].StartDate
+	_, err := freezeSvc.FreezeBilling(t.Context(), billing.ID, dto.FreezeRequest{
+		StartDate: startDate,
+		EndDate:   startDate.AddDate(0, 0, 14),
+		Reason:    "Flood relief",
+	})
+	assert.NoError(t, err)
+
+	_, err = freezeSvc.FreezeBilling(t.Context(), billing.ID, dto.FreezeRequest{
+		StartDate: startDate.AddDate(0, 0, 7),
+		EndDate:   startDate.AddDate(0, 0, 21),
+		Reason:    "Extended relief",
+	})
+	assert.EqualError(t, err, fmt.Sprintf("Freeze overlaps an existing freeze of billing %d.", billing.ID))
+
+	frozen, err := billingSvc.GetBilling(t.Context(), billing.ID)
+	assert.NoError(t, err)
+	assert.Len(t, frozen.Freezes, 1)
+	assert.WithinDuration(t, billing.Payments[0].DueDate.AddDate(0, 0, 14), frozen.Payments[0].DueDate, time.Second)
+}
+
+func TestIntegration_FreezeBilling_WaivesLateFee(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	backdateTestBilling(t, billing.ID, 2)
+	err := testDB.Model(&model.Payment{}).Where("billing_id = ? AND week = ?", billing.ID, 1).
+		Updates(map[string]any{"overdue": true, "late_fee": 5000}).Error
+	assert.NoError(t, err)
+	assert.NoError(t, testDB.Model(&model.Billing{}).Where("id = ?", billing.ID).Update("outstanding", 5505000).Error)
+
+	now := time.Now()
+	_, err = freezeSvc.FreezeBilling(t.Context(), billing.ID, dto.FreezeRequest{
+		StartDate: now.AddDate(0, 0, -14),
+		EndDate:   now.AddDate(0, 0, 14),
+		Reason:    "Flood relief",
+	})
+	assert.NoError(t, err)
+
+	resp, err := paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 1, Amount: 110000})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.Payment.LateFee)
+	assert.Equal(t, 5500000-110000, resp.Outstanding)
+
+	paid, err := billingSvc.GetBilling(t.Context(), billing.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 5500000-110000, paid.Outstanding)
+	assert.False(t, paid.Payments[0].Overdue)
+}
+
+func TestIntegration_FreezeBillings(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	assert.NotZero(t, billing.ID)
+
+	now := time.Now()
+	payload := dto.BatchFreezeRequest{
+		FreezeRequest: dto.FreezeRequest{
+			StartDate: now.AddDate(0, 0, -1),
+			EndDate:   now.AddDate(0, 0, 30),
+			Reason:    "Regional emergency",
+		},
+		Filter: dto.BillingFilter{CustomerIDs: []uint{billing.CustomerID}},
+	}
+	payloadBytes, _ := json.Marshal(payload)
+	closed, err := billingSvc.CreateBilling(t.Context(), dto.CreateBillingRequest{
+		CreateBillingDTO: dto.CreateBillingDTO{CustomerID: billing.CustomerID, LoanID: 2, LoanAmount: 1000000, LoanInterest: 10, LoanWeeks: 10},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, testDB.Model(&model.Billing{}).Where("id = ?", closed.ID).Updates(map[string]any{"status": model.BillingStatusClosed, "outstanding": 0}).Error)
+
+	r, _ := http.NewRequest("POST", "/freezes", bytes.NewBuffer(payloadBytes))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 201, w.Code)
+
+	var resp dto.BatchFreezeResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, 1, resp.Count)
+
+	backdateTestBilling(t, billing.ID, 3)
+
+	_, delinquency, err := billingSvc.IsDelinquent(t.Context(), billing.ID)
+	assert.NoError(t, err)
+	assert.False(t, delinquency.IsDelinquent)
+	assert.True(t, delinquency.Frozen)
+}
+
+func TestIntegration_WriteOffAndRecovery(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	assert.NotZero(t, billing.ID)
+
+	_, err := paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 1, Amount: 110000})
+	assert.NoError(t, err)
+
+	payloadBytes, _ := json.Marshal(dto.WriteOffRequest{Reason: "90+ days past due"})
+	r, _ := http.NewRequest("POST", fmt.Sprintf("/billings/%d/write-off", billing.ID), bytes.NewBuffer(payloadBytes))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 400, w.Code)
+
+	backdateTestBilling(t, billing.ID, 16)
+
+	r, _ = http.NewRequest("POST", fmt.Sprintf("/billings/%d/write-off", billing.ID), bytes.NewBuffer(payloadBytes))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	var writeOffResp dto.WriteOffResponse
+	json.Unmarshal(w.Body.Bytes(), &writeOffResp)
+	assert.Equal(t, model.BillingStatusWrittenOff, writeOffResp.Status)
+	assert.Equal(t, 5390000, writeOffResp.WrittenOffAmount)
+
+	paymentResp, err := paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 2, Amount: 200000})
+	assert.NoError(t, err)
+	assert.Nil(t, paymentResp.Payment)
+	assert.Equal(t, 200000, paymentResp.Recovery.Amount)
+	assert.Equal(t, 0, paymentResp.Outstanding)
+
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/billings/%d/recoveries", billing.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	var recoveriesResp dto.RecoveriesResponse
+	json.Unmarshal(w.Body.Bytes(), &recoveriesResp)
+	assert.Equal(t, 200000, recoveriesResp.RecoveredAmount)
+	assert.Len(t, recoveriesResp.Recoveries, 1)
+
+	r, _ = http.NewRequest("GET", "/reports/write-offs", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	var reportResp dto.WriteOffReportResponse
+	json.Unmarshal(w.Body.Bytes(), &reportResp)
+	assert.Equal(t, 1, reportResp.WrittenOffCount)
+	assert.Equal(t, 5390000, reportResp.WrittenOffAmount)
+	assert.Equal(t, 200000, reportResp.RecoveredAmount)
+	assert.Equal(t, 5190000, reportResp.NetLoss)
+}
+
+func TestIntegration_DelinquencyHistory(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	assert.NotZero(t, billing.ID)
+	backdateTestBilling(t, billing.ID, 3)
+
+	result, err := delinquencySvc.EvaluateActiveBillings(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Evaluated)
+	assert.Equal(t, 1, result.Changed)
+
+	_, err = paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 1, Amount: 110000})
+	assert.NoError(t, err)
+	_, err = paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 2, Amount: 110000})
+	assert.NoError(t, err)
+
+	r, _ := http.NewRequest("GET", fmt.Sprintf("/billings/%d/delinquency-history", billing.ID), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	var histories []model.DelinquencyHistory
+	json.Unmarshal(w.Body.Bytes(), &histories)
+	assert.Len(t, histories, 2)
+	assert.Equal(t, model.DelinquencyStatusCurrent, histories[0].FromStatus)
+	assert.Equal(t, model.DelinquencyStatusDelinquent, histories[0].ToStatus)
+	assert.Equal(t, model.DelinquencySourceEvaluator, histories[0].Source)
+	assert.Equal(t, model.DelinquencyRuleConsecutiveMisses, histories[0].Rule)
+	assert.Equal(t, model.DelinquencyStatusDelinquent, histories[1].FromStatus)
+	assert.Equal(t, model.DelinquencyStatusCurrent, histories[1].ToStatus)
+	assert.Equal(t, model.DelinquencySourcePayment, histories[1].Source)
+}
+
+func TestIntegration_EndOfDaySweep(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	assert.NotZero(t, billing.ID)
+	backdateTestBilling(t, billing.ID, 3)
+
+	sweep := service.NewSweepService(repository.NewBillingRepository(testDB), repository.NewPaymentRepository(testDB), delinquencySvc, promiseSvc, &config.BillingConfig{
+		LateFeeAmount:       5000,
+		EvaluationBatchSize: 10,
+	})
+	processed, failed, err := sweep.RunEndOfDaySweep(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Equal(t, 0, failed)
+
+	swept, err := billingSvc.GetBilling(t.Context(), billing.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 5510000, swept.Outstanding)
+	assert.Equal(t, model.DelinquencyStatusDelinquent, swept.DelinquencyStatus)
+	assert.True(t, swept.Payments[0].Overdue)
+	assert.Equal(t, 5000, swept.Payments[0].LateFee)
+	assert.False(t, swept.Payments[2].Overdue)
+
+	_, err = paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 1, Amount: 110000})
+	assert.Error(t, err)
+	paymentResp, err := paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 1, Amount: 115000})
+	assert.NoError(t, err)
+	assert.Equal(t, 5395000, paymentResp.Outstanding)
+}
+
+func TestIntegration_AdvisoryLock(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	instanceA := lock.NewAdvisoryLocker(testDB)
+	instanceB := lock.NewAdvisoryLocker(testDB)
+
+	lockA, acquired, err := instanceA.TryLock(t.Context(), "job:eod-sweep")
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	_, acquired, err = instanceB.TryLock(t.Context(), "job:eod-sweep")
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	assert.NoError(t, lockA.Release(t.Context()))
+
+	lockB, acquired, err := instanceB.TryLock(t.Context(), "job:eod-sweep")
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	assert.NoError(t, lockB.Release(t.Context()))
+}
+
+type recordingPublisher struct {
+	events []model.OutboxEvent
+	fail   map[uint]bool
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	if p.fail[event.BillingID] {
+		return fmt.Errorf("broker unavailable")
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestIntegration_Outbox(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	assert.NotZero(t, billing.ID)
+	backdateTestBilling(t, billing.ID, 3)
+	_, err := paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 3, Amount: 110000})
+	assert.NoError(t, err)
+
+	pending, err := outboxRepo.FindPending(t.Context(), 10)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 3)
+	assert.Equal(t, events.TypeBillingCreated, pending[0].EventType)
+	assert.Equal(t, events.TypePaymentApplied, pending[1].EventType)
+	assert.Equal(t, events.TypeBillingDelinquent, pending[2].EventType)
+
+	publisher := &recordingPublisher{fail: map[uint]bool{billing.ID: true}}
+	relay := outbox.NewRelay(outboxRepo, publisher, lock.NewAdvisoryLocker(testDB), &config.OutboxConfig{BatchSize: 10})
+	published, failed, err := relay.RelayBatch(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, published)
+	assert.Equal(t, 1, failed)
+
+	publisher.fail = nil
+	published, failed, err = relay.RelayBatch(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, published)
+	assert.Equal(t, 0, failed)
+	assert.Equal(t, pending[0].ID, publisher.events[0].ID)
+	assert.Equal(t, pending[2].ID, publisher.events[2].ID)
+
+	pending, err = outboxRepo.FindPending(t.Context(), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestIntegration_Webhooks(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	statusCode := http.StatusServiceUnavailable
+	var received []*http.Request
+	var bodies [][]byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r)
+		bodies = append(bodies, body)
+		w.WriteHeader(statusCode)
+	}))
+	defer receiver.Close()
+
+	payloadBytes, _ := json.Marshal(dto.WebhookSubscriptionRequest{
+		URL:        receiver.URL,
+		EventTypes: []string{events.TypeBillingCreated},
+	})
+	r, _ := http.NewRequest("POST", "/webhooks", bytes.NewBuffer(payloadBytes))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 201, w.Code)
+	var subscription dto.WebhookSubscriptionResponse
+	json.Unmarshal(w.Body.Bytes(), &subscription)
+	assert.NotEmpty(t, subscription.Secret)
+
+	billing := createTestBilling(t)
+	assert.NotZero(t, billing.ID)
+	_, err := paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 1, Amount: 110000})
+	assert.NoError(t, err)
+
+	relay := outbox.NewRelay(outboxRepo, webhook.NewPublisher(webhookSvc), lock.NewAdvisoryLocker(testDB), &config.OutboxConfig{BatchSize: 10})
+	published, _, err := relay.RelayBatch(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, published)
+
+	dispatcher := webhook.NewDispatcher(webhookRepo, lock.NewAdvisoryLocker(testDB), &config.WebhookConfig{
+		BatchSize:   10,
+		MaxAttempts: 2,
+		Timeout:     time.Second,
+	})
+	for range 2 {
+		_, failed, err := dispatcher.DispatchBatch(t.Context())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, failed)
+	}
+	assert.Len(t, received, 2)
+
+	r, _ = http.NewRequest("GET", "/webhook-deliveries?status=DEAD", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var dead []model.WebhookDelivery
+	json.Unmarshal(w.Body.Bytes(), &dead)
+	assert.Len(t, dead, 1)
+	assert.Equal(t, events.TypeBillingCreated, dead[0].EventType)
+	assert.Equal(t, 2, dead[0].Attempts)
+
+	statusCode = http.StatusNoContent
+	r, _ = http.NewRequest("POST", fmt.Sprintf("/webhook-deliveries/%d/replay", dead[0].ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	delivered, _, err := dispatcher.DispatchBatch(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Len(t, received, 3)
+
+	last := received[2]
+	timestamp, err := strconv.ParseInt(last.Header.Get(webhook.HeaderTimestamp), 10, 64)
+	assert.NoError(t, err)
+	assert.True(t, webhook.Verify(subscription.Secret, timestamp, bodies[2], last.Header.Get(webhook.HeaderSignature)))
+	event, err := events.Parse(bodies[2])
+	assert.NoError(t, err)
+	assert.Equal(t, events.TypeBillingCreated, event.Type)
+	assert.Equal(t, events.BillingSubject(billing.ID), event.Subject)
+	var created events.BillingCreated
+	assert.NoError(t, event.DecodeData(&created))
+	assert.Equal(t, billing.ID, created.BillingID)
+	assert.Equal(t, 5500000, created.Outstanding)
+}
+
+func postMockCallback(t *testing.T, req gateway.MockCallbackRequest, secret string) (int, dto.GatewayCallbackResponse) {
+	body, _ := json.Marshal(req)
+	r, _ := http.NewRequest("POST", "/gateways/mock/callbacks", bytes.NewBuffer(body))
+	r.Header.Set(gateway.MockSignatureHeader, gateway.SignMockCallback(secret, body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	var resp dto.GatewayCallbackResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp
+}
+
+func TestIntegration_GatewayCallback(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	assert.NotZero(t, billing.ID)
+
+	callbackReq := gateway.MockCallbackRequest{
+		TransactionID: "trx-1",
+		BillingID:     billing.ID,
+		Amount:        110000,
+		Status:        gateway.MockStatusPaid,
+	}
+	code, _ := postMockCallback(t, callbackReq, "wrong-secret")
+	assert.Equal(t, 401, code)
+
+	code, resp := postMockCallback(t, callbackReq, testGatewaySecret)
+	assert.Equal(t, 200, code)
+	assert.False(t, resp.Duplicate)
+	assert.Equal(t, model.GatewayCallbackStatusProcessed, resp.Callback.Status)
+	assert.NotNil(t, resp.Callback.PaymentID)
+
+	code, resp = postMockCallback(t, callbackReq, testGatewaySecret)
+	assert.Equal(t, 200, code)
+	assert.True(t, resp.Duplicate)
+
+	paid, err := billingSvc.GetBilling(t.Context(), billing.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 5390000, paid.Outstanding)
+	assert.True(t, paid.Payments[0].Paid)
+	assert.False(t, paid.Payments[1].Paid)
+
+	code, resp = postMockCallback(t, gateway.MockCallbackRequest{
+		TransactionID: "trx-2",
+		BillingID:     billing.ID,
+		Amount:        1000,
+		Status:        gateway.MockStatusPaid,
+	}, testGatewaySecret)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, model.GatewayCallbackStatusFailed, resp.Callback.Status)
+	assert.Contains(t, resp.Callback.Error, "Insufficient")
+
+	code, resp = postMockCallback(t, gateway.MockCallbackRequest{
+		TransactionID: "trx-3",
+		BillingID:     billing.ID,
+		Amount:        110000,
+		Status:        "EXPIRED",
+	}, testGatewaySecret)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, model.GatewayCallbackStatusIgnored, resp.Callback.Status)
+
+	r, _ := http.NewRequest("GET", "/gateway-callbacks?provider=mock&status=FAILED", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var failed []model.GatewayCallback
+	json.Unmarshal(w.Body.Bytes(), &failed)
+	assert.Len(t, failed, 1)
+	assert.Equal(t, "trx-2", failed[0].ExternalID)
+}
+
+func TestIntegration_GatewayCallback_RetryAndOverpayment(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+
+	code, resp := postMockCallback(t, gateway.MockCallbackRequest{
+		TransactionID: "trx-over",
+		BillingID:     billing.ID,
+		Amount:        250000,
+		Status:        gateway.MockStatusPaid,
+	}, testGatewaySecret)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, model.GatewayCallbackStatusFailed, resp.Callback.Status)
+	assert.Contains(t, resp.Callback.Error, "exceeds")
+
+	paidAt := time.Date(2025, 12, 1, 9, 30, 0, 0, time.UTC)
+	code, resp = postMockCallback(t, gateway.MockCallbackRequest{
+		TransactionID: "trx-over",
+		BillingID:     billing.ID,
+		Amount:        110000,
+		Status:        gateway.MockStatusPaid,
+		PaidAt:        &paidAt,
+	}, testGatewaySecret)
+	assert.Equal(t, 200, code)
+	assert.False(t, resp.Duplicate)
+	assert.Equal(t, model.GatewayCallbackStatusProcessed, resp.Callback.Status)
+	assert.Equal(t, 2, resp.Callback.Attempts)
+
+	paid, err := billingSvc.GetBilling(t.Context(), billing.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 5390000, paid.Outstanding)
+	assert.True(t, paid.Payments[0].Paid)
+	assert.True(t, paidAt.Equal(*paid.Payments[0].PaidDate))
+
+	_, err = paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Amount: 110000})
+	assert.Error(t, err)
+}
+
+func TestIntegration_VirtualAccounts(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	assert.Len(t, billing.VirtualAccounts, 2)
+	bca := billing.VirtualAccounts[0]
+	assert.Equal(t, "BCA", bca.BankCode)
+	assert.Len(t, bca.Number, 16)
+	assert.Equal(t, "39358", bca.Number[:5])
+	assert.True(t, utils.IsValidLuhn(bca.Number))
+
+	r, _ := http.NewRequest("POST", fmt.Sprintf("/billings/%d/virtual-accounts", billing.ID), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var issued []model.VirtualAccount
+	json.Unmarshal(w.Body.Bytes(), &issued)
+	assert.Len(t, issued, 2)
+	assert.Equal(t, bca.Number, issued[0].Number)
+
+	r, _ = http.NewRequest("GET", "/virtual-accounts/"+bca.Number, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var lookup dto.VirtualAccountResponse
+	json.Unmarshal(w.Body.Bytes(), &lookup)
+	assert.Equal(t, billing.ID, lookup.BillingID)
+	assert.Equal(t, 5500000, lookup.Outstanding)
+	assert.Equal(t, 1, lookup.NextInstallment.Week)
+	assert.Equal(t, 110000, lookup.NextInstallment.AmountDue)
+
+	r, _ = http.NewRequest("GET", "/virtual-accounts/0000000000000000", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 404, w.Code)
+
+	code, resp := postMockCallback(t, gateway.MockCallbackRequest{
+		TransactionID:  "va-trx-1",
+		VirtualAccount: bca.Number,
+		Amount:         110000,
+		Status:         gateway.MockStatusPaid,
+	}, testGatewaySecret)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, model.GatewayCallbackStatusProcessed, resp.Callback.Status)
+	assert.Equal(t, billing.ID, resp.Callback.BillingID)
+}
+
+func TestIntegration_QRIS(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+
+	r, _ := http.NewRequest("POST", fmt.Sprintf("/billings/%d/qris", billing.ID), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var resp dto.QRISResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, 110000, resp.Amount)
+	assert.Equal(t, 1, resp.Week)
+	fields, err := qris.Parse(resp.Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, "110000", fields[qris.TagAmount])
+	assert.Equal(t, qris.PointOfInitiationDynamic, fields[qris.TagPointOfInitiation])
+
+	body, _ := json.Marshal(dto.QRISRequest{Amount: 50000})
+	r, _ = http.NewRequest("POST", fmt.Sprintf("/billings/%d/qris", billing.ID), bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var custom dto.QRISResponse
+	json.Unmarshal(w.Body.Bytes(), &custom)
+	assert.Equal(t, 50000, custom.Amount)
+	assert.NotEqual(t, resp.Reference, custom.Reference)
+
+	r, _ = http.NewRequest("GET", "/qris/"+resp.Reference+"/image", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+
+	code, callback := postMockCallback(t, gateway.MockCallbackRequest{
+		TransactionID: "qris-trx-1",
+		Reference:     resp.Reference,
+		Amount:        resp.Amount,
+		Status:        gateway.MockStatusPaid,
+	}, testGatewaySecret)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, model.GatewayCallbackStatusProcessed, callback.Callback.Status)
+	assert.Equal(t, billing.ID, callback.Callback.BillingID)
+}
+
+func postStatement(t *testing.T, fileName, content string) (int, dto.StatementImportResponse) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", fileName)
+	assert.NoError(t, err)
+	part.Write([]byte(content))
+	writer.Close()
+
+	r, _ := http.NewRequest("POST", "/statement-imports", &body)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	var resp dto.StatementImportResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp
+}
+
+func TestIntegration_StatementReconciliation(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	va := billing.VirtualAccounts[0].Number
+
+	code, _ := postMockCallback(t, gateway.MockCallbackRequest{
+		TransactionID: "recon-trx-1",
+		BillingID:     billing.ID,
+		Amount:        110000,
+		Status:        gateway.MockStatusPaid,
+	}, testGatewaySecret)
+	assert.Equal(t, 200, code)
+
+	today := time.Now().Format("2006-01-02")
+	content := "date,amount,type,reference,description\n" +
+		today + ",110000,CR,recon-trx-1,GATEWAY SETTLEMENT\n" +
+		today + ",110000,CR,BNK001,VA PAYMENT " + va + "\n" +
+		today + ",1000,CR,BNK002,VA PAYMENT " + va + "\n" +
+		today + ",999,CR,BNK003,UNKNOWN TRANSFER\n" +
+		today + ",25000,DR,BNK004,ADMIN FEE\n"
+	code, resp := postStatement(t, "statement.csv", content)
+	assert.Equal(t, 200, code)
+	assert.False(t, resp.Duplicate)
+	assert.Equal(t, 5, resp.Import.TotalLines)
+	assert.Equal(t, 1, resp.Import.MatchedLines)
+	assert.Equal(t, 1, resp.Import.AppliedLines)
+	assert.Equal(t, 1, resp.Import.MismatchedLines)
+	assert.Equal(t, 1, resp.Import.UnmatchedLines)
+	assert.Equal(t, 1, resp.Import.IgnoredLines)
+
+	lines := resp.Import.Lines
+	assert.Equal(t, model.StatementLineStatusMatched, lines[0].Status)
+	assert.Equal(t, model.StatementMatchReference, lines[0].MatchedBy)
+	assert.Equal(t, model.StatementLineStatusApplied, lines[1].Status)
+	assert.Equal(t, model.StatementMatchVirtualAccount, lines[1].MatchedBy)
+	assert.Equal(t, model.StatementLineStatusMismatched, lines[2].Status)
+	assert.Equal(t, model.StatementLineStatusUnmatched, lines[3].Status)
+
+	updated, err := billingSvc.GetBilling(context.Background(), billing.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 5280000, updated.Outstanding)
+
+	code, resp = postStatement(t, "statement.csv", content)
+	assert.Equal(t, 200, code)
+	assert.True(t, resp.Duplicate)
+	assert.Equal(t, 1, resp.Import.AppliedLines)
+
+	r, _ := http.NewRequest("GET", fmt.Sprintf("/statement-imports/%d?status=UNMATCHED", resp.Import.ID), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var report model.StatementImport
+	json.Unmarshal(w.Body.Bytes(), &report)
+	assert.Len(t, report.Lines, 1)
+	assert.Equal(t, "BNK003", report.Lines[0].Reference)
+}
+
+func TestIntegration_StatementReconciliation_ReviewsAndResumes(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	va := billing.VirtualAccounts[0].Number
+
+	today := time.Now().Format("2006-01-02")
+	content := "date,amount,type,reference,description\n" +
+		today + ",110000,CR,BNK101,TRANSFER\n" +
+		today + ",250000,CR,BNK102,VA PAYMENT " + va + "\n"
+	code, resp := postStatement(t, "statement.csv", content)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, 0, resp.Import.AppliedLines)
+	assert.Equal(t, 1, resp.Import.UnmatchedLines)
+	assert.Equal(t, 1, resp.Import.MismatchedLines)
+	lines := resp.Import.Lines
+	assert.Equal(t, model.StatementLineStatusUnmatched, lines[0].Status)
+	assert.Contains(t, lines[0].Note, "apply it manually")
+	assert.Equal(t, model.StatementLineStatusMismatched, lines[1].Status)
+	assert.Contains(t, lines[1].Note, "exceeds")
+
+	updated, err := billingSvc.GetBilling(context.Background(), billing.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 5500000, updated.Outstanding)
+
+	err = testDB.Model(&model.StatementLine{}).Where("id = ?", lines[0].ID).Updates(map[string]any{"status": model.StatementLineStatusPending, "note": ""}).Error
+	assert.NoError(t, err)
+
+	code, resp = postStatement(t, "statement.csv", content)
+	assert.Equal(t, 200, code)
+	assert.True(t, resp.Duplicate)
+	assert.Equal(t, 1, resp.Import.UnmatchedLines)
+	assert.Equal(t, 1, resp.Import.MismatchedLines)
+	assert.Equal(t, model.StatementLineStatusUnmatched, resp.Import.Lines[0].Status)
+}
+
+func postPaymentBatch(t *testing.T, query, contentType, body string) (int, model.PaymentBatch) {
+	r, _ := http.NewRequest("POST", "/payments/batch"+query, bytes.NewBufferString(body))
+	r.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	var batch model.PaymentBatch
+	json.Unmarshal(w.Body.Bytes(), &batch)
+	return w.Code, batch
+}
+
+func TestIntegration_PaymentBatch(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	id := strconv.FormatUint(uint64(billing.ID), 10)
+	content := "billing_id,week,amount,reference\n" +
+		id + ",1,110000,agent-1\n" +
+		id + ",1,110000,agent-2\n" +
+		id + ",2,110000,agent-1\n" +
+		id + ",2,1000,agent-3\n" +
+		"abc,2,110000,agent-4\n"
+
+	code, batch := postPaymentBatch(t, "?dryRun=true", "text/csv", content)
+	assert.Equal(t, 200, code)
+	assert.True(t, batch.DryRun)
+	assert.Equal(t, model.PaymentBatchStatusCompleted, batch.Status)
+	assert.Equal(t, 1, batch.ValidRows)
+	assert.Equal(t, 1, batch.DuplicateRows)
+	assert.Equal(t, 3, batch.RejectedRows)
+	updated, err := billingSvc.GetBilling(context.Background(), billing.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 5500000, updated.Outstanding)
+
+	code, batch = postPaymentBatch(t, "", "text/csv", content)
+	assert.Equal(t, 200, code)
+	assert.Equal(t, 1, batch.AppliedRows)
+	assert.Len(t, batch.Rows, 5)
+	assert.Equal(t, model.PaymentBatchRowStatusApplied, batch.Rows[0].Status)
+	assert.NotNil(t, batch.Rows[0].PaymentID)
+	assert.Equal(t, model.PaymentBatchRowStatusRejected, batch.Rows[1].Status)
+	assert.Equal(t, "Week 1 has been paid.", batch.Rows[1].Reason)
+	assert.Equal(t, model.PaymentBatchRowStatusDuplicate, batch.Rows[2].Status)
+	assert.Equal(t, model.PaymentBatchRowStatusRejected, batch.Rows[3].Status)
+	assert.Equal(t, model.PaymentBatchRowStatusRejected, batch.Rows[4].Status)
+
+	jsonl := `{"billingId":` + id + `,"amount":110000,"reference":"agent-1"}` + "\n" +
+		`{"billingId":` + id + `,"amount":110000,"reference":"agent-5"}` + "\n"
+	code, batch = postPaymentBatch(t, "?async=true", "application/x-ndjson", jsonl)
+	assert.Equal(t, 202, code)
+	assert.Equal(t, model.PaymentBatchStatusPending, batch.Status)
+
+	processed, err := paymentBatchSvc.ProcessPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	r, _ := http.NewRequest("GET", fmt.Sprintf("/payments/batch/%d", batch.ID), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	json.Unmarshal(w.Body.Bytes(), &batch)
+	assert.Equal(t, model.PaymentBatchStatusCompleted, batch.Status)
+	assert.Equal(t, 1, batch.DuplicateRows)
+	assert.Equal(t, 1, batch.AppliedRows)
+	assert.Equal(t, 2, batch.Rows[1].Week)
+
+	updated, err = billingSvc.GetBilling(context.Background(), billing.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 5280000, updated.Outstanding)
+}
+
+func TestIntegration_PaymentBatch_ClaimsOnce(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	id := strconv.FormatUint(uint64(billing.ID), 10)
+	code, batch := postPaymentBatch(t, "?async=true", "text/csv", "billing_id,week,amount,reference\n"+id+",1,110000,lease-1\n")
+	assert.Equal(t, 202, code)
+
+	leaseExpiresAt := time.Now().Add(time.Minute)
+	err := testDB.Model(&model.PaymentBatch{}).Where("id = ?", batch.ID).Updates(map[string]any{
+		"status":           model.PaymentBatchStatusRunning,
+		"lease_expires_at": leaseExpiresAt,
+	}).Error
+	assert.NoError(t, err)
+
+	processed, err := paymentBatchSvc.ProcessPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, processed)
+
+	err = testDB.Model(&model.PaymentBatch{}).Where("id = ?", batch.ID).Update("lease_expires_at", time.Now().Add(-time.Second)).Error
+	assert.NoError(t, err)
+
+	processed, err = paymentBatchSvc.ProcessPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	completed, err := paymentBatchSvc.GetBatch(context.Background(), batch.ID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, model.PaymentBatchStatusCompleted, completed.Status)
+	assert.Equal(t, 1, completed.AppliedRows)
+}
+
+func TestIntegration_LoanImport(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	existing := createTestBilling(t)
+	startDate := time.Now().AddDate(0, 0, -21)
+	rows := []dto.ImportBillingRequest{
+		{
+			CreateBillingDTO: dto.CreateBillingDTO{CustomerID: 7, LoanID: 2001, LoanAmount: 400000, LoanInterest: 10, LoanWeeks: 4},
+			StartDate:        startDate,
+			Payments: []dto.ImportPayment{
+				{Week: 1, Amount: 110000, PaidDate: startDate.AddDate(0, 0, 7)},
+				{Week: 2, Amount: 110000, PaidDate: startDate.AddDate(0, 0, 14)},
+			},
+		},
+		{
+			CreateBillingDTO: dto.CreateBillingDTO{CustomerID: 8, LoanID: 2002, LoanAmount: 400000, LoanInterest: 10, LoanWeeks: 2},
+			StartDate:        startDate,
+			Installments: []dto.ImportInstallment{
+				{Week: 1, Amount: 100000, DueDate: startDate.AddDate(0, 0, 7)},
+				{Week: 2, Amount: 100000, DueDate: startDate.AddDate(0, 0, 14)},
+			},
+		},
+		{
+			CreateBillingDTO: dto.CreateBillingDTO{CustomerID: 1, LoanID: existing.LoanID, LoanAmount: 5000000, LoanInterest: 10, LoanWeeks: 50},
+			StartDate:        startDate,
+		},
+	}
+	var body bytes.Buffer
+	for _, row := range rows {
+		line, _ := json.Marshal(row)
+		body.Write(line)
+		body.WriteString("\n")
+	}
+	body.WriteString("not json\n")
+	content := body.String()
+
+	r, _ := http.NewRequest("POST", "/loan-imports", bytes.NewBufferString(content))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 202, w.Code)
+	var loanImport model.LoanImport
+	json.Unmarshal(w.Body.Bytes(), &loanImport)
+	assert.Equal(t, model.LoanImportStatusPending, loanImport.Status)
+	assert.Equal(t, 4, loanImport.TotalRows)
+
+	processed, err := loanImportSvc.ProcessPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/loan-imports/%d", loanImport.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	json.Unmarshal(w.Body.Bytes(), &loanImport)
+	assert.Equal(t, model.LoanImportStatusCompleted, loanImport.Status)
+	assert.Equal(t, 1, loanImport.ImportedRows)
+	assert.Equal(t, 1, loanImport.SkippedRows)
+	assert.Equal(t, 2, loanImport.FailedRows)
+	assert.Equal(t, "Loan 2002 installments total 200000, expected 440000.", loanImport.Rows[1].Reason)
+	assert.Equal(t, existing.ID, *loanImport.Rows[2].BillingID)
+
+	imported, err := billingSvc.GetBilling(context.Background(), *loanImport.Rows[0].BillingID)
+	assert.NoError(t, err)
+	assert.Equal(t, 220000, imported.Outstanding)
+	assert.True(t, imported.Payments[1].Paid)
+	assert.False(t, imported.Payments[2].Paid)
+	assert.Len(t, imported.VirtualAccounts, 2)
+
+	r, _ = http.NewRequest("POST", "/loan-imports", bytes.NewBufferString(content))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 202, w.Code)
+	_, err = loanImportSvc.ProcessPending(context.Background())
+	assert.NoError(t, err)
+	var rerun model.LoanImport
+	json.Unmarshal(w.Body.Bytes(), &rerun)
+	rerunReport, err := loanImportSvc.GetImport(context.Background(), rerun.ID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, rerunReport.ImportedRows)
+	assert.Equal(t, 2, rerunReport.SkippedRows)
+
+	r, _ = http.NewRequest("POST", fmt.Sprintf("/loan-imports/%d/retry", loanImport.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 202, w.Code)
+	json.Unmarshal(w.Body.Bytes(), &loanImport)
+	assert.Equal(t, model.LoanImportStatusPending, loanImport.Status)
+	assert.Equal(t, 2, loanImport.PendingRows)
+}
+
+type recordingChannel struct {
+	messages []notification.Message
+}
+
+func (c *recordingChannel) Send(ctx context.Context, msg notification.Message) (string, error) {
+	c.messages = append(c.messages, msg)
+	return fmt.Sprintf("recorded-%d", len(c.messages)), nil
+}
+
+func TestIntegration_PaymentReminders(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	body, _ := json.Marshal(dto.CustomerRequest{Name: "Budi", Phone: "081234567890", SMSOptIn: true})
+	r, _ := http.NewRequest("PUT", fmt.Sprintf("/customers/%d", billing.CustomerID), bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	year, month, day := billing.Payments[0].DueDate.In(jakarta).Date()
+	upcoming := time.Date(year, month, day-2, 9, 1, 0, 0, jakarta)
+
+	created, err := reminderSvc.ScheduleReminders(t.Context(), upcoming)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created)
+	created, err = reminderSvc.ScheduleReminders(t.Context(), upcoming)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, created)
+
+	sent, failed, err := reminderSvc.DispatchDue(t.Context(), upcoming)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, 0, failed)
+	assert.Len(t, reminderChannel.messages, 1)
+	assert.Equal(t, "+6281234567890", reminderChannel.messages[0].To)
+	assert.Contains(t, reminderChannel.messages[0].Body, "Rp110.000")
+
+	due := time.Date(year, month, day, 9, 1, 0, 0, jakarta)
+	created, err = reminderSvc.ScheduleReminders(t.Context(), due)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, created)
+	_, err = paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 1, Amount: 110000})
+	assert.NoError(t, err)
+	sent, _, err = reminderSvc.DispatchDue(t.Context(), due)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/billings/%d/reminders", billing.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var reminders []model.Reminder
+	json.Unmarshal(w.Body.Bytes(), &reminders)
+	assert.Len(t, reminders, 3)
+	assert.Equal(t, model.ReminderStatusSent, reminders[0].Status)
+	assert.Equal(t, -2, reminders[0].DayOffset)
+	assert.Equal(t, "recorded-1", reminders[0].ProviderRef)
+	assert.Equal(t, model.ReminderStatusCancelled, reminders[1].Status)
+	assert.Equal(t, "Installment is paid.", reminders[1].LastError)
+	assert.Equal(t, model.ReminderStatusScheduled, reminders[2].Status)
+	assert.Equal(t, 1, reminders[2].DayOffset)
+}
+
+func TestIntegration_Dunning(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	body, _ := json.Marshal(dto.DunningStrategyRequest{Code: "PAYLATER", Steps: []dto.DunningStepRequest{{DaysPastDue: 3, Action: "email"}}})
+	r, _ := http.NewRequest("POST", "/dunning-strategies", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 201, w.Code)
+
+	r, _ = http.NewRequest("GET", "/dunning-strategies", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var strategies []model.DunningStrategy
+	json.Unmarshal(w.Body.Bytes(), &strategies)
+	assert.Len(t, strategies, 2)
+	assert.Equal(t, model.DefaultDunningStrategyCode, strategies[0].Code)
+	assert.Equal(t, model.DunningActionEmail, strategies[1].Steps[0].Action)
+
+	billing := createTestBilling(t)
+	body, _ = json.Marshal(dto.CustomerRequest{Name: "Budi", Phone: "081234567890", SMSOptIn: true})
+	r, _ = http.NewRequest("PUT", fmt.Sprintf("/customers/%d", billing.CustomerID), bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	dueDate := billing.Payments[0].DueDate
+	processed, failed, err := dunningSvc.RunDunning(t.Context(), dueDate.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Equal(t, 0, failed)
+	assert.Len(t, reminderChannel.messages, 1)
+	assert.Equal(t, "+6281234567890", reminderChannel.messages[0].To)
+
+	_, _, err = dunningSvc.RunDunning(t.Context(), dueDate.Add(2*time.Hour))
+	assert.NoError(t, err)
+	_, _, err = dunningSvc.RunDunning(t.Context(), dueDate.AddDate(0, 0, 7).Add(time.Hour))
+	assert.NoError(t, err)
+
+	for week := 1; week <= 2; week++ {
+		_, err = paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: week, Amount: 110000})
+		assert.NoError(t, err)
+	}
+	_, _, err = dunningSvc.RunDunning(t.Context(), dueDate.AddDate(0, 0, 7).Add(2*time.Hour))
+	assert.NoError(t, err)
+
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/billings/%d/dunning-log", billing.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var logs []model.DunningLog
+	json.Unmarshal(w.Body.Bytes(), &logs)
+	assert.Len(t, logs, 3)
+	assert.Equal(t, model.DunningActionSMS, logs[0].Action)
+	assert.Equal(t, model.DunningStatusSent, logs[0].Status)
+	assert.Equal(t, model.DunningActionCall, logs[1].Action)
+	assert.Equal(t, model.DunningStatusCreated, logs[1].Status)
+	assert.Equal(t, "+6281234567890", logs[1].Recipient)
+	assert.Equal(t, model.DunningStatusStopped, logs[2].Status)
+	assert.Equal(t, "Billing is cured.", logs[2].Reason)
+	assert.Equal(t, 1, logs[2].Cycle)
+}
+
+func TestIntegration_CollectionCases(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	var agents []model.CollectionAgent
+	for _, req := range []dto.CollectionAgentRequest{{Name: "Sari", Region: "jakarta"}, {Name: "Andi"}} {
+		body, _ := json.Marshal(req)
+		r, _ := http.NewRequest("POST", "/collection-agents", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, 201, w.Code)
+		var agent model.CollectionAgent
+		json.Unmarshal(w.Body.Bytes(), &agent)
+		agents = append(agents, agent)
+	}
+	assert.Equal(t, "JAKARTA", agents[0].Region)
+	assert.True(t, agents[1].Active)
+
+	body, _ := json.Marshal(dto.CustomerRequest{Name: "Budi", Region: "Jakarta"})
+	r, _ := http.NewRequest("PUT", "/customers/1", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+
+	billing := createTestBilling(t)
+	backdateTestBilling(t, billing.ID, 3)
+	other, err := billingSvc.CreateBilling(t.Context(), dto.CreateBillingRequest{
+		CreateBillingDTO: dto.CreateBillingDTO{CustomerID: 2, LoanID: 2, LoanAmount: 5000000, LoanInterest: 10, LoanWeeks: 50},
+	})
+	assert.NoError(t, err)
+	backdateTestBilling(t, other.ID, 3)
+	_, _, err = sweepSvc.RunEndOfDaySweep(t.Context())
+	assert.NoError(t, err)
+	processed, failed, err := collectionSvc.SyncCases(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, processed)
+	assert.Equal(t, 0, failed)
+
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/collection-agents/%d/queue", agents[0].ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var queue []dto.CollectionQueueItem
+	json.Unmarshal(w.Body.Bytes(), &queue)
+	assert.Len(t, queue, 1)
+	assert.Equal(t, billing.ID, queue[0].BillingID)
+	assert.Equal(t, "JAKARTA", queue[0].Region)
+	assert.Equal(t, 2*110000, queue[0].OverdueAmount)
+	assert.GreaterOrEqual(t, queue[0].DaysPastDue, 7)
+	assert.Equal(t, queue[0].DaysPastDue+2, queue[0].PriorityScore)
+	caseID := queue[0].CaseID
+
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/collection-agents/%d/queue", agents[1].ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	json.Unmarshal(w.Body.Bytes(), &queue)
+	assert.Len(t, queue, 1)
+	assert.Equal(t, other.ID, queue[0].BillingID)
+
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/collection-agents/%d/queue?offset=1", agents[1].ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	queue = nil
+	json.Unmarshal(w.Body.Bytes(), &queue)
+	assert.Empty(t, queue)
+
+	body, _ = json.Marshal(dto.ContactAttemptRequest{Channel: "call", Outcome: "promised_to_pay", Notes: "Will pay on Friday"})
+	r, _ = http.NewRequest("POST", fmt.Sprintf("/collection-cases/%d/contact-attempts", caseID), bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 201, w.Code)
+	var attempt model.ContactAttempt
+	json.Unmarshal(w.Body.Bytes(), &attempt)
+	assert.Equal(t, agents[0].ID, *attempt.AgentID)
+
+	for week := 1; week <= 2; week++ {
+		_, err = paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: week, Amount: 110000})
+		assert.NoError(t, err)
+	}
+	relay := outbox.NewRelay(outboxRepo, collection.NewPublisher(collectionSvc), lock.NewAdvisoryLocker(testDB), &config.OutboxConfig{BatchSize: 100})
+	_, failed, err = relay.RelayBatch(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, failed)
+
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/collection-cases/%d", caseID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var collectionCase model.CollectionCase
+	json.Unmarshal(w.Body.Bytes(), &collectionCase)
+	assert.Equal(t, model.CollectionCaseStatusClosed, collectionCase.Status)
+	assert.Equal(t, "Billing is cured.", collectionCase.CloseReason)
+	assert.Equal(t, model.ContactOutcomePromisedToPay, collectionCase.LastOutcome)
+	assert.Len(t, collectionCase.Attempts, 1)
+
+	body, _ = json.Marshal(dto.ContactAttemptRequest{Channel: "CALL", Outcome: "NO_ANSWER"})
+	r, _ = http.NewRequest("POST", fmt.Sprintf("/collection-cases/%d/contact-attempts", caseID), bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "is closed.")
+
+	backdateTestBilling(t, other.ID, 13)
+	_, err = writeOffSvc.WriteOff(t.Context(), other.ID, dto.WriteOffRequest{Reason: "90+ days past due"})
+	assert.NoError(t, err)
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/billings/%d/collection-cases", other.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var cases []model.CollectionCase
+	json.Unmarshal(w.Body.Bytes(), &cases)
+	assert.Len(t, cases, 1)
+	assert.Equal(t, model.CollectionCaseStatusClosed, cases[0].Status)
+	assert.Equal(t, "Billing is written off.", cases[0].CloseReason)
+}
+
+func TestIntegration_PaymentPromises(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	backdateTestBilling(t, billing.ID, 3)
+
+	body, _ := json.Marshal(dto.PaymentPromiseRequest{Amount: 220000, PromiseDate: time.Now().AddDate(0, 0, -1).Format(time.DateOnly)})
+	r, _ := http.NewRequest("POST", fmt.Sprintf("/billings/%d/payment-promises", billing.ID), bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "Promise date cannot be in the past.")
+
+	body, _ = json.Marshal(dto.PaymentPromiseRequest{Amount: 220000, PromiseDate: time.Now().AddDate(0, 0, 2).Format(time.DateOnly), Notes: "Salary on Friday"})
+	r, _ = http.NewRequest("POST", fmt.Sprintf("/billings/%d/payment-promises", billing.ID), bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 201, w.Code)
+	var promise model.PaymentPromise
+	json.Unmarshal(w.Body.Bytes(), &promise)
+	assert.Equal(t, model.PaymentPromiseStatusPending, promise.Status)
+
+	_, _, err := sweepSvc.RunEndOfDaySweep(t.Context())
+	assert.NoError(t, err)
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/billings/%d/delinquent", billing.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var delinquent dto.DelinquentResponse
+	json.Unmarshal(w.Body.Bytes(), &delinquent)
+	assert.False(t, delinquent.IsDelinquent)
+	assert.True(t, delinquent.Promised)
+	swept, err := billingSvc.GetBilling(t.Context(), billing.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.DelinquencyStatusCurrent, swept.DelinquencyStatus)
+
+	processed, _, err := dunningSvc.RunDunning(t.Context(), time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	logs, err := dunningSvc.GetBillingLog(t.Context(), billing.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, logs)
+
+	paymentResp, err := paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 1, Amount: 110000})
+	assert.NoError(t, err)
+	assert.Equal(t, 110000, paymentResp.Promise.PaidAmount)
+	assert.Equal(t, model.PaymentPromiseStatusPending, paymentResp.Promise.Status)
+	paymentResp, err = paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 2, Amount: 110000})
+	assert.NoError(t, err)
+	assert.Equal(t, model.PaymentPromiseStatusFulfilled, paymentResp.Promise.Status)
+
+	body, _ = json.Marshal(dto.PaymentPromiseRequest{Amount: 110000, PromiseDate: time.Now().Format(time.DateOnly)})
+	r, _ = http.NewRequest("POST", fmt.Sprintf("/billings/%d/payment-promises", billing.ID), bytes.NewBuffer(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 201, w.Code)
+	json.Unmarshal(w.Body.Bytes(), &promise)
+	err = testDB.Model(&model.PaymentPromise{}).Where("id = ?", promise.ID).Update("expires_at", time.Now().Add(-time.Hour)).Error
+	assert.NoError(t, err)
+	_, _, err = sweepSvc.RunEndOfDaySweep(t.Context())
+	assert.NoError(t, err)
+
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/billings/%d/payment-promises", billing.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	var promises []model.PaymentPromise
+	json.Unmarshal(w.Body.Bytes(), &promises)
+	assert.Len(t, promises, 2)
+	assert.Equal(t, model.PaymentPromiseStatusFulfilled, promises[0].Status)
+	assert.Equal(t, 220000, promises[0].PaidAmount)
+	assert.Equal(t, model.PaymentPromiseStatusBroken, promises[1].Status)
+	assert.NotNil(t, promises[1].ResolvedAt)
+}
+
+func TestIntegration_LoanStatement(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	paymentResp, err := paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 1, Amount: 110000})
+	assert.NoError(t, err)
+
+	r, _ := http.NewRequest("GET", fmt.Sprintf("/billings/%d/statement?format=csv", billing.ID), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, "LOAN", rows[2][1])
+	assert.Equal(t, "5500000", rows[2][5])
+	assert.Equal(t, "PAYMENT", rows[3][1])
+	assert.Equal(t, "CLOSING_BALANCE", rows[len(rows)-1][1])
+	assert.Equal(t, strconv.Itoa(paymentResp.Outstanding), rows[len(rows)-1][7])
+
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/billings/%d/statement?format=html", billing.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "Loan Statement")
+	assert.Contains(t, w.Body.String(), "Rp5.390.000")
+
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/billings/%d/statement", billing.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "%PDF-"))
+
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/billings/%d/statement?format=xlsx", billing.ID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestIntegration_RepaymentCalendar(t *testing.T) {
+	teardown := setupTest(t)
+	defer teardown()
+
+	billing := createTestBilling(t)
+	_, err := paymentSvc.MakePayment(t.Context(), billing.ID, dto.PaymentRequest{Week: 1, Amount: 110000})
+	assert.NoError(t, err)
+
+	r, _ := http.NewRequest("GET", fmt.Sprintf("/billings/%d/schedule.ics", billing.ID), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\n"))
+	assert.Equal(t, billing.LoanWeeks, strings.Count(body, "BEGIN:VEVENT"))
+	assert.Equal(t, 1, strings.Count(body, "STATUS:CANCELLED"))
+	assert.Equal(t, billing.LoanWeeks-1, strings.Count(body, "STATUS:CONFIRMED"))
+	assert.Contains(t, body, fmt.Sprintf("UID:payment-%d@billing-engine\r\n", billing.Payments[0].ID))
+	assert.Equal(t, 1, strings.Count(body, "SEQUENCE:1\r\n"))
+	assert.Equal(t, billing.LoanWeeks-1, strings.Count(body, "SEQUENCE:0\r\n"))
+
+	r, _ = http.NewRequest("GET", fmt.Sprintf("/customers/%d/schedule.ics", billing.CustomerID), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, billing.LoanWeeks, strings.Count(w.Body.String(), "BEGIN:VEVENT"))
+
+	r, _ = http.NewRequest("GET", "/customers/999999/schedule.ics", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, 400, w.Code)
+}